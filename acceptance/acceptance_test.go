@@ -25,6 +25,7 @@ import (
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/client"
 	"github.com/docker/go-connections/nat"
+	"github.com/google/go-containerregistry/pkg/authn"
 	"github.com/google/go-containerregistry/pkg/name"
 	"github.com/pkg/errors"
 	"github.com/sclevine/spec"
@@ -221,7 +222,7 @@ func testAcceptance(t *testing.T, when spec.G, it spec.S, builder, runImageMirro
 			dockerCli.ImageRemove(context.TODO(), repoName, dockertypes.ImageRemoveOptions{Force: true, PruneChildren: true})
 			ref, err := name.ParseReference(repoName, name.WeakValidation)
 			h.AssertNil(t, err)
-			cacheImage := cache.NewImageCache(ref, dockerCli)
+			cacheImage := cache.NewImageCache(ref, dockerCli, authn.DefaultKeychain)
 			buildCacheVolume := cache.NewVolumeCache(ref, "build", dockerCli)
 			launchCacheVolume := cache.NewVolumeCache(ref, "launch", dockerCli)
 			cacheImage.Clear(context.TODO())
@@ -637,7 +638,7 @@ func testAcceptance(t *testing.T, when spec.G, it spec.S, builder, runImageMirro
 
 				h.DockerRmi(dockerCli, repoName)
 
-				cache.NewImageCache(ref, dockerCli).Clear(context.TODO())
+				cache.NewImageCache(ref, dockerCli, authn.DefaultKeychain).Clear(context.TODO())
 				cache.NewVolumeCache(ref, "build", dockerCli).Clear(context.TODO())
 				cache.NewVolumeCache(ref, "launch", dockerCli).Clear(context.TODO())
 			})
@@ -734,6 +735,41 @@ func testAcceptance(t *testing.T, when spec.G, it spec.S, builder, runImageMirro
 					h.AssertContains(t, output, fmt.Sprintf("Successfully rebased image '%s'", repoName))
 					assertMockAppRunsWithOutput(t, repoName, "contents-after-1", "contents-after-2")
 				})
+
+				when("--pull-policy", func() {
+					it("uses the local images when never", func() {
+						cmd := packCmd("rebase", repoName, "--pull-policy", "never", "--run-image", runAfter)
+						output := h.Run(t, cmd)
+
+						h.AssertContains(t, output, fmt.Sprintf("Successfully rebased image '%s'", repoName))
+						assertMockAppRunsWithOutput(t, repoName, "contents-after-1", "contents-after-2")
+					})
+
+					it("uses the local images when if-not-present", func() {
+						cmd := packCmd("rebase", repoName, "--pull-policy", "if-not-present", "--run-image", runAfter)
+						output := h.Run(t, cmd)
+
+						h.AssertContains(t, output, fmt.Sprintf("Successfully rebased image '%s'", repoName))
+						assertMockAppRunsWithOutput(t, repoName, "contents-after-1", "contents-after-2")
+					})
+
+					it("pulls the images when always", func() {
+						cmd := packCmd("rebase", repoName, "--pull-policy", "always", "--run-image", runAfter)
+						_, err := h.RunE(cmd)
+
+						// the images were only created on the daemon, so pulling them fails
+						h.AssertNotNil(t, err)
+						h.AssertEq(t, h.ImageID(t, repoName), origID)
+					})
+
+					it("rejects an unknown policy", func() {
+						cmd := packCmd("rebase", repoName, "--pull-policy", "sometimes", "--run-image", runAfter)
+						output, err := h.RunE(cmd)
+
+						h.AssertNotNil(t, err)
+						h.AssertContains(t, output, "invalid pull policy 'sometimes'")
+					})
+				})
 			})
 
 			when("local config has a mirror", func() {
@@ -1080,7 +1116,7 @@ func createStack(t *testing.T, dockerCli *client.Client, runImageMirror string)
 
 func createStackImage(t *testing.T, dockerCli *client.Client, repoName string, dir string) {
 	ctx := context.Background()
-	buildContext := archive.ReadDirAsTar(dir, "/", 0, 0, -1)
+	buildContext := archive.ReadDirAsTar(dir, "/", 0, 0, -1, nil)
 
 	res, err := dockerCli.ImageBuild(ctx, buildContext, dockertypes.ImageBuildOptions{
 		Tags:        []string{repoName},
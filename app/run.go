@@ -3,6 +3,7 @@ package app
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -16,36 +17,60 @@ import (
 	"github.com/buildpack/pack/logging"
 )
 
-func (i *Image) Run(ctx context.Context, docker *client.Client, ports []string) error {
+// processTypeEnv is read by the launcher to select the process type to run
+const processTypeEnv = "PACK_PROCESS_TYPE"
+
+type RunOptions struct {
+	Ports       []string // defaults to the ports exposed by the image
+	Env         map[string]string
+	Binds       []string
+	Name        string // container name, generated by docker when empty
+	Detach      bool   // start the container and return without waiting for it to exit
+	ProcessType string // process type from the app's launch metadata, defaults to the launcher's default
+}
+
+// Run runs the image in a container that is removed when it exits, and returns the ID of the container
+func (i *Image) Run(ctx context.Context, docker *client.Client, opts RunOptions) (string, error) {
+	ports := opts.Ports
 	if ports == nil {
 		var err error
 		ports, err = exposedPorts(ctx, docker, i.RepoName)
 		if err != nil {
-			return err
+			return "", err
 		}
 	}
 
 	parsedPorts, portBindings, err := parsePorts(ports)
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	ctr, err := docker.ContainerCreate(ctx, &dcontainer.Config{
 		Image:        i.RepoName,
-		AttachStdout: true,
-		AttachStderr: true,
+		AttachStdout: !opts.Detach,
+		AttachStderr: !opts.Detach,
+		Env:          containerEnv(opts.Env, opts.ProcessType),
 		ExposedPorts: parsedPorts,
 		Labels:       map[string]string{"author": "pack"},
 	}, &dcontainer.HostConfig{
 		AutoRemove:   true,
 		PortBindings: portBindings,
-	}, nil, "")
+		Binds:        opts.Binds,
+	}, nil, opts.Name)
 	if err != nil {
-		return err
+		return "", err
 	}
-	defer docker.ContainerRemove(context.Background(), ctr.ID, types.ContainerRemoveOptions{Force: true})
 
 	logContainerListening(i.Logger, portBindings)
+	if opts.Detach {
+		if err := docker.ContainerStart(ctx, ctr.ID, types.ContainerStartOptions{}); err != nil {
+			docker.ContainerRemove(context.Background(), ctr.ID, types.ContainerRemoveOptions{Force: true})
+			return "", errors.Wrap(err, "container start")
+		}
+		return ctr.ID, nil
+	}
+
+	defer docker.ContainerRemove(context.Background(), ctr.ID, types.ContainerRemoveOptions{Force: true})
 	if err = container.Run(
 		ctx,
 		docker,
@@ -53,10 +78,26 @@ func (i *Image) Run(ctx context.Context, docker *client.Client, ports []string)
 		logging.GetDebugWriter(i.Logger),
 		logging.GetDebugErrorWriter(i.Logger),
 	); err != nil {
-		return errors.Wrap(err, "run container")
+		return ctr.ID, errors.Wrap(err, "run container")
 	}
 
-	return nil
+	return ctr.ID, nil
+}
+
+// containerEnv returns the env in a stable order, with the process type overriding any set in the env
+func containerEnv(env map[string]string, processType string) []string {
+	var out []string
+	for key, value := range env {
+		if processType != "" && key == processTypeEnv {
+			continue
+		}
+		out = append(out, fmt.Sprintf("%s=%s", key, value))
+	}
+	if processType != "" {
+		out = append(out, fmt.Sprintf("%s=%s", processTypeEnv, processType))
+	}
+	sort.Strings(out)
+	return out
 }
 
 func exposedPorts(ctx context.Context, docker *client.Client, imageID string) ([]string, error) {
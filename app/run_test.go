@@ -7,10 +7,12 @@ import (
 	"io/ioutil"
 	"math/rand"
 	"net/http"
+	"os"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/client"
 	"github.com/fatih/color"
 	"github.com/sclevine/spec"
@@ -184,6 +186,39 @@ func testApp(t *testing.T, when spec.G, it spec.S) {
 					})
 			})
 		})
+
+		when("detached", func() {
+			it.Before(func() {
+				h.CreateImageOnLocal(t, docker, repo, "FROM hashicorp/http-echo\nCMD [\"-text=hello world\"]")
+			})
+
+			it("starts the container with the runtime options and returns its ID", func() {
+				name := "pack-run-" + h.RandString(10)
+				hostDir, err := ioutil.TempDir("", "pack.app.run")
+				h.AssertNil(t, err)
+				defer os.RemoveAll(hostDir)
+
+				id, err := subject.Run(context.TODO(), docker, app.RunOptions{
+					Ports:       []string{},
+					Env:         map[string]string{"SOME_VAR": "some-value", "PACK_PROCESS_TYPE": "web"},
+					Binds:       []string{hostDir + ":/some-dir:ro"},
+					Name:        name,
+					Detach:      true,
+					ProcessType: "worker",
+				})
+				h.AssertNil(t, err)
+				defer docker.ContainerRemove(context.TODO(), id, types.ContainerRemoveOptions{Force: true})
+
+				inspect, err := docker.ContainerInspect(context.TODO(), id)
+				h.AssertNil(t, err)
+				h.AssertEq(t, inspect.Name, "/"+name)
+				h.AssertEq(t, inspect.State.Running, true)
+				h.AssertContains(t, strings.Join(inspect.Config.Env, "\n"), "SOME_VAR=some-value")
+				h.AssertContains(t, strings.Join(inspect.Config.Env, "\n"), "PACK_PROCESS_TYPE=worker")
+				h.AssertNotContains(t, strings.Join(inspect.Config.Env, "\n"), "PACK_PROCESS_TYPE=web")
+				h.AssertEq(t, inspect.HostConfig.Binds, []string{hostDir + ":/some-dir:ro"})
+			})
+		})
 	})
 }
 
@@ -192,7 +227,8 @@ func assertOnRunningContainer(t *testing.T, subject *app.Image, port []string, e
 
 	done := make(chan error)
 	go func() {
-		done <- subject.Run(ctx, docker, port)
+		_, err := subject.Run(ctx, docker, app.RunOptions{Ports: port})
+		done <- err
 	}()
 
 	ticker := time.NewTicker(time.Second)
@@ -12,6 +12,7 @@ import (
 
 	"github.com/pkg/errors"
 
+	"github.com/buildpack/pack/image"
 	"github.com/buildpack/pack/internal/paths"
 	"github.com/buildpack/pack/logging"
 	"github.com/buildpack/pack/style"
@@ -25,17 +26,28 @@ const (
 type downloader struct {
 	logger       logging.Logger
 	baseCacheDir string
+	imageFetcher ImageFetcher
 }
 
-func NewDownloader(logger logging.Logger, baseCacheDir string) *downloader {
+// NewDownloader returns a downloader caching in baseCacheDir. The imageFetcher may be nil, in which case docker://
+// URIs are not supported.
+func NewDownloader(logger logging.Logger, baseCacheDir string, imageFetcher ImageFetcher) *downloader {
 	return &downloader{
 		logger:       logger,
 		baseCacheDir: baseCacheDir,
+		imageFetcher: imageFetcher,
 	}
 }
 
-func (d *downloader) Download(pathOrUri string) (Blob, error) {
-	if paths.IsURI(pathOrUri) {
+func (d *downloader) Download(pathOrUri string, pullPolicy image.PullPolicy) (Blob, error) {
+	if IsImageURI(pathOrUri) {
+		path, err := d.handleImage(pathOrUri, pullPolicy)
+		if err != nil {
+			return nil, err
+		}
+
+		return &blob{path: path}, nil
+	} else if paths.IsURI(pathOrUri) {
 		parsedUrl, err := url.Parse(pathOrUri)
 		if err != nil {
 			return nil, errors.Wrapf(err, "parsing path/uri %s", style.Symbol(pathOrUri))
@@ -141,11 +153,13 @@ func (d *downloader) downloadAsStream(uri string, etag string) (io.ReadCloser, s
 
 	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
 		d.logger.Debugf("Downloading from %s", style.Symbol(uri))
+		logging.LogEvent(d.logger, logging.Event{Type: logging.EventDownload, URI: uri, Action: "download"})
 		return resp.Body, resp.Header.Get("Etag"), nil
 	}
 
 	if resp.StatusCode == 304 {
 		d.logger.Debugf("Using cached version of %s", style.Symbol(uri))
+		logging.LogEvent(d.logger, logging.Event{Type: logging.EventDownload, URI: uri, Action: "cached"})
 		return nil, etag, nil
 	}
 
@@ -1,19 +1,26 @@
 package blob_test
 
 import (
+	"archive/tar"
+	"crypto/sha256"
+	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"os"
 	"path/filepath"
 	"testing"
 
+	"github.com/buildpack/imgutil/fakes"
 	"github.com/onsi/gomega/ghttp"
 	"github.com/sclevine/spec"
 	"github.com/sclevine/spec/report"
 
 	"github.com/buildpack/pack"
 	"github.com/buildpack/pack/blob"
+	"github.com/buildpack/pack/image"
 	"github.com/buildpack/pack/internal/archive"
+	ifakes "github.com/buildpack/pack/internal/fakes"
 	"github.com/buildpack/pack/internal/paths"
 	"github.com/buildpack/pack/logging"
 	h "github.com/buildpack/pack/testhelpers"
@@ -26,15 +33,17 @@ func TestDownloader(t *testing.T) {
 func testDownloader(t *testing.T, when spec.G, it spec.S) {
 	when("#Download", func() {
 		var (
-			cacheDir string
-			err      error
-			subject  pack.Downloader
+			cacheDir         string
+			err              error
+			subject          pack.Downloader
+			fakeImageFetcher *ifakes.FakeImageFetcher
 		)
 
 		it.Before(func() {
+			fakeImageFetcher = ifakes.NewFakeImageFetcher()
 			cacheDir, err = ioutil.TempDir("", "cache")
 			h.AssertNil(t, err)
-			subject = blob.NewDownloader(logging.New(ioutil.Discard), cacheDir)
+			subject = blob.NewDownloader(logging.New(ioutil.Discard), cacheDir, fakeImageFetcher)
 		})
 
 		it.After(func() {
@@ -55,7 +64,7 @@ func testDownloader(t *testing.T, when spec.G, it spec.S) {
 					absPath, err := filepath.Abs(relPath)
 					h.AssertNil(t, err)
 
-					b, err := subject.Download(absPath)
+					b, err := subject.Download(absPath, image.PullAlways)
 					h.AssertNil(t, err)
 					assertBlob(t, b)
 				})
@@ -63,7 +72,7 @@ func testDownloader(t *testing.T, when spec.G, it spec.S) {
 
 			when("is relative", func() {
 				it("resolves the absolute path", func() {
-					b, err := subject.Download(relPath)
+					b, err := subject.Download(relPath, image.PullAlways)
 					h.AssertNil(t, err)
 					assertBlob(t, b)
 				})
@@ -77,7 +86,7 @@ func testDownloader(t *testing.T, when spec.G, it spec.S) {
 					uri, err := paths.FilePathToUri(absPath)
 					h.AssertNil(t, err)
 
-					b, err := subject.Download(uri)
+					b, err := subject.Download(uri, image.PullAlways)
 					h.AssertNil(t, err)
 					assertBlob(t, b)
 				})
@@ -116,17 +125,17 @@ func testDownloader(t *testing.T, when spec.G, it spec.S) {
 				})
 
 				it("downloads from a 'http(s)://' URI", func() {
-					b, err := subject.Download(uri)
+					b, err := subject.Download(uri, image.PullAlways)
 					h.AssertNil(t, err)
 					assertBlob(t, b)
 				})
 
 				it("uses cache from a 'http(s)://' URI tgz", func() {
-					b, err := subject.Download(uri)
+					b, err := subject.Download(uri, image.PullAlways)
 					h.AssertNil(t, err)
 					assertBlob(t, b)
 
-					b, err = subject.Download(uri)
+					b, err = subject.Download(uri, image.PullAlways)
 					h.AssertNil(t, err)
 					assertBlob(t, b)
 				})
@@ -141,7 +150,7 @@ func testDownloader(t *testing.T, when spec.G, it spec.S) {
 					})
 
 					it("should return error", func() {
-						_, err := subject.Download(uri)
+						_, err := subject.Download(uri, image.PullAlways)
 						h.AssertError(t, err, "could not download")
 						h.AssertError(t, err, "http status '404'")
 					})
@@ -149,15 +158,175 @@ func testDownloader(t *testing.T, when spec.G, it spec.S) {
 
 				when("uri is unsupported", func() {
 					it("should return error", func() {
-						_, err := subject.Download("not-supported://file.tgz")
+						_, err := subject.Download("not-supported://file.tgz", image.PullAlways)
 						h.AssertError(t, err, "unsupported protocol 'not-supported'")
 					})
 				})
 			})
 		})
+
+		when("is docker:// uri", func() {
+			var (
+				bpImage   *fakes.Image
+				layersDir string
+			)
+
+			addLayer := func(files map[string]string) {
+				t.Helper()
+				layer := filepath.Join(layersDir, fmt.Sprintf("layer-%d.tar", len(fakeImageFetcher.ImageLayers["some/bp"])))
+				h.AssertNil(t, writeTar(layer, files))
+				h.AssertNil(t, bpImage.AddLayer(layer))
+
+				fh, err := os.Open(layer)
+				h.AssertNil(t, err)
+				defer fh.Close()
+				hasher := sha256.New()
+				_, err = io.Copy(hasher, fh)
+				h.AssertNil(t, err)
+				fakeImageFetcher.ImageLayers["some/bp"] = append(fakeImageFetcher.ImageLayers["some/bp"], fmt.Sprintf("sha256:%x", hasher.Sum(nil)))
+			}
+
+			it.Before(func() {
+				layersDir, err = ioutil.TempDir("", "layers")
+				h.AssertNil(t, err)
+
+				bpImage = fakes.NewImage("some/bp", "", "")
+				fakeImageFetcher.RemoteImages["some/bp"] = bpImage
+				addLayer(map[string]string{
+					"etc/some-file": "unrelated",
+					"cnb/buildpacks/some-bp/1.0/buildpack.toml": "some-descriptor",
+					"cnb/buildpacks/some-bp/1.0/file.txt":       "old-contents",
+					"cnb/buildpacks/some-bp/1.0/removed.txt":    "removed",
+				})
+				addLayer(map[string]string{
+					"cnb/buildpacks/some-bp/1.0/file.txt":        "contents",
+					"cnb/buildpacks/some-bp/1.0/.wh.removed.txt": "",
+				})
+			})
+
+			it.After(func() {
+				h.AssertNil(t, os.RemoveAll(layersDir))
+				bpImage.Cleanup()
+			})
+
+			it("extracts the buildpack directory from the image layers", func() {
+				b, err := subject.Download("docker://some/bp", image.PullAlways)
+				h.AssertNil(t, err)
+				assertBlob(t, b)
+				h.AssertEq(t, fakeImageFetcher.FetchCalls["some/bp"].Daemon, true)
+
+				r, err := b.Open()
+				h.AssertNil(t, err)
+				defer r.Close()
+				names := tarEntryNames(t, r)
+				h.AssertContains(t, names, "buildpack.toml\n")
+				h.AssertNotContains(t, names, "removed.txt")
+				h.AssertNotContains(t, names, "some-file")
+			})
+
+			it("reads each layer of the image once", func() {
+				countingImage := &layerCountingImage{Image: bpImage, reads: map[string]int{}}
+				fakeImageFetcher.RemoteImages["some/bp"] = countingImage
+
+				_, err := subject.Download("docker://some/bp", image.PullAlways)
+				h.AssertNil(t, err)
+				for _, diffID := range fakeImageFetcher.ImageLayers["some/bp"] {
+					h.AssertEq(t, countingImage.reads[diffID], 1)
+				}
+			})
+
+			it("fetches the image with the pull policy", func() {
+				_, err := subject.Download("docker://some/bp", image.PullIfNotPresent)
+				h.AssertNil(t, err)
+				h.AssertEq(t, fakeImageFetcher.FetchCalls["some/bp"].PullPolicy, image.PullIfNotPresent)
+			})
+
+			it("uses the cached buildpack when the image is unchanged", func() {
+				_, err := subject.Download("docker://some/bp", image.PullAlways)
+				h.AssertNil(t, err)
+				h.AssertNil(t, os.RemoveAll(layersDir))
+
+				b, err := subject.Download("docker://some/bp", image.PullAlways)
+				h.AssertNil(t, err)
+				assertBlob(t, b)
+			})
+
+			it("accepts an image reference with a tag", func() {
+				fakeImageFetcher.RemoteImages["some-bp:1.0"] = bpImage
+				fakeImageFetcher.ImageLayers["some-bp:1.0"] = fakeImageFetcher.ImageLayers["some/bp"]
+
+				b, err := subject.Download("docker://some-bp:1.0", image.PullAlways)
+				h.AssertNil(t, err)
+				assertBlob(t, b)
+				h.AssertEq(t, fakeImageFetcher.FetchCalls["some-bp:1.0"].Daemon, true)
+			})
+
+			it("fails when the image reference is invalid", func() {
+				_, err := subject.Download("docker://some/bp:not a tag", image.PullAlways)
+				h.AssertError(t, err, "invalid image reference in URI 'docker://some/bp:not a tag'")
+			})
+
+			it("fails when the image holds more than one buildpack", func() {
+				addLayer(map[string]string{"cnb/buildpacks/other-bp/1.0/buildpack.toml": "other-descriptor"})
+
+				_, err := subject.Download("docker://some/bp", image.PullAlways)
+				h.AssertError(t, err, "found more than one buildpack.toml in the image")
+			})
+
+			it("fails when the image cannot be fetched", func() {
+				_, err := subject.Download("docker://missing/bp", image.PullAlways)
+				h.AssertError(t, err, "fetching buildpack image 'missing/bp'")
+			})
+
+			it("fails without an image fetcher", func() {
+				subject = blob.NewDownloader(logging.New(ioutil.Discard), cacheDir, nil)
+				_, err := subject.Download("docker://some/bp", image.PullAlways)
+				h.AssertError(t, err, "without an image fetcher")
+			})
+		})
 	})
 }
 
+type layerCountingImage struct {
+	*fakes.Image
+	reads map[string]int
+}
+
+func (i *layerCountingImage) GetLayer(diffID string) (io.ReadCloser, error) {
+	i.reads[diffID]++
+	return i.Image.GetLayer(diffID)
+}
+
+func writeTar(path string, files map[string]string) error {
+	fh, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer fh.Close()
+
+	tw := tar.NewWriter(fh)
+	for name, contents := range files {
+		if err := archive.AddFileToTar(tw, name, contents); err != nil {
+			return err
+		}
+	}
+	return tw.Close()
+}
+
+func tarEntryNames(t *testing.T, r io.Reader) string {
+	t.Helper()
+	var names string
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return names
+		}
+		h.AssertNil(t, err)
+		names += header.Name + "\n"
+	}
+}
+
 func assertBlob(t *testing.T, b blob.Blob) {
 	t.Helper()
 	r, err := b.Open()
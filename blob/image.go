@@ -0,0 +1,296 @@
+package blob
+
+import (
+	"archive/tar"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/buildpack/imgutil"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/pkg/errors"
+
+	"github.com/buildpack/pack/image"
+	"github.com/buildpack/pack/logging"
+	"github.com/buildpack/pack/style"
+)
+
+// ImageScheme is the URI scheme of buildpacks distributed as images, e.g. 'docker://registry.example.com/some/bp:1.0'
+const ImageScheme = "docker"
+
+const (
+	whiteoutPrefix = ".wh."
+	opaqueWhiteout = ".wh..wh..opq"
+)
+
+// IsImageURI reports whether the URI references a buildpack image, i.e. has the docker:// scheme. The rest of such
+// URIs is an image reference, which is not a valid URL when it has a tag, so the URIs must not be parsed as URLs.
+func IsImageURI(uri string) bool {
+	return strings.HasPrefix(uri, ImageScheme+"://")
+}
+
+// ParseImageURI returns the image reference of a docker:// URI
+func ParseImageURI(uri string) (name.Reference, error) {
+	ref := strings.TrimPrefix(uri, ImageScheme+"://")
+	if ref == "" {
+		return nil, fmt.Errorf("URI %s is missing an image reference", style.Symbol(uri))
+	}
+	parsed, err := name.ParseReference(ref, name.WeakValidation)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid image reference in URI %s", style.Symbol(uri))
+	}
+	return parsed, nil
+}
+
+// ImageFetcher pulls the images of buildpacks referenced by docker:// URIs
+type ImageFetcher interface {
+	Fetch(ctx context.Context, name string, daemon bool, pullPolicy image.PullPolicy) (imgutil.Image, error)
+	Layers(ctx context.Context, name string, daemon bool) ([]string, error)
+}
+
+// handleImage pulls the image of the docker:// URI and extracts the directory holding its buildpack.toml into a tar
+// archive in the cache, returning the path of the archive. Archives are cached by image reference and layers.
+func (d *downloader) handleImage(uri string, pullPolicy image.PullPolicy) (string, error) {
+	if _, err := ParseImageURI(uri); err != nil {
+		return "", err
+	}
+	ref := strings.TrimPrefix(uri, ImageScheme+"://")
+	if d.imageFetcher == nil {
+		return "", fmt.Errorf("cannot fetch buildpack image %s without an image fetcher", style.Symbol(ref))
+	}
+
+	ctx := context.Background()
+	img, err := d.imageFetcher.Fetch(ctx, ref, true, pullPolicy)
+	if err != nil {
+		return "", errors.Wrapf(err, "fetching buildpack image %s", style.Symbol(ref))
+	}
+	layers, err := d.imageFetcher.Layers(ctx, ref, true)
+	if err != nil {
+		return "", errors.Wrapf(err, "reading layers of buildpack image %s", style.Symbol(ref))
+	}
+
+	cacheDir := d.versionedCacheDir()
+	if err := os.MkdirAll(cacheDir, 0755); err != nil {
+		return "", err
+	}
+	cachePath := filepath.Join(cacheDir, fmt.Sprintf("%x.tar", sha256.Sum256([]byte(ref+"\n"+strings.Join(layers, "\n")))))
+
+	exists, err := fileExists(cachePath)
+	if err != nil {
+		return "", err
+	}
+	if exists {
+		d.logger.Debugf("Using cached version of %s", style.Symbol(uri))
+		logging.LogEvent(d.logger, logging.Event{Type: logging.EventDownload, URI: uri, Action: "cached"})
+		return cachePath, nil
+	}
+
+	d.logger.Debugf("Extracting buildpack from image %s", style.Symbol(ref))
+	logging.LogEvent(d.logger, logging.Event{Type: logging.EventDownload, URI: uri, Action: "download"})
+	if err := extractBuildpack(img, layers, cachePath); err != nil {
+		return "", errors.Wrapf(err, "extracting buildpack from image %s", style.Symbol(ref))
+	}
+	return cachePath, nil
+}
+
+// extractBuildpack writes the buildpack directory of the image's filesystem to a tar archive at dest, with paths
+// relative to the directory. Each layer is fetched from the image once, as finding the directory takes a pass over all
+// layers before the pass that copies it.
+func extractBuildpack(img imgutil.Image, layers []string, dest string) error {
+	layersDir, err := ioutil.TempDir("", "pack.buildpack-image.")
+	if err != nil {
+		return errors.Wrap(err, "create temp dir for layers")
+	}
+	defer os.RemoveAll(layersDir)
+
+	layerPaths, err := saveLayers(img, layers, layersDir)
+	if err != nil {
+		return err
+	}
+
+	files, err := imageFiles(layerPaths, layers)
+	if err != nil {
+		return err
+	}
+	bpDir, err := buildpackDir(files)
+	if err != nil {
+		return err
+	}
+
+	tmp := dest + ".tmp"
+	fh, err := os.Create(tmp)
+	if err != nil {
+		return errors.Wrapf(err, "create cache path %s", style.Symbol(tmp))
+	}
+	defer os.Remove(tmp)
+
+	tw := tar.NewWriter(fh)
+	for i, layerPath := range layerPaths {
+		err := readLayer(layerPath, layers[i], func(header *tar.Header, r io.Reader) error {
+			name := cleanEntryName(header.Name)
+			if layer, ok := files[name]; !ok || layer != i {
+				return nil
+			}
+			rel, ok := relativeTo(name, bpDir)
+			if !ok || rel == "." {
+				return nil
+			}
+			if header.Typeflag == tar.TypeLink {
+				link, ok := relativeTo(cleanEntryName(header.Linkname), bpDir)
+				if !ok {
+					return fmt.Errorf("hard link %s points outside of the buildpack", style.Symbol(name))
+				}
+				header.Linkname = link
+			}
+			header.Name = rel
+			if err := tw.WriteHeader(header); err != nil {
+				return errors.Wrapf(err, "failed to write header for '%s'", rel)
+			}
+			_, err := io.Copy(tw, r)
+			return errors.Wrapf(err, "failed to write contents to '%s'", rel)
+		})
+		if err != nil {
+			fh.Close()
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		fh.Close()
+		return err
+	}
+	if err := fh.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, dest)
+}
+
+// saveLayers writes the layers of the image to files in dir, returning their paths in the order of the layers
+func saveLayers(img imgutil.Image, layers []string, dir string) ([]string, error) {
+	var paths []string
+	for i, diffID := range layers {
+		layerPath := filepath.Join(dir, fmt.Sprintf("%d.tar", i))
+		if err := saveLayer(img, diffID, layerPath); err != nil {
+			return nil, errors.Wrapf(err, "reading layer %s", style.Symbol(diffID))
+		}
+		paths = append(paths, layerPath)
+	}
+	return paths, nil
+}
+
+func saveLayer(img imgutil.Image, diffID, dest string) error {
+	rc, err := img.GetLayer(diffID)
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	fh, err := os.Create(dest)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(fh, rc); err != nil {
+		fh.Close()
+		return err
+	}
+	return fh.Close()
+}
+
+// imageFiles returns the paths of the image's filesystem, mapped to the index of the layer holding their contents
+func imageFiles(layerPaths, layers []string) (map[string]int, error) {
+	files := map[string]int{}
+	for i, layerPath := range layerPaths {
+		err := readLayer(layerPath, layers[i], func(header *tar.Header, _ io.Reader) error {
+			name := cleanEntryName(header.Name)
+			dir, base := path.Split(name)
+			switch {
+			case base == opaqueWhiteout:
+				removeLowerFiles(files, path.Clean(dir), i, false)
+			case strings.HasPrefix(base, whiteoutPrefix):
+				removeLowerFiles(files, path.Join(dir, strings.TrimPrefix(base, whiteoutPrefix)), i, true)
+			default:
+				files[name] = i
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+// removeLowerFiles removes the files below p that come from layers below the given one, and p itself if inclusive
+func removeLowerFiles(files map[string]int, p string, layer int, inclusive bool) {
+	for name, l := range files {
+		if l >= layer {
+			continue
+		}
+		if (inclusive && name == p) || strings.HasPrefix(name, p+"/") || (p == "." && name != ".") {
+			delete(files, name)
+		}
+	}
+}
+
+func buildpackDir(files map[string]int) (string, error) {
+	var dirs []string
+	for name := range files {
+		if path.Base(name) == "buildpack.toml" {
+			dirs = append(dirs, path.Dir(name))
+		}
+	}
+	sort.Strings(dirs)
+	switch len(dirs) {
+	case 0:
+		return "", errors.New("could not find a buildpack.toml in the image")
+	case 1:
+		return dirs[0], nil
+	default:
+		return "", fmt.Errorf("found more than one buildpack.toml in the image: %s", strings.Join(dirs, ", "))
+	}
+}
+
+func readLayer(layerPath, diffID string, fn func(header *tar.Header, r io.Reader) error) error {
+	fh, err := os.Open(layerPath)
+	if err != nil {
+		return err
+	}
+	defer fh.Close()
+
+	tr := tar.NewReader(fh)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return errors.Wrapf(err, "reading layer %s", style.Symbol(diffID))
+		}
+		if err := fn(header, tr); err != nil {
+			return err
+		}
+	}
+}
+
+func cleanEntryName(name string) string {
+	return path.Clean(strings.TrimPrefix(path.Clean("/"+name), "/"))
+}
+
+func relativeTo(name, dir string) (string, bool) {
+	if dir == "." {
+		return name, true
+	}
+	if name == dir {
+		return ".", true
+	}
+	if strings.HasPrefix(name, dir+"/") {
+		return strings.TrimPrefix(name, dir+"/"), true
+	}
+	return "", false
+}
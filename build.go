@@ -6,38 +6,70 @@ import (
 	"math/rand"
 	"net/url"
 	"os"
+	"path"
 	"path/filepath"
 	"runtime"
 	"strings"
+	"time"
 
 	"github.com/buildpack/imgutil"
+	"github.com/buildpack/lifecycle/metadata"
 	"github.com/docker/docker/api/types"
 	"github.com/google/go-containerregistry/pkg/name"
 	"github.com/pkg/errors"
 
+	"github.com/buildpack/pack/blob"
 	"github.com/buildpack/pack/build"
 	"github.com/buildpack/pack/builder"
+	"github.com/buildpack/pack/cache"
+	"github.com/buildpack/pack/image"
 	"github.com/buildpack/pack/internal/archive"
+	"github.com/buildpack/pack/internal/ignore"
 	"github.com/buildpack/pack/internal/paths"
+	"github.com/buildpack/pack/project"
 	"github.com/buildpack/pack/style"
 )
 
 type Lifecycle interface {
-	Execute(ctx context.Context, opts build.LifecycleOptions) error
+	Execute(ctx context.Context, opts build.LifecycleOptions) (*build.Result, error)
 }
 
 type BuildOptions struct {
-	Image             string              // required
-	Builder           string              // required
+	Image             string              // required, unless set by the app's project.toml
+	AdditionalTags    []string            // further tags for the same image, each must be a valid tag reference
+	Builder           string              // required, unless set by the app's project.toml or DefaultBuilder
+	DefaultBuilder    string              // only considered if neither Builder nor the app's project.toml set a builder
 	AppPath           string              // defaults to current working directory
 	RunImage          string              // defaults to the best mirror from the builder metadata or AdditionalMirrors
 	AdditionalMirrors map[string][]string // only considered if RunImage is not provided
-	Env               map[string]string
+	Env               map[string]string   // merged over the env in the app's project.toml
+	Secrets           map[string]string   // build env for the detect and build phases only, never written to an image
 	Publish           bool
-	NoPull            bool
+	PullPolicy        image.PullPolicy // for the builder, run and buildpack images, defaults to image.PullAlways
 	ClearCache        bool
-	Buildpacks        []string
-	ProxyConfig       *ProxyConfig // defaults to  environment proxy vars
+	CacheImage        string                   // registry image used as the build cache instead of a volume, requires Publish
+	Buildpacks        []string                 // replaces the buildpacks in the app's project.toml
+	Exclude           []string                 // gitignore-style patterns, applied after those in the app's .packignore and project.toml
+	ProxyConfig       *ProxyConfig             // defaults to  environment proxy vars
+	Network           string                   // docker network for the lifecycle containers, defaults to the daemon's default
+	Timeout           time.Duration            // for the whole build, no timeout when zero
+	PhaseTimeouts     map[string]time.Duration // keyed by lifecycle phase, e.g. 'builder', a phase fails with a build.TimeoutError when it passes
+	Volumes           []string                 // host mounts for the detect and build phases, in the form '<host path>:<target path>[:<mode>]'
+}
+
+type BuildResult struct {
+	Image          string                  `json:"image"` // fully qualified name of the app image
+	AdditionalTags []string                `json:"additionalTags,omitempty"`
+	ImageDigest    string                  `json:"imageDigest"`       // empty when the image was saved to the daemon and has not been pushed
+	ImageID        string                  `json:"imageId,omitempty"` // set when the image was saved to the daemon
+	Builder        string                  `json:"builder"`
+	BuilderDigest  string                  `json:"builderDigest"`
+	RunImage       string                  `json:"runImage"`
+	RunImageDigest string                  `json:"runImageDigest"`
+	Buildpacks     []builder.BuildpackInfo `json:"buildpacks"` // the buildpack group chosen by detection
+	BuildCache     string                  `json:"buildCache"`
+	LaunchCache    string                  `json:"launchCache"`
+	PhaseTimings   []build.PhaseTiming     `json:"phaseTimings"`
 }
 
 type ProxyConfig struct {
@@ -46,71 +78,290 @@ type ProxyConfig struct {
 	NoProxy    string
 }
 
-func (c *Client) Build(ctx context.Context, opts BuildOptions) error {
+// ErrNoBuilder is returned by Build when no builder is set by the options or the app's project.toml
+var ErrNoBuilder = errors.New("builder is a required parameter if the client has no default builder")
+
+func (c *Client) Build(ctx context.Context, opts BuildOptions) (*BuildResult, error) {
+	if err := validateTimeouts(opts.Timeout, opts.PhaseTimeouts); err != nil {
+		return nil, errors.Wrap(err, "invalid timeout")
+	}
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	appPath, err := c.processAppPath(opts.AppPath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid app path '%s'", opts.AppPath)
+	}
+
+	descriptor, err := c.readProjectDescriptor(appPath)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid project descriptor")
+	}
+	opts = applyProjectDescriptor(opts, descriptor, appPath)
+
 	imageRef, err := c.parseTagReference(opts.Image)
 	if err != nil {
-		return errors.Wrapf(err, "invalid image name '%s'", opts.Image)
+		return nil, errors.Wrapf(err, "invalid image name '%s'", opts.Image)
 	}
 
-	appPath, err := c.processAppPath(opts.AppPath)
+	additionalTags, err := c.processAdditionalTags(opts.AdditionalTags)
+	if err != nil {
+		return nil, err
+	}
+
+	tags := append([]string{imageRef.Name()}, additionalTags...)
+	pushFromDaemon := false
+	if opts.Publish {
+		if pushFromDaemon, err = c.requiresPushFromDaemon(tags); err != nil {
+			return nil, err
+		}
+	}
+	// the lifecycle exports to the daemon when pack has to push the image itself
+	exportToRegistry := opts.Publish && !pushFromDaemon
+
+	cacheImageRef, err := c.processCacheImage(opts.CacheImage, opts.Publish)
 	if err != nil {
-		return errors.Wrapf(err, "invalid app path '%s'", opts.AppPath)
+		return nil, errors.Wrapf(err, "invalid cache image '%s'", opts.CacheImage)
+	}
+
+	exclude, err := c.processExclusions(appPath, descriptor.Build.Include, append(descriptor.Build.Exclude, opts.Exclude...))
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid exclusions")
+	}
+
+	volumes, err := processVolumes(opts.Volumes)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid volume")
+	}
+
+	if err := validateSecrets(opts.Secrets); err != nil {
+		return nil, errors.Wrap(err, "invalid secret")
 	}
 
 	proxyConfig := c.processProxyConfig(opts.ProxyConfig)
 
 	builderRef, err := c.processBuilderName(opts.Builder)
 	if err != nil {
-		return errors.Wrapf(err, "invalid builder '%s'", opts.Builder)
+		return nil, errors.Wrapf(err, "invalid builder '%s'", opts.Builder)
 	}
 
-	rawBuilderImage, err := c.imageFetcher.Fetch(ctx, builderRef.Name(), true, !opts.NoPull)
+	rawBuilderImage, err := c.imageFetcher.Fetch(ctx, builderRef.Name(), true, opts.PullPolicy)
 	if err != nil {
-		return errors.Wrapf(err, "failed to fetch builder image '%s'", builderRef.Name())
+		return nil, errors.Wrapf(err, "failed to fetch builder image '%s'", builderRef.Name())
 	}
 
 	builderImage, err := c.processBuilderImage(rawBuilderImage)
 	if err != nil {
-		return errors.Wrapf(err, "invalid builder '%s'", opts.Builder)
+		return nil, errors.Wrapf(err, "invalid builder '%s'", opts.Builder)
+	}
+
+	builderDigest, err := rawBuilderImage.Digest()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get digest of builder '%s'", builderRef.Name())
 	}
 
 	runImage := c.resolveRunImage(opts.RunImage, imageRef.Context().RegistryStr(), builderImage.GetStackInfo(), opts.AdditionalMirrors)
 
-	if _, err := c.validateRunImage(ctx, runImage, opts.NoPull, opts.Publish, builderImage.StackID); err != nil {
-		return errors.Wrapf(err, "invalid run-image '%s'", runImage)
+	rawRunImage, err := c.validateRunImage(ctx, runImage, opts.PullPolicy, exportToRegistry, builderImage.StackID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid run-image '%s'", runImage)
 	}
 
-	fetchedBps, group, err := c.processBuildpacks(opts.Buildpacks)
+	fetchedBps, group, err := c.processBuildpacks(opts.Buildpacks, opts.PullPolicy)
 	if err != nil {
-		return errors.Wrap(err, "invalid buildpack")
+		return nil, errors.Wrap(err, "invalid buildpack")
 	}
 
 	ephemeralBuilder, err := c.createEphemeralBuilder(rawBuilderImage, opts.Env, group, fetchedBps)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer c.docker.ImageRemove(context.Background(), ephemeralBuilder.Name(), types.ImageRemoveOptions{Force: true})
 
-	return c.lifecycle.Execute(ctx, build.LifecycleOptions{
-		AppPath:    appPath,
-		Image:      imageRef,
-		Builder:    ephemeralBuilder,
-		RunImage:   runImage,
-		ClearCache: opts.ClearCache,
-		Publish:    opts.Publish,
-		HTTPProxy:  proxyConfig.HTTPProxy,
-		HTTPSProxy: proxyConfig.HTTPSProxy,
-		NoProxy:    proxyConfig.NoProxy,
+	lifecycleResult, err := c.lifecycle.Execute(ctx, build.LifecycleOptions{
+		AppPath:        appPath,
+		Exclude:        exclude,
+		Image:          imageRef,
+		AdditionalTags: additionalTags,
+		Builder:        ephemeralBuilder,
+		RunImage:       runImage,
+		ClearCache:     opts.ClearCache,
+		Publish:        exportToRegistry,
+		CacheImage:     cacheImageRef,
+		HTTPProxy:      proxyConfig.HTTPProxy,
+		HTTPSProxy:     proxyConfig.HTTPSProxy,
+		NoProxy:        proxyConfig.NoProxy,
+		Network:        opts.Network,
+		Volumes:        volumes,
+		Secrets:        opts.Secrets,
+		PhaseTimeouts:  opts.PhaseTimeouts,
+		Keychain:       c.registries.Keychain(),
 	})
+	if err != nil {
+		return nil, err
+	}
+
+	pushedDigest := ""
+	if pushFromDaemon {
+		if pushedDigest, err = c.pushFromDaemon(ctx, tags); err != nil {
+			return nil, err
+		}
+	}
+
+	usedCaches := []string{cache.NewVolumeCache(imageRef, "launch", c.docker).Name()}
+	if cacheImageRef == nil {
+		usedCaches = append(usedCaches, cache.NewVolumeCache(imageRef, "build", c.docker).Name())
+	}
+	c.recordCacheUsage(usedCaches...)
+
+	result, err := c.processBuildResult(ctx, imageRef, exportToRegistry, builderRef, builderDigest, rawRunImage, lifecycleResult)
+	if err != nil {
+		return nil, err
+	}
+	if pushFromDaemon {
+		result.ImageDigest = pushedDigest
+	}
+	result.AdditionalTags = additionalTags
+	return result, nil
+}
+
+func (c *Client) processBuildResult(ctx context.Context, imageRef name.Reference, publish bool, builderRef name.Reference, builderDigest string, runImage imgutil.Image, lifecycleResult *build.Result) (*BuildResult, error) {
+	appImage, err := c.imageFetcher.Fetch(ctx, imageRef.Name(), !publish, image.PullNever)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to fetch built image '%s'", imageRef.Name())
+	}
+
+	result := &BuildResult{
+		Image:         imageRef.Name(),
+		Builder:       builderRef.Name(),
+		BuilderDigest: builderDigest,
+		RunImage:      runImage.Name(),
+		BuildCache:    lifecycleResult.BuildCache,
+		LaunchCache:   lifecycleResult.LaunchCache,
+		PhaseTimings:  lifecycleResult.Phases,
+	}
+
+	if result.ImageDigest, err = appImage.Digest(); err != nil {
+		return nil, errors.Wrapf(err, "failed to get digest of image '%s'", imageRef.Name())
+	}
+	if !publish {
+		if result.ImageID, err = c.imageFetcher.ID(ctx, imageRef.Name(), true); err != nil {
+			return nil, errors.Wrapf(err, "failed to get ID of image '%s'", imageRef.Name())
+		}
+	}
+	if result.RunImageDigest, err = runImage.Digest(); err != nil {
+		return nil, errors.Wrapf(err, "failed to get digest of run image '%s'", runImage.Name())
+	}
+
+	rawMetadata, err := appImage.Label(metadata.AppMetadataLabel)
+	if err != nil {
+		return nil, err
+	}
+	if rawMetadata != "" {
+		md, err := metadata.GetAppMetadata(appImage)
+		if err != nil {
+			return nil, err
+		}
+		for _, bp := range md.Buildpacks {
+			result.Buildpacks = append(result.Buildpacks, builder.BuildpackInfo{ID: bp.ID, Version: bp.Version})
+		}
+	}
+
+	return result, nil
+}
+
+func (c *Client) readProjectDescriptor(appPath string) (project.Descriptor, error) {
+	isDir, err := paths.IsDir(appPath)
+	if err != nil || !isDir {
+		return project.Descriptor{}, err
+	}
+
+	if _, err := os.Stat(filepath.Join(appPath, project.FileName)); os.IsNotExist(err) {
+		return project.Descriptor{}, nil
+	}
+
+	c.logger.Debugf("Using project descriptor %s", style.Symbol(project.FileName))
+	return project.ReadDescriptor(appPath)
+}
+
+// applyProjectDescriptor fills in the options not set by the caller from the project descriptor
+func applyProjectDescriptor(opts BuildOptions, descriptor project.Descriptor, appPath string) BuildOptions {
+	if opts.Image == "" {
+		opts.Image = descriptor.Project.Image
+	}
+
+	if opts.Builder == "" {
+		opts.Builder = descriptor.Build.Builder
+	}
+	if opts.Builder == "" {
+		opts.Builder = opts.DefaultBuilder
+	}
+
+	if len(opts.Buildpacks) == 0 {
+		for _, bp := range descriptor.Build.Buildpacks {
+			switch {
+			case bp.URI == "":
+				ref := bp.ID
+				if bp.Version != "" {
+					ref += "@" + bp.Version
+				}
+				opts.Buildpacks = append(opts.Buildpacks, ref)
+			case paths.IsURI(bp.URI) || filepath.IsAbs(bp.URI):
+				opts.Buildpacks = append(opts.Buildpacks, bp.URI)
+			default:
+				opts.Buildpacks = append(opts.Buildpacks, filepath.Join(appPath, filepath.FromSlash(bp.URI)))
+			}
+		}
+	}
+
+	env := descriptor.Build.EnvMap()
+	for k, v := range opts.Env {
+		env[k] = v
+	}
+	opts.Env = env
+
+	return opts
 }
 
 func (c *Client) processBuilderName(builderName string) (name.Reference, error) {
 	if builderName == "" {
-		return nil, errors.New("builder is a required parameter if the client has no default builder")
+		return nil, ErrNoBuilder
 	}
 	return name.ParseReference(builderName, name.WeakValidation)
 }
 
+func (c *Client) processAdditionalTags(tags []string) ([]string, error) {
+	var names []string
+	for _, tag := range tags {
+		ref, err := c.parseTagReference(tag)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid additional tag '%s'", tag)
+		}
+		names = append(names, ref.Name())
+	}
+	return names, nil
+}
+
+func (c *Client) processCacheImage(cacheImage string, publish bool) (name.Reference, error) {
+	if cacheImage == "" {
+		return nil, nil
+	}
+	if !publish {
+		return nil, errors.New("cache image can only be used when publishing")
+	}
+	plainHTTP, err := c.registries.RequiresPlainHTTP(cacheImage)
+	if err != nil {
+		return nil, err
+	}
+	if plainHTTP {
+		return nil, errors.New("cache image must not be on an insecure registry")
+	}
+	return c.parseTagReference(cacheImage)
+}
+
 func (c *Client) processBuilderImage(img imgutil.Image) (*builder.Builder, error) {
 	builder, err := builder.GetBuilder(img)
 	if err != nil {
@@ -122,11 +373,11 @@ func (c *Client) processBuilderImage(img imgutil.Image) (*builder.Builder, error
 	return builder, nil
 }
 
-func (c *Client) validateRunImage(context context.Context, name string, noPull bool, publish bool, expectedStack string) (imgutil.Image, error) {
+func (c *Client) validateRunImage(context context.Context, name string, pullPolicy image.PullPolicy, publish bool, expectedStack string) (imgutil.Image, error) {
 	if name == "" {
 		return nil, errors.New("run image must be specified")
 	}
-	img, err := c.imageFetcher.Fetch(context, name, !publish, !noPull)
+	img, err := c.imageFetcher.Fetch(context, name, !publish, pullPolicy)
 	if err != nil {
 		return nil, err
 	}
@@ -185,6 +436,150 @@ func (c *Client) processAppPath(appPath string) (string, error) {
 	return resolvedAppPath, nil
 }
 
+func (c *Client) processExclusions(appPath string, includePatterns, patterns []string) (archive.ExcludeFunc, error) {
+	isDir, err := paths.IsDir(appPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var allPatterns []string
+	if isDir {
+		filePatterns, err := ignore.ReadFile(filepath.Join(appPath, ignore.FileName))
+		if err != nil {
+			return nil, err
+		}
+		if len(filePatterns) > 0 {
+			c.logger.Debugf("Using exclusions from %s", style.Symbol(ignore.FileName))
+		}
+		allPatterns = append(allPatterns, filePatterns...)
+	}
+	allPatterns = append(allPatterns, patterns...)
+
+	var excluded archive.ExcludeFunc
+	if len(allPatterns) > 0 {
+		matcher, err := ignore.NewMatcher(allPatterns)
+		if err != nil {
+			return nil, err
+		}
+		excluded = matcher.Matches
+	}
+
+	if len(includePatterns) == 0 {
+		return excluded, nil
+	}
+
+	included, err := ignore.NewMatcher(includePatterns)
+	if err != nil {
+		return nil, err
+	}
+
+	// directories are always walked so that included files within them are found
+	return func(path string, isDir bool) bool {
+		if excluded != nil && excluded(path, isDir) {
+			return true
+		}
+		return !isDir && !included.Matches(path, false)
+	}, nil
+}
+
+func validateTimeouts(timeout time.Duration, phaseTimeouts map[string]time.Duration) error {
+	if timeout < 0 {
+		return fmt.Errorf("build timeout %s must not be negative", style.Symbol(timeout.String()))
+	}
+	for phase, t := range phaseTimeouts {
+		if !isPhaseName(phase) {
+			return fmt.Errorf("unknown phase %s, must be one of %s", style.Symbol(phase), strings.Join(build.PhaseNames, ", "))
+		}
+		if t < 0 {
+			return fmt.Errorf("timeout %s for phase %s must not be negative", style.Symbol(t.String()), style.Symbol(phase))
+		}
+	}
+	return nil
+}
+
+func isPhaseName(name string) bool {
+	for _, phase := range build.PhaseNames {
+		if phase == name {
+			return true
+		}
+	}
+	return false
+}
+
+func validateSecrets(secrets map[string]string) error {
+	for name := range secrets {
+		if name == "" || strings.ContainsAny(name, `/\`) || name == "." || name == ".." {
+			return fmt.Errorf("secret name %s is not a valid env var name", style.Symbol(name))
+		}
+	}
+	return nil
+}
+
+// reservedMountPaths are used by the lifecycle and cannot be shadowed by user volumes
+var reservedMountPaths = []string{"/layers", "/workspace", "/cnb", "/platform"}
+
+// processVolumes validates the volumes and returns them as docker binds, with the mode defaulting to read-only
+func processVolumes(volumes []string) ([]string, error) {
+	var binds []string
+	for _, volume := range volumes {
+		parts := splitVolume(volume)
+		if len(parts) < 2 || len(parts) > 3 || parts[0] == "" || parts[1] == "" {
+			return nil, fmt.Errorf("volume %s must be in the form %s", style.Symbol(volume), style.Symbol("<host path>:<target path>[:<mode>]"))
+		}
+
+		source := parts[0]
+		if !hasDriveLetter(source) && (strings.HasPrefix(source, ".") || strings.ContainsRune(source, filepath.Separator)) {
+			abs, err := filepath.Abs(source)
+			if err != nil {
+				return nil, errors.Wrapf(err, "resolve host path of volume %s", style.Symbol(volume))
+			}
+			source = abs
+		}
+
+		target := path.Clean(parts[1])
+		if !path.IsAbs(target) {
+			return nil, fmt.Errorf("target path of volume %s must be absolute", style.Symbol(volume))
+		}
+		for _, reserved := range reservedMountPaths {
+			if isSameOrNestedPath(target, reserved) || isSameOrNestedPath(reserved, target) {
+				return nil, fmt.Errorf("volume %s cannot be mounted over %s", style.Symbol(volume), style.Symbol(reserved))
+			}
+		}
+
+		mode := "ro"
+		if len(parts) == 3 {
+			mode = parts[2]
+		}
+		if mode != "ro" && mode != "rw" {
+			return nil, fmt.Errorf("mode of volume %s must be %s or %s", style.Symbol(volume), style.Symbol("ro"), style.Symbol("rw"))
+		}
+
+		binds = append(binds, fmt.Sprintf("%s:%s:%s", source, target, mode))
+	}
+	return binds, nil
+}
+
+// splitVolume splits a volume into its host path, target path and mode, keeping the drive letter of Windows host
+// paths like 'C:\deps' in the host path
+func splitVolume(volume string) []string {
+	var drive string
+	if hasDriveLetter(volume) {
+		drive, volume = volume[:2], volume[2:]
+	}
+	parts := strings.Split(volume, ":")
+	parts[0] = drive + parts[0]
+	return parts
+}
+
+func hasDriveLetter(p string) bool {
+	return len(p) > 2 && p[1] == ':' && (p[2] == '\\' || p[2] == '/') &&
+		(('a' <= p[0] && p[0] <= 'z') || ('A' <= p[0] && p[0] <= 'Z'))
+}
+
+func isSameOrNestedPath(p, parent string) bool {
+	return p == parent || parent == "/" || strings.HasPrefix(p, parent+"/")
+}
+
 func (c *Client) processProxyConfig(config *ProxyConfig) ProxyConfig {
 	var (
 		httpProxy, httpsProxy, noProxy string
@@ -209,7 +604,7 @@ func (c *Client) processProxyConfig(config *ProxyConfig) ProxyConfig {
 	}
 }
 
-func (c *Client) processBuildpacks(buildpacks []string) ([]builder.Buildpack, builder.OrderEntry, error) {
+func (c *Client) processBuildpacks(buildpacks []string, pullPolicy image.PullPolicy) ([]builder.Buildpack, builder.OrderEntry, error) {
 	group := builder.OrderEntry{Group: []builder.BuildpackRef{}}
 	var bps []builder.Buildpack
 	for _, bp := range buildpacks {
@@ -229,7 +624,7 @@ func (c *Client) processBuildpacks(buildpacks []string) ([]builder.Buildpack, bu
 
 			c.logger.Debugf("fetching buildpack from %s", style.Symbol(bp))
 
-			blob, err := c.downloader.Download(bp)
+			blob, err := c.downloader.Download(bp, pullPolicy)
 			if err != nil {
 				return nil, builder.OrderEntry{}, errors.Wrapf(err, "downloading buildpack from %s", style.Symbol(bp))
 			}
@@ -259,6 +654,10 @@ func isBuildpackID(bp string) bool {
 }
 
 func ensureBPSupport(bpPath string) (err error) {
+	if blob.IsImageURI(bpPath) {
+		return nil
+	}
+
 	p := bpPath
 	if paths.IsURI(bpPath) {
 		var u *url.URL
@@ -2,35 +2,82 @@ package build
 
 import (
 	"context"
+	"fmt"
 	"math/rand"
+	"os"
 	"sync"
 	"time"
 
 	"github.com/docker/docker/client"
+	"github.com/google/go-containerregistry/pkg/authn"
 	"github.com/google/go-containerregistry/pkg/name"
 	"github.com/pkg/errors"
 
 	"github.com/buildpack/pack/builder"
 	"github.com/buildpack/pack/cache"
+	"github.com/buildpack/pack/internal/archive"
+	"github.com/buildpack/pack/internal/dockerhost"
 	"github.com/buildpack/pack/logging"
 	"github.com/buildpack/pack/style"
 )
 
 type Lifecycle struct {
-	builder      *builder.Builder
-	logger       logging.Logger
-	docker       *client.Client
-	appPath      string
-	appOnce      *sync.Once
-	httpProxy    string
-	httpsProxy   string
-	noProxy      string
-	LayersVolume string
-	AppVolume    string
+	builder       *builder.Builder
+	logger        logging.Logger
+	docker        *client.Client
+	daemonHost    string
+	daemonAccess  bool // whether the daemon's socket can be shared with the phase containers
+	appPath       string
+	appOnce       *sync.Once
+	exclude       archive.ExcludeFunc
+	httpProxy     string
+	httpsProxy    string
+	noProxy       string
+	network       string
+	volumes       []string
+	secrets       map[string]string
+	phaseTimeouts map[string]time.Duration
+	keychain      authn.Keychain
+	LayersVolume  string
+	AppVolume     string
+	SecretsVolume string // only set when there are secrets
+}
+
+// Result describes a successful execution of the lifecycle
+type Result struct {
+	BuildCache  string
+	LaunchCache string
+	Phases      []PhaseTiming
+}
+
+// PhaseNames are the phases Execute runs, in order
+var PhaseNames = []string{"detector", "restorer", "analyzer", "builder", "exporter", "cacher"}
+
+// TimeoutError is returned when a phase runs past its own timeout or the deadline of the build
+type TimeoutError struct {
+	Phase   string
+	Elapsed time.Duration
+}
+
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("phase %s timed out after %s", style.Symbol(e.Phase), e.Elapsed.Round(time.Millisecond))
+}
+
+type PhaseTiming struct {
+	Name     string        `json:"name"`
+	Duration time.Duration `json:"duration"` // nanoseconds when encoded
+}
+
+func (r *Result) time(phase string, run func() error) error {
+	start := time.Now()
+	err := run()
+	r.Phases = append(r.Phases, PhaseTiming{Name: phase, Duration: time.Since(start)})
+	return err
 }
 
 type Cache interface {
 	Name() string
+	Type() cache.Type
 	Clear(context.Context) error
 }
 
@@ -39,79 +86,137 @@ func init() {
 }
 
 func NewLifecycle(docker *client.Client, logger logging.Logger) *Lifecycle {
-	return &Lifecycle{logger: logger, docker: docker}
+	return &Lifecycle{logger: logger, docker: docker, daemonHost: os.Getenv(dockerhost.EnvHost)}
 }
 
 type LifecycleOptions struct {
-	AppPath    string
-	Image      name.Reference
-	Builder    *builder.Builder
-	RunImage   string
-	ClearCache bool
-	Publish    bool
-	HTTPProxy  string
-	HTTPSProxy string
-	NoProxy    string
+	AppPath        string
+	Exclude        archive.ExcludeFunc
+	Image          name.Reference
+	AdditionalTags []string // further tags the exporter writes the image under
+	Builder        *builder.Builder
+	RunImage       string
+	ClearCache     bool
+	Publish        bool
+	CacheImage     name.Reference
+	HTTPProxy      string
+	HTTPSProxy     string
+	NoProxy        string
+	Network        string                   // docker network for the phase containers, e.g. 'none' or a user-defined network
+	Volumes        []string                 // binds for the detect and build phases
+	Secrets        map[string]string        // env for the detect and build phases, kept out of images and container config
+	PhaseTimeouts  map[string]time.Duration // keyed by phase name, e.g. 'builder'
+	Keychain       authn.Keychain           // credentials passed to phases with registry access, defaults to the docker config
 }
 
-func (l *Lifecycle) Execute(ctx context.Context, opts LifecycleOptions) error {
+func (l *Lifecycle) Execute(ctx context.Context, opts LifecycleOptions) (*Result, error) {
+	lifecycleVersion := opts.Builder.GetLifecycleDescriptor().Info.Version
+	if lifecycleVersion == nil {
+		l.logger.Warnf("lifecycle version unknown, assuming %s", style.Symbol(builder.AssumedLifecycleVersion))
+		lifecycleVersion = builder.VersionMustParse(builder.AssumedLifecycleVersion)
+	} else {
+		l.logger.Debugf("Executing lifecycle version %s", style.Symbol(lifecycleVersion.String()))
+	}
+
+	if len(opts.AdditionalTags) > 0 && lifecycleVersion.LessThan(&builder.VersionMustParse(builder.AdditionalTagsLifecycleVersion).Version) {
+		return nil, fmt.Errorf(
+			"additional tags require lifecycle %s or later, builder %s has lifecycle %s",
+			style.Symbol(builder.AdditionalTagsLifecycleVersion),
+			style.Symbol(opts.Builder.Name()),
+			style.Symbol(lifecycleVersion.String()),
+		)
+	}
+
+	daemonAccess := true
+	if _, err := dockerhost.DaemonSocket(l.daemonHost); err != nil {
+		if errors.Cause(err) != dockerhost.ErrNoDaemonSocket {
+			return nil, err
+		}
+		if !opts.Publish {
+			return nil, errors.Wrapf(err, "the daemon's socket cannot be shared with the lifecycle, %s is required", style.Symbol("--publish"))
+		}
+		daemonAccess = false
+	}
+
 	l.Setup(opts)
 	defer l.Cleanup()
+	l.daemonAccess = daemonAccess
 
-	buildCache := cache.NewVolumeCache(opts.Image, "build", l.docker)
 	launchCache := cache.NewVolumeCache(opts.Image, "launch", l.docker)
-	l.logger.Debugf("Using build cache volume %s", style.Symbol(buildCache.Name()))
+	volumes := []*cache.VolumeCache{launchCache}
+
+	var buildCache Cache
+	if opts.CacheImage != nil {
+		buildCache = cache.NewImageCache(opts.CacheImage, l.docker, l.keychain)
+		l.logger.Debugf("Using build cache image %s", style.Symbol(buildCache.Name()))
+	} else {
+		buildVolume := cache.NewVolumeCache(opts.Image, "build", l.docker)
+		volumes = append(volumes, buildVolume)
+		buildCache = buildVolume
+		l.logger.Debugf("Using build cache volume %s", style.Symbol(buildCache.Name()))
+	}
 
 	if opts.ClearCache {
 		if err := buildCache.Clear(ctx); err != nil {
-			return errors.Wrap(err, "clearing build cache")
+			return nil, errors.Wrap(err, "clearing build cache")
 		}
 		l.logger.Debugf("Build cache %s cleared", style.Symbol(buildCache.Name()))
+		logging.LogEvent(l.logger, logging.Event{Type: logging.EventCache, Cache: buildCache.Name(), Action: "clear"})
 	}
 
-	lifecycleVersion := l.builder.GetLifecycleDescriptor().Info.Version
-	if lifecycleVersion == nil {
-		l.logger.Warnf("lifecycle version unknown, assuming %s", style.Symbol(builder.AssumedLifecycleVersion))
-		lifecycleVersion = builder.VersionMustParse(builder.AssumedLifecycleVersion)
-	} else {
-		l.logger.Debugf("Executing lifecycle version %s", style.Symbol(lifecycleVersion.String()))
+	for _, volume := range volumes {
+		if err := volume.Create(ctx); err != nil {
+			return nil, errors.Wrapf(err, "creating cache volume %s", style.Symbol(volume.Name()))
+		}
+	}
+	for _, c := range []Cache{buildCache, launchCache} {
+		logging.LogEvent(l.logger, logging.Event{Type: logging.EventCache, Cache: c.Name(), Action: "use"})
+	}
+
+	result := &Result{
+		BuildCache:  buildCache.Name(),
+		LaunchCache: launchCache.Name(),
 	}
 
 	l.logger.Debug(style.Step("DETECTING"))
-	if err := l.Detect(ctx); err != nil {
-		return err
+	if err := result.time("detector", func() error { return l.Detect(ctx) }); err != nil {
+		return nil, err
 	}
 
 	l.logger.Debug(style.Step("RESTORING"))
 	if opts.ClearCache {
 		l.logger.Debug("Skipping 'restore' due to clearing cache")
 	} else {
-		if err := l.Restore(ctx, buildCache.Name()); err != nil {
-			return err
+		if err := result.time("restorer", func() error { return l.Restore(ctx, buildCache) }); err != nil {
+			return nil, err
 		}
 	}
 
 	l.logger.Debug(style.Step("ANALYZING"))
-	if err := l.Analyze(ctx, opts.Image.Name(), opts.Publish, opts.ClearCache); err != nil {
-		return err
+	if err := result.time("analyzer", func() error {
+		return l.Analyze(ctx, opts.Image.Name(), opts.Publish, opts.ClearCache)
+	}); err != nil {
+		return nil, err
 	}
 
 	l.logger.Debug(style.Step("BUILDING"))
-	if err := l.Build(ctx); err != nil {
-		return err
+	if err := result.time("builder", func() error { return l.Build(ctx) }); err != nil {
+		return nil, err
 	}
 
 	l.logger.Debug(style.Step("EXPORTING"))
 	launchCacheName := launchCache.Name()
-	if err := l.Export(ctx, opts.Image.Name(), opts.RunImage, opts.Publish, launchCacheName); err != nil {
-		return err
+	if err := result.time("exporter", func() error {
+		return l.Export(ctx, opts.Image.Name(), opts.RunImage, opts.Publish, launchCacheName, opts.AdditionalTags)
+	}); err != nil {
+		return nil, err
 	}
 
 	l.logger.Debug(style.Step("CACHING"))
-	if err := l.Cache(ctx, buildCache.Name()); err != nil {
-		return err
+	if err := result.time("cacher", func() error { return l.Cache(ctx, buildCache) }); err != nil {
+		return nil, err
 	}
-	return nil
+	return result, nil
 }
 
 func (l *Lifecycle) Setup(opts LifecycleOptions) {
@@ -119,10 +224,24 @@ func (l *Lifecycle) Setup(opts LifecycleOptions) {
 	l.AppVolume = "pack-app-" + randString(10)
 	l.appPath = opts.AppPath
 	l.appOnce = &sync.Once{}
+	l.daemonAccess = true
+	l.exclude = opts.Exclude
 	l.builder = opts.Builder
 	l.httpProxy = opts.HTTPProxy
 	l.httpsProxy = opts.HTTPSProxy
 	l.noProxy = opts.NoProxy
+	l.network = opts.Network
+	l.volumes = opts.Volumes
+	l.secrets = opts.Secrets
+	l.phaseTimeouts = opts.PhaseTimeouts
+	l.keychain = opts.Keychain
+	if l.keychain == nil {
+		l.keychain = authn.DefaultKeychain
+	}
+	l.SecretsVolume = ""
+	if len(opts.Secrets) > 0 {
+		l.SecretsVolume = "pack-secrets-" + randString(10)
+	}
 }
 
 func (l *Lifecycle) Cleanup() error {
@@ -133,6 +252,11 @@ func (l *Lifecycle) Cleanup() error {
 	if err := l.docker.VolumeRemove(context.Background(), l.AppVolume, true); err != nil {
 		reterr = errors.Wrapf(err, "failed to clean up app volume %s", l.AppVolume)
 	}
+	if l.SecretsVolume != "" {
+		if err := l.docker.VolumeRemove(context.Background(), l.SecretsVolume, true); err != nil && !client.IsErrNotFound(err) {
+			reterr = errors.Wrapf(err, "failed to clean up secrets volume %s", l.SecretsVolume)
+		}
+	}
 	return reterr
 }
 
@@ -0,0 +1,82 @@
+package build
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"testing"
+
+	"github.com/buildpack/imgutil/fakes"
+	"github.com/fatih/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpack/pack/builder"
+	"github.com/buildpack/pack/logging"
+	h "github.com/buildpack/pack/testhelpers"
+)
+
+func TestLifecycle(t *testing.T) {
+	color.NoColor = true
+	spec.Run(t, "lifecycle", testLifecycle, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testLifecycle(t *testing.T, when spec.G, it spec.S) {
+	when("#Execute", func() {
+		when("the daemon is remote", func() {
+			it("requires publishing, as the lifecycle cannot export to the daemon", func() {
+				subject := &Lifecycle{logger: logging.New(ioutil.Discard), daemonHost: "tcp://10.0.0.1:2376"}
+
+				_, err := subject.Execute(context.TODO(), LifecycleOptions{
+					Builder: newBuilder(t, "0.5.0"),
+					Publish: false,
+				})
+				h.AssertError(t, err, "the daemon's socket cannot be shared with the lifecycle, '--publish' is required")
+				h.AssertError(t, err, "daemon 'tcp://10.0.0.1:2376' is remote")
+			})
+		})
+
+		when("there are additional tags", func() {
+			it("requires a lifecycle whose exporter accepts them", func() {
+				subject := &Lifecycle{logger: logging.New(ioutil.Discard)}
+
+				_, err := subject.Execute(context.TODO(), LifecycleOptions{
+					Builder:        newBuilder(t, "0.4.0"),
+					AdditionalTags: []string{"some/app:v1"},
+				})
+				h.AssertError(t, err, "additional tags require lifecycle '0.5.0' or later, builder 'some/builder' has lifecycle '0.4.0'")
+			})
+
+			when("the builder has no lifecycle version", func() {
+				it("assumes a lifecycle that does not accept them", func() {
+					subject := &Lifecycle{logger: logging.New(ioutil.Discard)}
+
+					_, err := subject.Execute(context.TODO(), LifecycleOptions{
+						Builder:        newBuilder(t, ""),
+						AdditionalTags: []string{"some/app:v1"},
+					})
+					h.AssertError(t, err, "builder 'some/builder' has lifecycle '0.3.0'")
+				})
+			})
+		})
+	})
+}
+
+func newBuilder(t *testing.T, lifecycleVersion string) *builder.Builder {
+	t.Helper()
+
+	metadata := `{}`
+	if lifecycleVersion != "" {
+		metadata = fmt.Sprintf(`{"lifecycle": {"version": %q}}`, lifecycleVersion)
+	}
+
+	img := fakes.NewImage("some/builder", "", "")
+	h.AssertNil(t, img.SetEnv("CNB_USER_ID", "1234"))
+	h.AssertNil(t, img.SetEnv("CNB_GROUP_ID", "4321"))
+	h.AssertNil(t, img.SetLabel("io.buildpacks.stack.id", "some.stack.id"))
+	h.AssertNil(t, img.SetLabel(builder.MetadataLabel, metadata))
+
+	bldr, err := builder.GetBuilder(img)
+	h.AssertNil(t, err)
+	return bldr
+}
@@ -1,12 +1,17 @@
 package build
 
 import (
+	"archive/tar"
+	"bytes"
 	"context"
 	"fmt"
 	"io"
 	"os"
+	"path"
 	"runtime"
+	"sort"
 	"sync"
+	"time"
 
 	"github.com/buildpack/lifecycle/image/auth"
 	"github.com/docker/docker/api/types"
@@ -17,6 +22,7 @@ import (
 
 	"github.com/buildpack/pack/internal/archive"
 	"github.com/buildpack/pack/internal/container"
+	"github.com/buildpack/pack/internal/dockerhost"
 	"github.com/buildpack/pack/logging"
 )
 
@@ -24,12 +30,17 @@ type Phase struct {
 	name     string
 	logger   logging.Logger
 	docker   *client.Client
+	host     string
 	ctrConf  *dcontainer.Config
 	hostConf *dcontainer.HostConfig
 	ctr      dcontainer.ContainerCreateCreatedBody
 	uid, gid int
 	appPath  string
 	appOnce  *sync.Once
+	exclude  archive.ExcludeFunc
+	secrets  map[string]string
+	redact   []string
+	timeout  time.Duration
 }
 
 func (l *Lifecycle) NewPhase(name string, ops ...func(*Phase) (*Phase, error)) (*Phase, error) {
@@ -49,11 +60,22 @@ func (l *Lifecycle) NewPhase(name string, ops ...func(*Phase) (*Phase, error)) (
 		hostConf: hostConf,
 		name:     name,
 		docker:   l.docker,
+		host:     l.daemonHost,
 		logger:   l.logger,
 		uid:      l.builder.UID,
 		gid:      l.builder.GID,
 		appPath:  l.appPath,
 		appOnce:  l.appOnce,
+		exclude:  l.exclude,
+		timeout:  l.phaseTimeouts[name],
+	}
+
+	for _, v := range l.secrets {
+		phase.redact = append(phase.redact, v)
+	}
+
+	if l.network != "" {
+		phase.hostConf.NetworkMode = dcontainer.NetworkMode(l.network)
 	}
 
 	if l.httpProxy != "" {
@@ -86,10 +108,24 @@ func WithArgs(args ...string) func(*Phase) (*Phase, error) {
 	}
 }
 
+// WithDaemonAccess binds the socket of the daemon given by DOCKER_HOST, e.g. that of a rootless or remote daemon,
+// to the default socket path in the container
 func WithDaemonAccess() func(*Phase) (*Phase, error) {
+	return func(phase *Phase) (*Phase, error) {
+		socket, err := dockerhost.DaemonSocket(phase.host)
+		if err != nil {
+			return nil, errors.Wrap(err, "daemon access unavailable")
+		}
+		phase.ctrConf.User = "root"
+		phase.hostConf.Binds = append(phase.hostConf.Binds, fmt.Sprintf("%s:%s", socket, dockerhost.DefaultSocket))
+		return phase, nil
+	}
+}
+
+// WithRoot runs the phase as root
+func WithRoot() func(*Phase) (*Phase, error) {
 	return func(phase *Phase) (*Phase, error) {
 		phase.ctrConf.User = "root"
-		phase.hostConf.Binds = append(phase.hostConf.Binds, "/var/run/docker.sock:/var/run/docker.sock")
 		return phase, nil
 	}
 }
@@ -101,19 +137,51 @@ func WithBinds(binds ...string) func(*Phase) (*Phase, error) {
 	}
 }
 
-func WithRegistryAccess(repos ...string) func(*Phase) (*Phase, error) {
+// WithSecrets mounts the secrets volume over the platform env dir, which the volume is seeded from, and copies the
+// secrets into it before the phase runs, so that they are never part of an image or of the container config
+func WithSecrets(volume string, secrets map[string]string) func(*Phase) (*Phase, error) {
+	return func(phase *Phase) (*Phase, error) {
+		if len(secrets) == 0 {
+			return phase, nil
+		}
+		phase.hostConf.Binds = append(phase.hostConf.Binds, fmt.Sprintf("%s:%s", volume, platformEnvDir))
+		phase.secrets = secrets
+		return phase, nil
+	}
+}
+
+func WithRegistryAccess(keychain authn.Keychain, repos ...string) func(*Phase) (*Phase, error) {
 	return func(phase *Phase) (*Phase, error) {
-		authHeader, err := auth.BuildEnvVar(authn.DefaultKeychain, repos...)
+		authHeader, err := auth.BuildEnvVar(keychain, repos...)
 		if err != nil {
 			return nil, err
 		}
 		phase.ctrConf.Env = append(phase.ctrConf.Env, fmt.Sprintf(`CNB_REGISTRY_AUTH=%s`, authHeader))
-		phase.hostConf.NetworkMode = "host"
+		if phase.hostConf.NetworkMode == "" {
+			phase.hostConf.NetworkMode = "host"
+		}
 		return phase, nil
 	}
 }
 
+// Run runs the phase, returning a TimeoutError if the phase timeout or the deadline of ctx passes first. The container
+// is left to Cleanup, which stops it.
 func (p *Phase) Run(ctx context.Context) error {
+	if p.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	err := p.run(ctx)
+	if err != nil && ctx.Err() == context.DeadlineExceeded {
+		return &TimeoutError{Phase: p.name, Elapsed: time.Since(start)}
+	}
+	return err
+}
+
+func (p *Phase) run(ctx context.Context) error {
 	var err error
 
 	p.ctr, err = p.docker.ContainerCreate(ctx, p.ctrConf, p.hostConf, nil, "")
@@ -154,13 +222,41 @@ func (p *Phase) Run(ctx context.Context) error {
 		return errors.Wrapf(err, "failed to copy files to '%s' container", p.name)
 	}
 
-	return container.Run(
-		ctx,
-		p.docker,
-		p.ctr.ID,
-		logging.NewPrefixWriter(logging.GetDebugWriter(p.logger), p.name),
-		logging.NewPrefixWriter(logging.GetDebugErrorWriter(p.logger), p.name),
-	)
+	if len(p.secrets) > 0 {
+		if err := p.docker.CopyToContainer(ctx, p.ctr.ID, "/", p.createSecretsReader(), types.CopyToContainerOptions{}); err != nil {
+			return errors.Wrapf(err, "failed to copy secrets to '%s' container", p.name)
+		}
+	}
+
+	out, errOut := p.outputWriters()
+	logging.LogEvent(p.logger, logging.Event{Type: logging.EventPhaseStart, Phase: p.name})
+	err = container.Run(ctx, p.docker, p.ctr.ID, out, errOut)
+	logging.LogEvent(p.logger, logging.Event{Type: logging.EventPhaseEnd, Phase: p.name, ExitCode: exitCode(err)})
+	return err
+}
+
+func (p *Phase) outputWriters() (io.Writer, io.Writer) {
+	var out, errOut io.Writer
+	if el, ok := p.logger.(logging.WithEvents); ok {
+		out, errOut = logging.NewEventWriter(el, p.name, "stdout"), logging.NewEventWriter(el, p.name, "stderr")
+	} else {
+		out, errOut = logging.NewPrefixWriter(logging.GetDebugWriter(p.logger), p.name),
+			logging.NewPrefixWriter(logging.GetDebugErrorWriter(p.logger), p.name)
+	}
+	return logging.NewRedactingWriter(out, p.redact...), logging.NewRedactingWriter(errOut, p.redact...)
+}
+
+// exitCode is nil when the container did not run to completion
+func exitCode(err error) *int {
+	code := 0
+	if err != nil {
+		exitErr, ok := err.(*container.ExitError)
+		if !ok {
+			return nil
+		}
+		code = exitErr.StatusCode
+	}
+	return &code
 }
 
 func (p *Phase) Cleanup() error {
@@ -179,8 +275,34 @@ func (p *Phase) createAppReader() (io.ReadCloser, error) {
 			mode = 0777
 		}
 
-		return archive.ReadDirAsTar(p.appPath, appDir, p.uid, p.gid, mode), nil
+		return archive.ReadDirAsTar(p.appPath, appDir, p.uid, p.gid, mode, p.exclude), nil
 	}
 
-	return archive.ReadZipAsTar(p.appPath, appDir, p.uid, p.gid, -1), nil
+	return archive.ReadZipAsTar(p.appPath, appDir, p.uid, p.gid, -1, p.exclude), nil
+}
+
+func (p *Phase) createSecretsReader() io.Reader {
+	var names []string
+	for name := range p.secrets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	buf := &bytes.Buffer{}
+	tw := tar.NewWriter(buf)
+	for _, name := range names {
+		value := p.secrets[name]
+		// writing to an in-memory buffer cannot fail
+		_ = tw.WriteHeader(&tar.Header{
+			Name:    path.Join(platformEnvDir, name),
+			Size:    int64(len(value)),
+			Mode:    0600,
+			Uid:     p.uid,
+			Gid:     p.gid,
+			ModTime: archive.NormalizedDateTime,
+		})
+		_, _ = tw.Write([]byte(value))
+	}
+	_ = tw.Close()
+	return buf
 }
@@ -18,6 +18,7 @@ import (
 	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/client"
 	"github.com/fatih/color"
+	"github.com/google/go-containerregistry/pkg/authn"
 	"github.com/sclevine/spec"
 	"github.com/sclevine/spec/report"
 
@@ -192,6 +193,109 @@ func testPhase(t *testing.T, when spec.G, it spec.S) {
 				h.AssertContains(t, outBuf.String(), "no_proxy=some-no-proxy")
 			})
 
+			when("a network is set", func() {
+				it.Before(func() {
+					h.AssertNil(t, subject.Cleanup())
+
+					var err error
+					subject, err = CreateFakeLifecycle(filepath.Join("testdata", "fake-app"), docker, fakes.NewFakeLogger(&outBuf), func(opts *build.LifecycleOptions) {
+						opts.Network = "none"
+					})
+					h.AssertNil(t, err)
+				})
+
+				it("runs the phase container on the network", func() {
+					phase, err := subject.NewPhase("phase", build.WithArgs("network"))
+					h.AssertNil(t, err)
+					assertRunSucceeds(t, phase, &outBuf, &errBuf)
+					h.AssertContains(t, outBuf.String(), "[phase] interface: lo")
+					h.AssertNotContains(t, outBuf.String(), "[phase] interface: eth0")
+				})
+
+				it("is not replaced by registry access", func() {
+					phase, err := subject.NewPhase("phase", build.WithArgs("network"), build.WithRegistryAccess(authn.DefaultKeychain))
+					h.AssertNil(t, err)
+					assertRunSucceeds(t, phase, &outBuf, &errBuf)
+					h.AssertNotContains(t, outBuf.String(), "[phase] interface: eth0")
+				})
+			})
+
+			when("#WithSecrets", func() {
+				it.Before(func() {
+					h.AssertNil(t, subject.Cleanup())
+
+					var err error
+					subject, err = CreateFakeLifecycle(filepath.Join("testdata", "fake-app"), docker, fakes.NewFakeLogger(&outBuf), func(opts *build.LifecycleOptions) {
+						opts.Secrets = map[string]string{"SOME_TOKEN": "some-secret-value"}
+					})
+					h.AssertNil(t, err)
+				})
+
+				it("provides the secrets in the platform env dir", func() {
+					phase, err := subject.NewPhase(
+						"phase",
+						build.WithArgs("read", "/platform/env/SOME_TOKEN"),
+						build.WithSecrets(subject.SecretsVolume, map[string]string{"SOME_TOKEN": "some-secret-value"}),
+					)
+					h.AssertNil(t, err)
+					assertRunSucceeds(t, phase, &outBuf, &errBuf)
+					h.AssertContains(t, outBuf.String(), "[phase] file contents: [REDACTED]")
+					h.AssertNotContains(t, outBuf.String(), "some-secret-value")
+				})
+
+				it("does not provide the secrets to other phases", func() {
+					phase, err := subject.NewPhase("phase", build.WithArgs("read", "/platform/env/SOME_TOKEN"))
+					h.AssertNil(t, err)
+					h.AssertNotNil(t, phase.Run(context.TODO()))
+					h.AssertNil(t, phase.Cleanup())
+				})
+			})
+
+			when("the phase has a timeout", func() {
+				it.Before(func() {
+					h.AssertNil(t, subject.Cleanup())
+
+					var err error
+					subject, err = CreateFakeLifecycle(filepath.Join("testdata", "fake-app"), docker, fakes.NewFakeLogger(&outBuf), func(opts *build.LifecycleOptions) {
+						opts.PhaseTimeouts = map[string]time.Duration{"phase": time.Second}
+					})
+					h.AssertNil(t, err)
+				})
+
+				it("returns a timeout error naming the phase", func() {
+					phase, err := subject.NewPhase("phase", build.WithArgs("sleep", "1m"))
+					h.AssertNil(t, err)
+					defer phase.Cleanup()
+
+					err = phase.Run(context.TODO())
+					timeoutErr, ok := err.(*build.TimeoutError)
+					if !ok {
+						t.Fatalf("expected a timeout error, got: %v", err)
+					}
+					h.AssertEq(t, timeoutErr.Phase, "phase")
+					h.AssertEq(t, timeoutErr.Elapsed >= time.Second, true)
+					h.AssertContains(t, err.Error(), "phase 'phase' timed out after")
+				})
+
+				it("succeeds when the phase finishes in time", func() {
+					phase, err := subject.NewPhase("phase", build.WithArgs("sleep", "10ms"))
+					h.AssertNil(t, err)
+					assertRunSucceeds(t, phase, &outBuf, &errBuf)
+				})
+			})
+
+			it("returns a timeout error when the context deadline passes", func() {
+				phase, err := subject.NewPhase("phase", build.WithArgs("sleep", "1m"))
+				h.AssertNil(t, err)
+				defer phase.Cleanup()
+
+				ctx, cancel := context.WithTimeout(context.TODO(), time.Second)
+				defer cancel()
+				err = phase.Run(ctx)
+				_, ok := err.(*build.TimeoutError)
+				h.AssertEq(t, ok, true)
+			})
+
 			when("#WithArgs", func() {
 				it("runs the subject phase with args", func() {
 					phase, err := subject.NewPhase("phase", build.WithArgs("some", "args"))
@@ -252,7 +356,7 @@ func testPhase(t *testing.T, when spec.G, it spec.S) {
 					phase, err := subject.NewPhase(
 						"phase",
 						build.WithArgs("registry", registry.RepoName("packs/build:v3alpha2")),
-						build.WithRegistryAccess(),
+						build.WithRegistryAccess(authn.DefaultKeychain),
 					)
 					h.AssertNil(t, err)
 					assertRunSucceeds(t, phase, &outBuf, &errBuf)
@@ -308,7 +412,7 @@ func CreateFakeLifecycleImage(t *testing.T, dockerCli *client.Client, repoName s
 
 	wd, err := os.Getwd()
 	h.AssertNil(t, err)
-	buildContext := archive.ReadDirAsTar(filepath.Join(wd, "testdata", "fake-lifecycle"), "/", 0, 0, -1)
+	buildContext := archive.ReadDirAsTar(filepath.Join(wd, "testdata", "fake-lifecycle"), "/", 0, 0, -1, nil)
 
 	res, err := dockerCli.ImageBuild(ctx, buildContext, dockertypes.ImageBuildOptions{
 		Tags:        []string{repoName},
@@ -321,7 +425,7 @@ func CreateFakeLifecycleImage(t *testing.T, dockerCli *client.Client, repoName s
 	res.Body.Close()
 }
 
-func CreateFakeLifecycle(appDir string, docker *client.Client, logger logging.Logger) (*build.Lifecycle, error) {
+func CreateFakeLifecycle(appDir string, docker *client.Client, logger logging.Logger, ops ...func(*build.LifecycleOptions)) (*build.Lifecycle, error) {
 	subject := build.NewLifecycle(docker, logger)
 	builderImage, err := imgutil.NewLocalImage(repoName, docker)
 	if err != nil {
@@ -333,12 +437,16 @@ func CreateFakeLifecycle(appDir string, docker *client.Client, logger logging.Lo
 		return nil, err
 	}
 
-	subject.Setup(build.LifecycleOptions{
+	opts := build.LifecycleOptions{
 		AppPath:    appDir,
 		Builder:    bldr,
 		HTTPProxy:  "some-http-proxy",
 		HTTPSProxy: "some-https-proxy",
 		NoProxy:    "some-no-proxy",
-	})
+	}
+	for _, op := range ops {
+		op(&opts)
+	}
+	subject.Setup(opts)
 	return subject, nil
 }
@@ -3,6 +3,8 @@ package build
 import (
 	"context"
 	"fmt"
+
+	"github.com/buildpack/pack/cache"
 )
 
 const (
@@ -11,6 +13,7 @@ const (
 	cacheDir       = "/cache"
 	launchCacheDir = "/launch-cache"
 	platformDir    = "/platform"
+	platformEnvDir = "/platform/env"
 )
 
 func (l *Lifecycle) Detect(ctx context.Context) error {
@@ -20,6 +23,8 @@ func (l *Lifecycle) Detect(ctx context.Context) error {
 			"-app", appDir,
 			"-platform", platformDir,
 		),
+		WithBinds(l.volumes...),
+		WithSecrets(l.SecretsVolume, l.secrets),
 	)
 	if err != nil {
 		return err
@@ -28,20 +33,8 @@ func (l *Lifecycle) Detect(ctx context.Context) error {
 	return detect.Run(ctx)
 }
 
-func (l *Lifecycle) Restore(ctx context.Context, cacheName string) error {
-	var restore *Phase
-	var err error
-
-	restore, err = l.NewPhase(
-		"restorer",
-		WithDaemonAccess(),
-		WithArgs(
-			"-path", cacheDir,
-			"-layers", layersDir,
-		),
-		WithBinds(fmt.Sprintf("%s:%s", cacheName, cacheDir)),
-	)
-
+func (l *Lifecycle) Restore(ctx context.Context, buildCache Cache) error {
+	restore, err := l.NewPhase("restorer", l.cacheOps(buildCache)...)
 	if err != nil {
 		return err
 	}
@@ -70,7 +63,7 @@ func (l *Lifecycle) newAnalyze(repoName string, publish, clearCache bool) (*Phas
 	if publish {
 		return l.NewPhase(
 			"analyzer",
-			WithRegistryAccess(repoName),
+			WithRegistryAccess(l.keychain, repoName),
 			WithArgs(args...),
 		)
 	} else {
@@ -97,6 +90,8 @@ func (l *Lifecycle) Build(ctx context.Context) error {
 			"-app", appDir,
 			"-platform", platformDir,
 		),
+		WithBinds(l.volumes...),
+		WithSecrets(l.SecretsVolume, l.secrets),
 	)
 	if err != nil {
 		return err
@@ -105,8 +100,8 @@ func (l *Lifecycle) Build(ctx context.Context) error {
 	return build.Run(ctx)
 }
 
-func (l *Lifecycle) Export(ctx context.Context, repoName string, runImage string, publish bool, launchCacheName string) error {
-	export, err := l.newExport(repoName, runImage, publish, launchCacheName)
+func (l *Lifecycle) Export(ctx context.Context, repoName string, runImage string, publish bool, launchCacheName string, additionalTags []string) error {
+	export, err := l.newExport(repoName, runImage, publish, launchCacheName, additionalTags)
 	if err != nil {
 		return err
 	}
@@ -114,66 +109,69 @@ func (l *Lifecycle) Export(ctx context.Context, repoName string, runImage string
 	return export.Run(ctx)
 }
 
-func (l *Lifecycle) newExport(repoName, runImage string, publish bool, launchCacheName string) (*Phase, error) {
+func (l *Lifecycle) newExport(repoName, runImage string, publish bool, launchCacheName string, additionalTags []string) (*Phase, error) {
+	args := []string{
+		"-image", runImage,
+		"-layers", layersDir,
+		"-app", appDir,
+	}
+	tags := append([]string{repoName}, additionalTags...)
+
 	if publish {
 		return l.NewPhase(
 			"exporter",
-			WithRegistryAccess(repoName, runImage),
-			WithArgs(
-				"-image", runImage,
-				"-layers", layersDir,
-				"-app", appDir,
-				repoName,
-			),
+			WithRegistryAccess(l.keychain, append(tags, runImage)...),
+			WithArgs(append(args, tags...)...),
 		)
 	} else {
+		args = append(args, "-daemon")
 		if launchCacheName != "" {
 			return l.NewPhase(
 				"exporter",
 				WithDaemonAccess(),
-				WithArgs(
-					"-image", runImage,
-					"-layers", layersDir,
-					"-app", appDir,
-					"-daemon",
-					"-launch-cache", launchCacheDir,
-					repoName,
-				),
+				WithArgs(append(args, "-launch-cache", launchCacheDir)...),
+				WithArgs(tags...),
 				WithBinds(fmt.Sprintf("%s:%s", launchCacheName, launchCacheDir)),
 			)
 		} else {
 			return l.NewPhase(
 				"exporter",
 				WithDaemonAccess(),
-				WithArgs(
-					"-image", runImage,
-					"-layers", layersDir,
-					"-app", appDir,
-					"-daemon",
-					repoName,
-				),
+				WithArgs(append(args, tags...)...),
 			)
 		}
 	}
 }
 
-func (l *Lifecycle) Cache(ctx context.Context, cacheName string) error {
-	var cache *Phase
-	var err error
+func (l *Lifecycle) Cache(ctx context.Context, buildCache Cache) error {
+	cache, err := l.NewPhase("cacher", l.cacheOps(buildCache)...)
+	if err != nil {
+		return err
+	}
+	defer cache.Cleanup()
+	return cache.Run(ctx)
+}
 
-	cache, err = l.NewPhase(
-		"cacher",
-		WithDaemonAccess(),
+func (l *Lifecycle) cacheOps(buildCache Cache) []func(*Phase) (*Phase, error) {
+	if buildCache.Type() == cache.Image {
+		return []func(*Phase) (*Phase, error){
+			WithRegistryAccess(l.keychain, buildCache.Name()),
+			WithArgs(
+				"-image", buildCache.Name(),
+				"-layers", layersDir,
+			),
+		}
+	}
+	daemonAccess := WithDaemonAccess()
+	if !l.daemonAccess {
+		daemonAccess = WithRoot() // a volume cache needs no daemon, only the permissions to restore its layers
+	}
+	return []func(*Phase) (*Phase, error){
+		daemonAccess,
 		WithArgs(
 			"-path", cacheDir,
 			"-layers", layersDir,
 		),
-		WithBinds(fmt.Sprintf("%s:%s", cacheName, cacheDir)),
-	)
-
-	if err != nil {
-		return err
+		WithBinds(fmt.Sprintf("%s:%s", buildCache.Name(), cacheDir)),
 	}
-	defer cache.Cleanup()
-	return cache.Run(ctx)
 }
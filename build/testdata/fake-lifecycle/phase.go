@@ -4,9 +4,11 @@ import (
 	"context"
 	"fmt"
 	"io/ioutil"
+	"net"
 	"os"
 	"path/filepath"
 	"syscall"
+	"time"
 
 	"github.com/buildpack/lifecycle/image/auth"
 	"github.com/docker/docker/api/types"
@@ -44,6 +46,12 @@ func main() {
 	if len(os.Args) > 1 && os.Args[1] == "binds" {
 		testBinds()
 	}
+	if len(os.Args) > 1 && os.Args[1] == "network" {
+		testNetwork()
+	}
+	if len(os.Args) > 2 && os.Args[1] == "sleep" {
+		testSleep(os.Args[2])
+	}
 }
 
 func testWrite(filename, contents string) {
@@ -164,3 +172,25 @@ func readDir(dir string) {
 		}
 	}
 }
+
+func testNetwork() {
+	fmt.Println("network test")
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		fmt.Printf("failed to list interfaces: %s\n", err)
+		os.Exit(1)
+	}
+	for _, iface := range ifaces {
+		fmt.Printf("interface: %s\n", iface.Name)
+	}
+}
+
+func testSleep(duration string) {
+	fmt.Println("sleep test")
+	d, err := time.ParseDuration(duration)
+	if err != nil {
+		fmt.Printf("failed to parse duration: %s\n", err)
+		os.Exit(1)
+	}
+	time.Sleep(d)
+}
@@ -0,0 +1,206 @@
+package pack
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/buildpack/imgutil"
+
+	"github.com/buildpack/pack/blob"
+	"github.com/buildpack/pack/image"
+	"github.com/buildpack/pack/logging"
+)
+
+type BuildManyOptions struct {
+	Builds      []BuildOptions
+	Concurrency int // maximum number of builds run at once, defaults to 1
+}
+
+type AppBuildResult struct {
+	AppPath  string
+	Image    string
+	Result   *BuildResult // nil if the build failed
+	Err      error
+	Duration time.Duration
+}
+
+// BuildMany runs the given builds, at most opts.Concurrency at a time. Builder and run images are pulled once and
+// buildpacks are downloaded once for all builds that use them. The results are in the order of opts.Builds, and the
+// returned error is non-nil if any build failed.
+func (c *Client) BuildMany(ctx context.Context, opts BuildManyOptions) ([]AppBuildResult, error) {
+	concurrency := opts.Concurrency
+	if concurrency < 1 || c.newLifecycle == nil {
+		// without a lifecycle per build, builds have to share the client's lifecycle
+		concurrency = 1
+	}
+
+	fetcher := newSharedImageFetcher(c.imageFetcher)
+	downloader := newSharedDownloader(c.downloader)
+
+	var (
+		results = make([]AppBuildResult, len(opts.Builds))
+		sem     = make(chan struct{}, concurrency)
+		wg      sync.WaitGroup
+	)
+	for i, buildOpts := range opts.Builds {
+		results[i] = AppBuildResult{AppPath: buildOpts.AppPath, Image: buildOpts.Image}
+
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			results[i].Err = ctx.Err()
+			continue
+		}
+
+		wg.Add(1)
+		go func(result *AppBuildResult, buildOpts BuildOptions) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			appClient := c.forApp(buildLabel(buildOpts), fetcher, downloader)
+			start := time.Now()
+			result.Result, result.Err = appClient.Build(ctx, buildOpts)
+			result.Duration = time.Since(start)
+			if result.Result != nil {
+				result.Image = result.Result.Image
+			}
+		}(&results[i], buildOpts)
+	}
+	wg.Wait()
+
+	failed := 0
+	for _, result := range results {
+		if result.Err != nil {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return results, fmt.Errorf("%d of %d builds failed", failed, len(results))
+	}
+	return results, nil
+}
+
+// forApp returns a copy of the client for a single build of BuildMany or rebase of RebaseMany, logging with the given
+// prefix
+func (c *Client) forApp(label string, fetcher ImageFetcher, downloader Downloader) *Client {
+	appClient := *c
+	appClient.logger = logging.NewPrefixLogger(c.logger, label)
+	appClient.imageFetcher = fetcher
+	appClient.downloader = downloader
+	if c.newLifecycle != nil {
+		appClient.lifecycle = c.newLifecycle(appClient.logger)
+	}
+	return &appClient
+}
+
+func buildLabel(opts BuildOptions) string {
+	switch {
+	case opts.Image != "":
+		return opts.Image
+	case opts.AppPath != "":
+		return opts.AppPath
+	default:
+		return "."
+	}
+}
+
+type sharedResult struct {
+	done chan struct{}
+	blob blob.Blob
+	err  error
+}
+
+// sharedImageFetcher pulls each image at most once, later fetches of the image use the pulled image
+type sharedImageFetcher struct {
+	fetcher ImageFetcher
+	mu      sync.Mutex
+	pulls   map[string]*sharedResult
+}
+
+func newSharedImageFetcher(fetcher ImageFetcher) *sharedImageFetcher {
+	return &sharedImageFetcher{
+		fetcher: fetcher,
+		pulls:   map[string]*sharedResult{},
+	}
+}
+
+func (f *sharedImageFetcher) Fetch(ctx context.Context, name string, daemon bool, pullPolicy image.PullPolicy) (imgutil.Image, error) {
+	if !daemon || pullPolicy == image.PullNever {
+		return f.fetcher.Fetch(ctx, name, daemon, pullPolicy)
+	}
+
+	f.mu.Lock()
+	p, pulling := f.pulls[name]
+	if !pulling {
+		p = &sharedResult{done: make(chan struct{})}
+		f.pulls[name] = p
+	}
+	f.mu.Unlock()
+
+	if !pulling {
+		img, err := f.fetcher.Fetch(ctx, name, daemon, pullPolicy)
+		p.err = err
+		close(p.done)
+		return img, err
+	}
+
+	select {
+	case <-p.done:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	if p.err != nil {
+		// the failure may be particular to the build that pulled, e.g. its timeout, so try again
+		return f.fetcher.Fetch(ctx, name, daemon, pullPolicy)
+	}
+	return f.fetcher.Fetch(ctx, name, daemon, image.PullNever)
+}
+
+func (f *sharedImageFetcher) Layers(ctx context.Context, name string, daemon bool) ([]string, error) {
+	return f.fetcher.Layers(ctx, name, daemon)
+}
+
+func (f *sharedImageFetcher) ID(ctx context.Context, name string, daemon bool) (string, error) {
+	return f.fetcher.ID(ctx, name, daemon)
+}
+
+// sharedDownloader downloads each buildpack at most once
+type sharedDownloader struct {
+	downloader Downloader
+	mu         sync.Mutex
+	downloads  map[string]*sharedResult
+}
+
+func newSharedDownloader(downloader Downloader) *sharedDownloader {
+	return &sharedDownloader{
+		downloader: downloader,
+		downloads:  map[string]*sharedResult{},
+	}
+}
+
+func (d *sharedDownloader) Download(pathOrUri string, pullPolicy image.PullPolicy) (blob.Blob, error) {
+	d.mu.Lock()
+	download, ok := d.downloads[pathOrUri]
+	if !ok {
+		download = &sharedResult{done: make(chan struct{})}
+		d.downloads[pathOrUri] = download
+	}
+	d.mu.Unlock()
+
+	if !ok {
+		download.blob, download.err = d.downloader.Download(pathOrUri, pullPolicy)
+		close(download.done)
+		return download.blob, download.err
+	}
+
+	<-download.done
+	if download.err != nil {
+		// the failure may be particular to the build that downloaded, e.g. a network hiccup, so try again
+		return d.downloader.Download(pathOrUri, pullPolicy)
+	}
+	return download.blob, nil
+}
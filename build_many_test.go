@@ -0,0 +1,262 @@
+package pack
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Masterminds/semver"
+	"github.com/buildpack/imgutil/fakes"
+	"github.com/docker/docker/client"
+	"github.com/fatih/color"
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpack/pack/api"
+	"github.com/buildpack/pack/blob"
+	"github.com/buildpack/pack/build"
+	"github.com/buildpack/pack/builder"
+	"github.com/buildpack/pack/cache"
+	"github.com/buildpack/pack/image"
+	ifakes "github.com/buildpack/pack/internal/fakes"
+	"github.com/buildpack/pack/logging"
+	h "github.com/buildpack/pack/testhelpers"
+	"github.com/buildpack/pack/testmocks"
+)
+
+func TestBuildMany(t *testing.T) {
+	color.NoColor = true
+	spec.Run(t, "build-many", testBuildMany, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+// countingLifecycle records the builds it executes and the most builds executed at once
+type countingLifecycle struct {
+	mu         sync.Mutex
+	running    int
+	maxRunning int
+	appPaths   []string
+}
+
+func (l *countingLifecycle) Execute(ctx context.Context, opts build.LifecycleOptions) (*build.Result, error) {
+	l.mu.Lock()
+	l.running++
+	if l.running > l.maxRunning {
+		l.maxRunning = l.running
+	}
+	l.appPaths = append(l.appPaths, opts.AppPath)
+	l.mu.Unlock()
+
+	time.Sleep(50 * time.Millisecond)
+
+	l.mu.Lock()
+	l.running--
+	l.mu.Unlock()
+	return &build.Result{}, nil
+}
+
+func testBuildMany(t *testing.T, when spec.G, it spec.S) {
+	var (
+		subject          *Client
+		fakeImageFetcher *ifakes.FakeImageFetcher
+		lifecycle        *countingLifecycle
+		appDirs          []string
+		tmpDir           string
+		outBuf           bytes.Buffer
+	)
+
+	it.Before(func() {
+		var err error
+
+		tmpDir, err = ioutil.TempDir("", "build-many-test")
+		h.AssertNil(t, err)
+
+		fakeImageFetcher = ifakes.NewFakeImageFetcher()
+		lifecycle = &countingLifecycle{}
+
+		runImage := fakes.NewImage("default/run", "", "")
+		h.AssertNil(t, runImage.SetLabel("io.buildpacks.stack.id", "some.stack.id"))
+		fakeImageFetcher.LocalImages[runImage.Name()] = runImage
+
+		appDirs = nil
+		for i := 1; i <= 3; i++ {
+			// builders are modified by each build, so each app gets its own
+			builderImage := ifakes.NewFakeBuilderImage(t,
+				fmt.Sprintf("example.com/builder-%d:tag", i),
+				"some.stack.id",
+				"1234",
+				"5678",
+				builder.Metadata{
+					Stack: builder.StackMetadata{
+						RunImage: builder.RunImageMetadata{Image: "default/run"},
+					},
+					Lifecycle: builder.LifecycleMetadata{
+						LifecycleInfo: builder.LifecycleInfo{
+							Version: &builder.Version{Version: *semver.MustParse("0.3.0")},
+						},
+						API: builder.LifecycleAPI{
+							BuildpackVersion: api.MustParse("0.3"),
+							PlatformVersion:  api.MustParse("0.2"),
+						},
+					},
+				},
+			)
+			fakeImageFetcher.LocalImages[builderImage.Name()] = builderImage
+
+			appImage := fakes.NewImage(fmt.Sprintf("example.com/app-%d:latest", i), "", "")
+			h.AssertNil(t, appImage.SetLabel("io.buildpacks.lifecycle.metadata", `{}`))
+			fakeImageFetcher.LocalImages[appImage.Name()] = appImage
+			fakeImageFetcher.ImageIDs[appImage.Name()] = fmt.Sprintf("sha256:app-%d-id", i)
+
+			appDir := filepath.Join(tmpDir, fmt.Sprintf("app-%d", i))
+			h.AssertNil(t, os.MkdirAll(appDir, 0755))
+			appDirs = append(appDirs, appDir)
+		}
+
+		docker, err := client.NewClientWithOpts(client.FromEnv, client.WithVersion("1.38"))
+		h.AssertNil(t, err)
+
+		logger := ifakes.NewFakeLogger(&outBuf)
+		subject = &Client{
+			logger:       logger,
+			imageFetcher: fakeImageFetcher,
+			downloader:   blob.NewDownloader(logger, filepath.Join(tmpDir, "dl-cache"), nil),
+			lifecycle:    lifecycle,
+			newLifecycle: func(logging.Logger) Lifecycle {
+				return lifecycle
+			},
+			docker:     docker,
+			cacheUsage: cache.NewUsage(filepath.Join(tmpDir, "cache-usage.toml")),
+		}
+	})
+
+	it.After(func() {
+		os.RemoveAll(tmpDir)
+	})
+
+	buildsOf := func(n int) []BuildOptions {
+		var builds []BuildOptions
+		for i := 1; i <= n; i++ {
+			builds = append(builds, BuildOptions{
+				AppPath:    appDirs[i-1],
+				Image:      fmt.Sprintf("example.com/app-%d", i),
+				Builder:    fmt.Sprintf("example.com/builder-%d:tag", i),
+				PullPolicy: image.PullNever,
+			})
+		}
+		return builds
+	}
+
+	when("#BuildMany", func() {
+		it("builds each app and reports the results in order", func() {
+			results, err := subject.BuildMany(context.TODO(), BuildManyOptions{Builds: buildsOf(3), Concurrency: 3})
+			h.AssertNil(t, err)
+
+			h.AssertEq(t, len(results), 3)
+			for i, result := range results {
+				h.AssertNil(t, result.Err)
+				h.AssertEq(t, result.AppPath, appDirs[i])
+				h.AssertEq(t, result.Image, fmt.Sprintf("example.com/app-%d:latest", i+1))
+				h.AssertEq(t, result.Result.Builder, fmt.Sprintf("example.com/builder-%d:tag", i+1))
+			}
+			h.AssertEq(t, len(lifecycle.appPaths), 3)
+		})
+
+		it("runs no more builds at once than the concurrency", func() {
+			_, err := subject.BuildMany(context.TODO(), BuildManyOptions{Builds: buildsOf(3), Concurrency: 2})
+			h.AssertNil(t, err)
+
+			h.AssertEq(t, lifecycle.maxRunning, 2)
+		})
+
+		it("runs one build at a time by default", func() {
+			_, err := subject.BuildMany(context.TODO(), BuildManyOptions{Builds: buildsOf(3)})
+			h.AssertNil(t, err)
+
+			h.AssertEq(t, lifecycle.maxRunning, 1)
+		})
+
+		it("reports the builds that failed and continues with the others", func() {
+			builds := buildsOf(2)
+			builds[0].Image = "InvalidName"
+
+			results, err := subject.BuildMany(context.TODO(), BuildManyOptions{Builds: builds, Concurrency: 2})
+			h.AssertError(t, err, "1 of 2 builds failed")
+
+			h.AssertError(t, results[0].Err, "invalid image name 'InvalidName'")
+			h.AssertEq(t, results[0].Result == nil, true)
+			h.AssertNil(t, results[1].Err)
+			h.AssertEq(t, lifecycle.appPaths, []string{appDirs[1]})
+		})
+
+		it("prefixes the output of each build with its image", func() {
+			_, err := subject.BuildMany(context.TODO(), BuildManyOptions{Builds: buildsOf(1)})
+			h.AssertNil(t, err)
+
+			h.AssertContains(t, outBuf.String(), "[example.com/app-1] ")
+		})
+	})
+
+	when("sharing images and buildpacks", func() {
+		var mockController *gomock.Controller
+
+		it.Before(func() {
+			mockController = gomock.NewController(t)
+		})
+
+		it.After(func() {
+			mockController.Finish()
+		})
+
+		it("pulls an image once and then uses the pulled image", func() {
+			img := fakes.NewImage("some/builder", "", "")
+			mockFetcher := testmocks.NewMockImageFetcher(mockController)
+			mockFetcher.EXPECT().Fetch(gomock.Any(), "some/builder", true, image.PullAlways).Return(img, nil).Times(1)
+			mockFetcher.EXPECT().Fetch(gomock.Any(), "some/builder", true, image.PullNever).Return(img, nil).Times(2)
+
+			fetcher := newSharedImageFetcher(mockFetcher)
+			for i := 0; i < 3; i++ {
+				fetched, err := fetcher.Fetch(context.TODO(), "some/builder", true, image.PullAlways)
+				h.AssertNil(t, err)
+				h.AssertEq(t, fetched.Name(), "some/builder")
+			}
+		})
+
+		it("downloads a buildpack once", func() {
+			bp := blob.NewBlob(filepath.Join("testdata", "buildpack"))
+			mockDownloader := testmocks.NewMockDownloader(mockController)
+			mockDownloader.EXPECT().Download("https://example.com/bp.tgz", image.PullAlways).Return(bp, nil).Times(1)
+
+			downloader := newSharedDownloader(mockDownloader)
+			for i := 0; i < 2; i++ {
+				downloaded, err := downloader.Download("https://example.com/bp.tgz", image.PullAlways)
+				h.AssertNil(t, err)
+				h.AssertEq(t, downloaded == bp, true)
+			}
+		})
+
+		it("downloads a buildpack again when the download failed", func() {
+			bp := blob.NewBlob(filepath.Join("testdata", "buildpack"))
+			mockDownloader := testmocks.NewMockDownloader(mockController)
+			gomock.InOrder(
+				mockDownloader.EXPECT().Download("https://example.com/bp.tgz", image.PullAlways).Return(nil, errors.New("some-error")),
+				mockDownloader.EXPECT().Download("https://example.com/bp.tgz", image.PullAlways).Return(bp, nil),
+			)
+
+			downloader := newSharedDownloader(mockDownloader)
+			_, err := downloader.Download("https://example.com/bp.tgz", image.PullAlways)
+			h.AssertError(t, err, "some-error")
+
+			downloaded, err := downloader.Download("https://example.com/bp.tgz", image.PullAlways)
+			h.AssertNil(t, err)
+			h.AssertEq(t, downloaded == bp, true)
+		})
+	})
+}
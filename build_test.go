@@ -4,6 +4,7 @@ import (
 	"archive/tar"
 	"bytes"
 	"context"
+	"encoding/base64"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -19,14 +20,21 @@ import (
 	"github.com/buildpack/imgutil/fakes"
 	"github.com/docker/docker/client"
 	"github.com/fatih/color"
+	"github.com/google/go-containerregistry/pkg/name"
 	"github.com/onsi/gomega/ghttp"
+	"github.com/pkg/errors"
 	"github.com/sclevine/spec"
 	"github.com/sclevine/spec/report"
 
 	"github.com/buildpack/pack/api"
 	"github.com/buildpack/pack/blob"
+	"github.com/buildpack/pack/build"
 	"github.com/buildpack/pack/builder"
+	"github.com/buildpack/pack/cache"
+	"github.com/buildpack/pack/config"
+	"github.com/buildpack/pack/image"
 	ifakes "github.com/buildpack/pack/internal/fakes"
+	"github.com/buildpack/pack/internal/registry"
 	h "github.com/buildpack/pack/testhelpers"
 )
 
@@ -47,6 +55,7 @@ func testBuild(t *testing.T, when spec.G, it spec.S) {
 		fakeDefaultRunImage   *fakes.Image
 		fakeMirror1           *fakes.Image
 		fakeMirror2           *fakes.Image
+		fakeAppImage          *fakes.Image
 		tmpDir                string
 		outBuf                bytes.Buffer
 	)
@@ -110,6 +119,24 @@ func testBuild(t *testing.T, when spec.G, it spec.S) {
 		h.AssertNil(t, fakeMirror2.SetLabel("io.buildpacks.stack.id", defaultBuilderStackID))
 		fakeImageFetcher.LocalImages[fakeMirror2.Name()] = fakeMirror2
 
+		fakeAppImage = fakes.NewImage("some/app", "", "some-app-digest")
+		h.AssertNil(t, fakeAppImage.SetLabel("io.buildpacks.lifecycle.metadata", `{
+  "buildpacks": [
+    {"key": "some.bp", "version": "1.2.3"},
+    {"key": "other.bp", "version": "4.5.6"}
+  ]
+}`))
+		for _, name := range []string{
+			"index.docker.io/some/app:latest",
+			"example.com/some/repo:tag",
+			"registry1.example.com/some/app:latest",
+			"registry2.example.com/some/app:latest",
+		} {
+			fakeImageFetcher.LocalImages[name] = fakeAppImage
+			fakeImageFetcher.RemoteImages[name] = fakeAppImage
+			fakeImageFetcher.ImageIDs[name] = "sha256:some-app-id"
+		}
+
 		docker, err := client.NewClientWithOpts(client.FromEnv, client.WithVersion("1.38"))
 		h.AssertNil(t, err)
 
@@ -121,9 +148,10 @@ func testBuild(t *testing.T, when spec.G, it spec.S) {
 		subject = &Client{
 			logger:       logger,
 			imageFetcher: fakeImageFetcher,
-			downloader:   blob.NewDownloader(logger, dlCacheDir),
+			downloader:   blob.NewDownloader(logger, dlCacheDir, nil),
 			lifecycle:    fakeLifecycle,
 			docker:       docker,
+			cacheUsage:   cache.NewUsage(filepath.Join(tmpDir, "cache-usage.toml")),
 		}
 	})
 
@@ -132,43 +160,138 @@ func testBuild(t *testing.T, when spec.G, it spec.S) {
 		fakeDefaultRunImage.Cleanup()
 		fakeMirror1.Cleanup()
 		fakeMirror2.Cleanup()
+		fakeAppImage.Cleanup()
 		os.RemoveAll(tmpDir)
 	})
 
 	when("#Build", func() {
+		when("it succeeds", func() {
+			it.Before(func() {
+				fakeLifecycle.Result = build.Result{
+					BuildCache:  "some-build-cache",
+					LaunchCache: "some-launch-cache",
+					Phases: []build.PhaseTiming{
+						{Name: "detector", Duration: time.Second},
+						{Name: "builder", Duration: time.Minute},
+					},
+				}
+			})
+
+			it("returns the built image and its digest", func() {
+				result, err := subject.Build(context.TODO(), BuildOptions{
+					Image:   "some/app",
+					Builder: builderName,
+				})
+				h.AssertNil(t, err)
+				h.AssertEq(t, result.Image, "index.docker.io/some/app:latest")
+				h.AssertEq(t, result.ImageDigest, "some-app-digest")
+
+				args := fakeImageFetcher.FetchCalls["index.docker.io/some/app:latest"]
+				h.AssertEq(t, args.Daemon, true)
+				h.AssertEq(t, args.PullPolicy, image.PullNever)
+			})
+
+			it("returns the image ID when the image is saved to the daemon", func() {
+				result, err := subject.Build(context.TODO(), BuildOptions{
+					Image:   "some/app",
+					Builder: builderName,
+				})
+				h.AssertNil(t, err)
+				h.AssertEq(t, result.ImageID, "sha256:some-app-id")
+			})
+
+			it("returns the builder and run image", func() {
+				result, err := subject.Build(context.TODO(), BuildOptions{
+					Image:   "some/app",
+					Builder: builderName,
+				})
+				h.AssertNil(t, err)
+				h.AssertEq(t, result.Builder, builderName)
+				h.AssertEq(t, result.RunImage, "default/run")
+			})
+
+			it("returns the buildpack group recorded on the image", func() {
+				result, err := subject.Build(context.TODO(), BuildOptions{
+					Image:   "some/app",
+					Builder: builderName,
+				})
+				h.AssertNil(t, err)
+				h.AssertEq(t, result.Buildpacks, []builder.BuildpackInfo{
+					{ID: "some.bp", Version: "1.2.3"},
+					{ID: "other.bp", Version: "4.5.6"},
+				})
+			})
+
+			it("returns the caches and phase timings from lifecycle", func() {
+				result, err := subject.Build(context.TODO(), BuildOptions{
+					Image:   "some/app",
+					Builder: builderName,
+				})
+				h.AssertNil(t, err)
+				h.AssertEq(t, result.BuildCache, "some-build-cache")
+				h.AssertEq(t, result.LaunchCache, "some-launch-cache")
+				h.AssertEq(t, result.PhaseTimings, fakeLifecycle.Result.Phases)
+			})
+
+			it("fetches the image from the registry when publishing", func() {
+				fakeImageFetcher.RemoteImages[fakeDefaultRunImage.Name()] = fakeDefaultRunImage
+				result, err := subject.Build(context.TODO(), BuildOptions{
+					Image:   "some/app",
+					Builder: builderName,
+					Publish: true,
+				})
+				h.AssertNil(t, err)
+				h.AssertEq(t, result.ImageID, "")
+
+				args := fakeImageFetcher.FetchCalls["index.docker.io/some/app:latest"]
+				h.AssertEq(t, args.Daemon, false)
+			})
+		})
+
+		when("the built image cannot be found", func() {
+			it("errors", func() {
+				delete(fakeImageFetcher.LocalImages, "index.docker.io/some/app:latest")
+				delete(fakeImageFetcher.RemoteImages, "index.docker.io/some/app:latest")
+
+				_, err := subject.Build(context.TODO(), BuildOptions{
+					Image:   "some/app",
+					Builder: builderName,
+				})
+				h.AssertError(t, err, "failed to fetch built image 'index.docker.io/some/app:latest'")
+			})
+		})
+
 		when("Image option", func() {
 			it("is required", func() {
-				h.AssertError(t, subject.Build(context.TODO(), BuildOptions{
+				_, err := subject.Build(context.TODO(), BuildOptions{
 					Image:   "",
 					Builder: builderName,
-				}),
-					"invalid image name ''",
-				)
+				})
+				h.AssertError(t, err, "invalid image name ''")
 			})
 
 			it("must be a valid image reference", func() {
-				h.AssertError(t, subject.Build(context.TODO(), BuildOptions{
+				_, err := subject.Build(context.TODO(), BuildOptions{
 					Image:   "not@valid",
 					Builder: builderName,
-				}),
-					"invalid image name 'not@valid'",
-				)
+				})
+				h.AssertError(t, err, "invalid image name 'not@valid'")
 			})
 
 			it("must be a valid tag reference", func() {
-				h.AssertError(t, subject.Build(context.TODO(), BuildOptions{
+				_, err := subject.Build(context.TODO(), BuildOptions{
 					Image:   "registry.com/my/image@sha256:954e1f01e80ce09d0887ff6ea10b13a812cb01932a0781d6b0cc23f743a874fd",
 					Builder: builderName,
-				}),
-					"invalid image name 'registry.com/my/image@sha256:954e1f01e80ce09d0887ff6ea10b13a812cb01932a0781d6b0cc23f743a874fd'",
-				)
+				})
+				h.AssertError(t, err, "invalid image name 'registry.com/my/image@sha256:954e1f01e80ce09d0887ff6ea10b13a812cb01932a0781d6b0cc23f743a874fd'")
 			})
 
 			it("lifecycle receives resolved reference", func() {
-				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+				_, err := subject.Build(context.TODO(), BuildOptions{
 					Builder: builderName,
 					Image:   "example.com/some/repo:tag",
-				}))
+				})
+				h.AssertNil(t, err)
 				h.AssertEq(t, fakeLifecycle.Opts.Image.Context().RegistryStr(), "example.com")
 				h.AssertEq(t, fakeLifecycle.Opts.Image.Context().RepositoryStr(), "some/repo")
 				h.AssertEq(t, fakeLifecycle.Opts.Image.Identifier(), "tag")
@@ -177,10 +300,11 @@ func testBuild(t *testing.T, when spec.G, it spec.S) {
 
 		when("AppDir option", func() {
 			it("defaults to the current working directory", func() {
-				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+				_, err := subject.Build(context.TODO(), BuildOptions{
 					Image:   "some/app",
 					Builder: builderName,
-				}))
+				})
+				h.AssertNil(t, err)
 
 				wd, err := os.Getwd()
 				h.AssertNil(t, err)
@@ -196,7 +320,7 @@ func testBuild(t *testing.T, when spec.G, it spec.S) {
 				appPath := appPath
 
 				it(fmt.Sprintf("supports %s files", fileDesc), func() {
-					err := subject.Build(context.TODO(), BuildOptions{
+					_, err := subject.Build(context.TODO(), BuildOptions{
 						Image:   "some/app",
 						Builder: builderName,
 						AppPath: appPath,
@@ -215,7 +339,7 @@ func testBuild(t *testing.T, when spec.G, it spec.S) {
 				errMessage := testData[0]
 
 				it(fmt.Sprintf("does NOT support %s files", fileDesc), func() {
-					err := subject.Build(context.TODO(), BuildOptions{
+					_, err := subject.Build(context.TODO(), BuildOptions{
 						Image:   "some/app",
 						Builder: builderName,
 						AppPath: appPath,
@@ -226,11 +350,12 @@ func testBuild(t *testing.T, when spec.G, it spec.S) {
 			}
 
 			it("resolves the absolute path", func() {
-				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+				_, err := subject.Build(context.TODO(), BuildOptions{
 					Image:   "some/app",
 					Builder: builderName,
 					AppPath: filepath.Join("testdata", "some-app"),
-				}))
+				})
+				h.AssertNil(t, err)
 				absPath, err := filepath.Abs(filepath.Join("testdata", "some-app"))
 				h.AssertNil(t, err)
 				h.AssertEq(t, fakeLifecycle.Opts.AppPath, absPath)
@@ -266,11 +391,12 @@ func testBuild(t *testing.T, when spec.G, it spec.S) {
 					relLink := filepath.Join(tmpDir, "some-app.link")
 					h.AssertNil(t, os.Symlink(filepath.Join(".", appDirName), relLink))
 
-					h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+					_, err := subject.Build(context.TODO(), BuildOptions{
 						Image:   "some/app",
 						Builder: builderName,
 						AppPath: relLink,
-					}))
+					})
+					h.AssertNil(t, err)
 
 					h.AssertEq(t, fakeLifecycle.Opts.AppPath, absoluteAppDir)
 				})
@@ -279,11 +405,12 @@ func testBuild(t *testing.T, when spec.G, it spec.S) {
 					relLink := filepath.Join(tmpDir, "some-app.link")
 					h.AssertNil(t, os.Symlink(absoluteAppDir, relLink))
 
-					h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+					_, err := subject.Build(context.TODO(), BuildOptions{
 						Image:   "some/app",
 						Builder: builderName,
 						AppPath: relLink,
-					}))
+					})
+					h.AssertNil(t, err)
 
 					h.AssertEq(t, fakeLifecycle.Opts.AppPath, absoluteAppDir)
 				})
@@ -298,11 +425,12 @@ func testBuild(t *testing.T, when spec.G, it spec.S) {
 					h.AssertNil(t, os.Symlink(linkRef1, absoluteLink1))
 					h.AssertNil(t, os.Symlink(linkRef2, symbolicLink))
 
-					h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+					_, err := subject.Build(context.TODO(), BuildOptions{
 						Image:   "some/app",
 						Builder: builderName,
 						AppPath: symbolicLink,
-					}))
+					})
+					h.AssertNil(t, err)
 
 					h.AssertEq(t, fakeLifecycle.Opts.AppPath, absoluteAppDir)
 				})
@@ -311,11 +439,10 @@ func testBuild(t *testing.T, when spec.G, it spec.S) {
 
 		when("Builder option", func() {
 			it("builder is required", func() {
-				h.AssertError(t, subject.Build(context.TODO(), BuildOptions{
+				_, err := subject.Build(context.TODO(), BuildOptions{
 					Image: "some/app",
-				}),
-					"invalid builder ''",
-				)
+				})
+				h.AssertError(t, err, "invalid builder ''")
 			})
 
 			when("the builder name is provided", func() {
@@ -351,10 +478,11 @@ func testBuild(t *testing.T, when spec.G, it spec.S) {
 				})
 
 				it("it uses the provided builder", func() {
-					h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+					_, err := subject.Build(context.TODO(), BuildOptions{
 						Image:   "some/app",
 						Builder: builderName,
-					}))
+					})
+					h.AssertNil(t, err)
 					h.AssertEq(t, fakeLifecycle.Opts.Builder.Name(), customBuilderImage.Name())
 				})
 			})
@@ -380,11 +508,12 @@ func testBuild(t *testing.T, when spec.G, it spec.S) {
 				})
 
 				it("uses the provided image", func() {
-					h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+					_, err := subject.Build(context.TODO(), BuildOptions{
 						Image:    "some/app",
 						Builder:  builderName,
 						RunImage: "custom/run",
-					}))
+					})
+					h.AssertNil(t, err)
 					h.AssertEq(t, fakeLifecycle.Opts.RunImage, "custom/run")
 				})
 			})
@@ -395,39 +524,41 @@ func testBuild(t *testing.T, when spec.G, it spec.S) {
 				})
 
 				it("errors", func() {
-					h.AssertError(t, subject.Build(context.TODO(), BuildOptions{
+					_, err := subject.Build(context.TODO(), BuildOptions{
 						Image:    "some/app",
 						Builder:  builderName,
 						RunImage: "custom/run",
-					}),
-						"invalid run-image 'custom/run': run-image stack id 'other.stack' does not match builder stack 'some.stack.id'",
-					)
+					})
+					h.AssertError(t, err, "invalid run-image 'custom/run': run-image stack id 'other.stack' does not match builder stack 'some.stack.id'")
 				})
 			})
 
 			when("run image is not supplied", func() {
 				when("there are no locally configured mirrors", func() {
 					it("chooses the best mirror from the builder", func() {
-						h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+						_, err := subject.Build(context.TODO(), BuildOptions{
 							Image:   "some/app",
 							Builder: builderName,
-						}))
+						})
+						h.AssertNil(t, err)
 						h.AssertEq(t, fakeLifecycle.Opts.RunImage, "default/run")
 					})
 
 					it("chooses the best mirror from the builder", func() {
-						h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+						_, err := subject.Build(context.TODO(), BuildOptions{
 							Image:   "registry1.example.com/some/app",
 							Builder: builderName,
-						}))
+						})
+						h.AssertNil(t, err)
 						h.AssertEq(t, fakeLifecycle.Opts.RunImage, "registry1.example.com/run/mirror")
 					})
 
 					it("chooses the best mirror from the builder", func() {
-						h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+						_, err := subject.Build(context.TODO(), BuildOptions{
 							Image:   "registry2.example.com/some/app",
 							Builder: builderName,
-						}))
+						})
+						h.AssertNil(t, err)
 						h.AssertEq(t, fakeLifecycle.Opts.RunImage, "registry2.example.com/run/mirror")
 					})
 				})
@@ -456,36 +587,39 @@ func testBuild(t *testing.T, when spec.G, it spec.S) {
 					})
 
 					it("prefers user provided mirrors", func() {
-						h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+						_, err := subject.Build(context.TODO(), BuildOptions{
 							Image:   "some/app",
 							Builder: builderName,
 							AdditionalMirrors: map[string][]string{
 								"default/run": {"local/mirror", "registry1.example.com/local/mirror"},
 							},
-						}))
+						})
+						h.AssertNil(t, err)
 						h.AssertEq(t, fakeLifecycle.Opts.RunImage, "local/mirror")
 					})
 
 					it("choose the correct user provided mirror for the registry", func() {
-						h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+						_, err := subject.Build(context.TODO(), BuildOptions{
 							Image:   "registry1.example.com/some/app",
 							Builder: builderName,
 							AdditionalMirrors: map[string][]string{
 								"default/run": {"local/mirror", "registry1.example.com/local/mirror"},
 							},
-						}))
+						})
+						h.AssertNil(t, err)
 						h.AssertEq(t, fakeLifecycle.Opts.RunImage, "registry1.example.com/local/mirror")
 					})
 
 					when("there is no user provided mirror for the registry", func() {
 						it("chooses from builder mirrors", func() {
-							h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+							_, err := subject.Build(context.TODO(), BuildOptions{
 								Image:   "registry2.example.com/some/app",
 								Builder: builderName,
 								AdditionalMirrors: map[string][]string{
 									"default/run": {"local/mirror", "registry1.example.com/local/mirror"},
 								},
-							}))
+							})
+							h.AssertNil(t, err)
 							h.AssertEq(t, fakeLifecycle.Opts.RunImage, "registry2.example.com/run/mirror")
 						})
 					})
@@ -495,31 +629,345 @@ func testBuild(t *testing.T, when spec.G, it spec.S) {
 
 		when("ClearCache option", func() {
 			it("passes it through to lifecycle", func() {
-				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+				_, err := subject.Build(context.TODO(), BuildOptions{
 					Image:      "some/app",
 					Builder:    builderName,
 					ClearCache: true,
-				}))
+				})
+				h.AssertNil(t, err)
 				h.AssertEq(t, fakeLifecycle.Opts.ClearCache, true)
 			})
 
 			it("defaults to false", func() {
-				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+				_, err := subject.Build(context.TODO(), BuildOptions{
 					Image:   "some/app",
 					Builder: builderName,
-				}))
+				})
+				h.AssertNil(t, err)
 				h.AssertEq(t, fakeLifecycle.Opts.ClearCache, false)
 			})
 		})
 
+		when("cache usage", func() {
+			it("records the use of the image's cache volumes", func() {
+				_, err := subject.Build(context.TODO(), BuildOptions{
+					Image:   "some/app",
+					Builder: builderName,
+				})
+				h.AssertNil(t, err)
+
+				lastUsed, err := subject.cacheUsage.LastUsed()
+				h.AssertNil(t, err)
+				h.AssertEq(t, len(lastUsed), 2)
+			})
+
+			it("does not record a build cache volume when using a cache image", func() {
+				fakeImageFetcher.RemoteImages[fakeDefaultRunImage.Name()] = fakeDefaultRunImage
+				_, err := subject.Build(context.TODO(), BuildOptions{
+					Image:      "some/app",
+					Builder:    builderName,
+					Publish:    true,
+					CacheImage: "some/cache-image",
+				})
+				h.AssertNil(t, err)
+
+				lastUsed, err := subject.cacheUsage.LastUsed()
+				h.AssertNil(t, err)
+				h.AssertEq(t, len(lastUsed), 1)
+			})
+		})
+
+		when("AdditionalTags option", func() {
+			it("passes the tags through to lifecycle", func() {
+				result, err := subject.Build(context.TODO(), BuildOptions{
+					Image:          "some/app",
+					Builder:        builderName,
+					AdditionalTags: []string{"some/app:v1", "example.com/some/repo:tag"},
+				})
+				h.AssertNil(t, err)
+				h.AssertEq(t, fakeLifecycle.Opts.AdditionalTags, []string{"index.docker.io/some/app:v1", "example.com/some/repo:tag"})
+				h.AssertEq(t, result.AdditionalTags, []string{"index.docker.io/some/app:v1", "example.com/some/repo:tag"})
+			})
+
+			it("passes the tags through when publishing", func() {
+				fakeImageFetcher.RemoteImages[fakeDefaultRunImage.Name()] = fakeDefaultRunImage
+				_, err := subject.Build(context.TODO(), BuildOptions{
+					Image:          "some/app",
+					Builder:        builderName,
+					Publish:        true,
+					AdditionalTags: []string{"some/app:v1"},
+				})
+				h.AssertNil(t, err)
+				h.AssertEq(t, fakeLifecycle.Opts.AdditionalTags, []string{"index.docker.io/some/app:v1"})
+			})
+
+			it("errors when a tag is not a tag reference", func() {
+				_, err := subject.Build(context.TODO(), BuildOptions{
+					Image:          "some/app",
+					Builder:        builderName,
+					AdditionalTags: []string{"some/app@sha256:9ab5e0a2bcd2e1bd6c6ff56ba0e3af8bb5f67fd5e2a23b0fbd1e4b6e5a0b2a7a"},
+				})
+				h.AssertError(t, err, "invalid additional tag 'some/app@sha256:9ab5e0a2bcd2e1bd6c6ff56ba0e3af8bb5f67fd5e2a23b0fbd1e4b6e5a0b2a7a'")
+			})
+
+			it("errors when a tag is not valid", func() {
+				_, err := subject.Build(context.TODO(), BuildOptions{
+					Image:          "some/app",
+					Builder:        builderName,
+					AdditionalTags: []string{"not@valid"},
+				})
+				h.AssertError(t, err, "invalid additional tag 'not@valid'")
+			})
+		})
+
+		when("CacheImage option", func() {
+			it("uses a volume cache by default", func() {
+				_, err := subject.Build(context.TODO(), BuildOptions{
+					Image:   "some/app",
+					Builder: builderName,
+				})
+				h.AssertNil(t, err)
+				h.AssertEq(t, fakeLifecycle.Opts.CacheImage == nil, true)
+			})
+
+			it("passes the cache image through to lifecycle", func() {
+				fakeImageFetcher.RemoteImages[fakeDefaultRunImage.Name()] = fakeDefaultRunImage
+				_, err := subject.Build(context.TODO(), BuildOptions{
+					Image:      "some/app",
+					Builder:    builderName,
+					Publish:    true,
+					CacheImage: "some/cache-image",
+				})
+				h.AssertNil(t, err)
+				h.AssertEq(t, fakeLifecycle.Opts.CacheImage.Name(), "index.docker.io/some/cache-image:latest")
+			})
+
+			it("errors when not publishing", func() {
+				_, err := subject.Build(context.TODO(), BuildOptions{
+					Image:      "some/app",
+					Builder:    builderName,
+					CacheImage: "some/cache-image",
+				})
+				h.AssertError(t, err, "cache image can only be used when publishing")
+			})
+
+			it("errors when the cache image is not a tag reference", func() {
+				_, err := subject.Build(context.TODO(), BuildOptions{
+					Image:      "some/app",
+					Builder:    builderName,
+					Publish:    true,
+					CacheImage: "some/cache-image@sha256:9ab5e0a2bcd2e1bd6c6ff56ba0e3af8bb5f67fd5e2a23b0fbd1e4b6e5a0b2a7a",
+				})
+				h.AssertError(t, err, "is not a tag reference")
+			})
+		})
+
+		when("Exclude option", func() {
+			var appDir string
+
+			it.Before(func() {
+				var err error
+				appDir, err = ioutil.TempDir(tmpDir, "exclude-app")
+				h.AssertNil(t, err)
+			})
+
+			it("does not exclude anything by default", func() {
+				_, err := subject.Build(context.TODO(), BuildOptions{
+					Image:   "some/app",
+					Builder: builderName,
+					AppPath: appDir,
+				})
+				h.AssertNil(t, err)
+				h.AssertEq(t, fakeLifecycle.Opts.Exclude == nil, true)
+			})
+
+			it("passes the patterns through to lifecycle", func() {
+				_, err := subject.Build(context.TODO(), BuildOptions{
+					Image:   "some/app",
+					Builder: builderName,
+					AppPath: appDir,
+					Exclude: []string{"*.log", "build/"},
+				})
+				h.AssertNil(t, err)
+				h.AssertEq(t, fakeLifecycle.Opts.Exclude("some/dir/file.log", false), true)
+				h.AssertEq(t, fakeLifecycle.Opts.Exclude("build", true), true)
+				h.AssertEq(t, fakeLifecycle.Opts.Exclude("build", false), false)
+				h.AssertEq(t, fakeLifecycle.Opts.Exclude("src/main.go", false), false)
+			})
+
+			when("the app has a .packignore file", func() {
+				it.Before(func() {
+					h.AssertNil(t, ioutil.WriteFile(filepath.Join(appDir, ".packignore"), []byte("# comment\n.git\n*.secret\n"), 0644))
+				})
+
+				it("excludes the files matched by the file", func() {
+					_, err := subject.Build(context.TODO(), BuildOptions{
+						Image:   "some/app",
+						Builder: builderName,
+						AppPath: appDir,
+					})
+					h.AssertNil(t, err)
+					h.AssertEq(t, fakeLifecycle.Opts.Exclude(".git/config", false), true)
+					h.AssertEq(t, fakeLifecycle.Opts.Exclude("keys/prod.secret", false), true)
+					h.AssertEq(t, fakeLifecycle.Opts.Exclude("README.md", false), false)
+				})
+
+				it("applies the provided patterns after those in the file", func() {
+					_, err := subject.Build(context.TODO(), BuildOptions{
+						Image:   "some/app",
+						Builder: builderName,
+						AppPath: appDir,
+						Exclude: []string{"!public.secret"},
+					})
+					h.AssertNil(t, err)
+					h.AssertEq(t, fakeLifecycle.Opts.Exclude("keys/prod.secret", false), true)
+					h.AssertEq(t, fakeLifecycle.Opts.Exclude("keys/public.secret", false), false)
+				})
+			})
+		})
+
+		when("the app has a project.toml", func() {
+			var (
+				appDir       string
+				otherBuilder *fakes.Image
+			)
+
+			it.Before(func() {
+				var err error
+				appDir, err = ioutil.TempDir(tmpDir, "project-app")
+				h.AssertNil(t, err)
+
+				h.AssertNil(t, ioutil.WriteFile(filepath.Join(appDir, "project.toml"), []byte(`
+[project]
+image = "some/app"
+
+[build]
+builder = "example.com/project/builder:tag"
+include = ["src/", "*.toml"]
+exclude = ["src/generated/"]
+
+[[build.buildpacks]]
+id = "buildpack.id"
+version = "buildpack.version"
+
+[[build.env]]
+name = "key1"
+value = "project-value1"
+
+[[build.env]]
+name = "key2"
+value = "project-value2"
+`), 0644))
+
+				otherBuilder = ifakes.NewFakeBuilderImage(t,
+					"example.com/project/builder:tag",
+					defaultBuilderStackID,
+					"1234",
+					"5678",
+					builder.Metadata{
+						Buildpacks: []builder.BuildpackMetadata{
+							{BuildpackInfo: builder.BuildpackInfo{ID: "buildpack.id", Version: "buildpack.version"}, Latest: true},
+						},
+						Stack: builder.StackMetadata{RunImage: builder.RunImageMetadata{Image: "default/run"}},
+						Lifecycle: builder.LifecycleMetadata{
+							LifecycleInfo: builder.LifecycleInfo{Version: &builder.Version{Version: *semver.MustParse("0.3.0")}},
+							API: builder.LifecycleAPI{
+								BuildpackVersion: api.MustParse("0.3"),
+								PlatformVersion:  api.MustParse("0.2"),
+							},
+						},
+					},
+				)
+				fakeImageFetcher.LocalImages[otherBuilder.Name()] = otherBuilder
+			})
+
+			it.After(func() {
+				otherBuilder.Cleanup()
+			})
+
+			it("uses the image, builder, buildpacks and env from the file", func() {
+				result, err := subject.Build(context.TODO(), BuildOptions{
+					AppPath:        appDir,
+					DefaultBuilder: builderName,
+				})
+				h.AssertNil(t, err)
+				h.AssertEq(t, result.Image, "index.docker.io/some/app:latest")
+				h.AssertEq(t, result.Builder, "example.com/project/builder:tag")
+
+				bldr, err := builder.GetBuilder(otherBuilder)
+				h.AssertNil(t, err)
+				h.AssertEq(t, bldr.GetOrder(), builder.Order{
+					{Group: []builder.BuildpackRef{{
+						BuildpackInfo: builder.BuildpackInfo{ID: "buildpack.id", Version: "buildpack.version"},
+					}}},
+				})
+
+				layerTar, err := otherBuilder.FindLayerWithPath("/platform/env/key1")
+				h.AssertNil(t, err)
+				assertTarFileContents(t, layerTar, "/platform/env/key1", `project-value1`)
+				assertTarFileContents(t, layerTar, "/platform/env/key2", `project-value2`)
+			})
+
+			it("prefers the provided options over the file", func() {
+				result, err := subject.Build(context.TODO(), BuildOptions{
+					Image:   "example.com/some/repo:tag",
+					Builder: builderName,
+					AppPath: appDir,
+					Env:     map[string]string{"key2": "option-value2"},
+				})
+				h.AssertNil(t, err)
+				h.AssertEq(t, result.Image, "example.com/some/repo:tag")
+				h.AssertEq(t, result.Builder, builderName)
+
+				layerTar, err := defaultBuilderImage.FindLayerWithPath("/platform/env/key1")
+				h.AssertNil(t, err)
+				assertTarFileContents(t, layerTar, "/platform/env/key1", `project-value1`)
+				assertTarFileContents(t, layerTar, "/platform/env/key2", `option-value2`)
+			})
+
+			it("only includes the files matched by the include patterns", func() {
+				_, err := subject.Build(context.TODO(), BuildOptions{
+					AppPath: appDir,
+					Exclude: []string{"*.md"},
+				})
+				h.AssertNil(t, err)
+				h.AssertEq(t, fakeLifecycle.Opts.Exclude("src/main.go", false), false)
+				h.AssertEq(t, fakeLifecycle.Opts.Exclude("project.toml", false), false)
+				h.AssertEq(t, fakeLifecycle.Opts.Exclude("README", false), true)
+				h.AssertEq(t, fakeLifecycle.Opts.Exclude("docs", true), false)
+				h.AssertEq(t, fakeLifecycle.Opts.Exclude("src/generated/code.go", false), true)
+				h.AssertEq(t, fakeLifecycle.Opts.Exclude("src/README.md", false), true)
+			})
+
+			when("no builder is set anywhere", func() {
+				it("returns ErrNoBuilder", func() {
+					h.AssertNil(t, ioutil.WriteFile(filepath.Join(appDir, "project.toml"), []byte(`
+[project]
+image = "some/app"
+`), 0644))
+					_, err := subject.Build(context.TODO(), BuildOptions{AppPath: appDir})
+					h.AssertEq(t, errors.Cause(err) == ErrNoBuilder, true)
+				})
+			})
+
+			when("the file is invalid", func() {
+				it("errors", func() {
+					h.AssertNil(t, ioutil.WriteFile(filepath.Join(appDir, "project.toml"), []byte("[[build.buildpacks]]\n"), 0644))
+					_, err := subject.Build(context.TODO(), BuildOptions{AppPath: appDir})
+					h.AssertError(t, err, "invalid project descriptor")
+				})
+			})
+		})
+
 		when("Buildpacks option", func() {
 			it("builder order is overwritten", func() {
-				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+				_, err := subject.Build(context.TODO(), BuildOptions{
 					Image:      "some/app",
 					Builder:    builderName,
 					ClearCache: true,
 					Buildpacks: []string{"buildpack.id@buildpack.version"},
-				}))
+				})
+				h.AssertNil(t, err)
 				h.AssertEq(t, fakeLifecycle.Opts.Builder.Name(), defaultBuilderImage.Name())
 				bldr, err := builder.GetBuilder(defaultBuilderImage)
 				h.AssertNil(t, err)
@@ -535,12 +983,13 @@ func testBuild(t *testing.T, when spec.G, it spec.S) {
 
 			when("no version is provided", func() {
 				it("resolves version", func() {
-					h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+					_, err := subject.Build(context.TODO(), BuildOptions{
 						Image:      "some/app",
 						Builder:    builderName,
 						ClearCache: true,
 						Buildpacks: []string{"buildpack.id"},
-					}))
+					})
+					h.AssertNil(t, err)
 					h.AssertEq(t, fakeLifecycle.Opts.Builder.Name(), defaultBuilderImage.Name())
 					bldr, err := builder.GetBuilder(defaultBuilderImage)
 					h.AssertNil(t, err)
@@ -557,12 +1006,13 @@ func testBuild(t *testing.T, when spec.G, it spec.S) {
 
 			when("latest is explicitly provided", func() {
 				it("resolves version and prints a warning", func() {
-					h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+					_, err := subject.Build(context.TODO(), BuildOptions{
 						Image:      "some/app",
 						Builder:    builderName,
 						ClearCache: true,
 						Buildpacks: []string{"buildpack.id@latest"},
-					}))
+					})
+					h.AssertNil(t, err)
 					h.AssertEq(t, fakeLifecycle.Opts.Builder.Name(), defaultBuilderImage.Name())
 					bldr, err := builder.GetBuilder(defaultBuilderImage)
 					h.AssertNil(t, err)
@@ -579,14 +1029,13 @@ func testBuild(t *testing.T, when spec.G, it spec.S) {
 			})
 
 			it("ensures buildpacks exist on builder", func() {
-				h.AssertError(t, subject.Build(context.TODO(), BuildOptions{
+				_, err := subject.Build(context.TODO(), BuildOptions{
 					Image:      "some/app",
 					Builder:    builderName,
 					ClearCache: true,
 					Buildpacks: []string{"missing.bp@version"},
-				}),
-					"no versions of buildpack 'missing.bp' were found on the builder",
-				)
+				})
+				h.AssertError(t, err, "no versions of buildpack 'missing.bp' were found on the builder")
 			})
 
 			when("buildpacks include URIs", func() {
@@ -600,13 +1049,28 @@ func testBuild(t *testing.T, when spec.G, it spec.S) {
 					h.AssertNil(t, os.Remove(buildpackTgz))
 				})
 
+				it("fetches the images of docker:// URIs with a tag and the pull policy", func() {
+					subject.downloader = blob.NewDownloader(subject.logger, tmpDir, fakeImageFetcher)
+					_, err := subject.Build(context.TODO(), BuildOptions{
+						Image:      "some/app",
+						Builder:    builderName,
+						ClearCache: true,
+						Buildpacks: []string{"docker://some-bp:1.0"},
+						PullPolicy: image.PullIfNotPresent,
+					})
+
+					h.AssertError(t, err, "fetching buildpack image 'some-bp:1.0'")
+					h.AssertEq(t, fakeImageFetcher.FetchCalls["some-bp:1.0"].Daemon, true)
+					h.AssertEq(t, fakeImageFetcher.FetchCalls["some-bp:1.0"].PullPolicy, image.PullIfNotPresent)
+				})
+
 				when("is windows", func() {
 					it.Before(func() {
 						h.SkipIf(t, runtime.GOOS != "windows", "Skipped on non-windows")
 					})
 
 					it("disallows directory-based buildpacks", func() {
-						err := subject.Build(context.TODO(), BuildOptions{
+						_, err := subject.Build(context.TODO(), BuildOptions{
 							Image:      "some/app",
 							Builder:    builderName,
 							ClearCache: true,
@@ -620,7 +1084,7 @@ func testBuild(t *testing.T, when spec.G, it spec.S) {
 					})
 
 					it("buildpacks are added to ephemeral builder", func() {
-						err := subject.Build(context.TODO(), BuildOptions{
+						_, err := subject.Build(context.TODO(), BuildOptions{
 							Image:      "some/app",
 							Builder:    builderName,
 							ClearCache: true,
@@ -665,7 +1129,7 @@ func testBuild(t *testing.T, when spec.G, it spec.S) {
 					})
 
 					it("buildpacks are added to ephemeral builder", func() {
-						err := subject.Build(context.TODO(), BuildOptions{
+						_, err := subject.Build(context.TODO(), BuildOptions{
 							Image:      "some/app",
 							Builder:    builderName,
 							ClearCache: true,
@@ -714,7 +1178,7 @@ func testBuild(t *testing.T, when spec.G, it spec.S) {
 					})
 
 					it("adds the buildpack", func() {
-						err := subject.Build(context.TODO(), BuildOptions{
+						_, err := subject.Build(context.TODO(), BuildOptions{
 							Image:      "some/app",
 							Builder:    builderName,
 							ClearCache: true,
@@ -746,16 +1210,197 @@ func testBuild(t *testing.T, when spec.G, it spec.S) {
 			})
 		})
 
+		when("Network option", func() {
+			it("uses the daemon default network by default", func() {
+				_, err := subject.Build(context.TODO(), BuildOptions{
+					Image:   "some/app",
+					Builder: builderName,
+				})
+				h.AssertNil(t, err)
+				h.AssertEq(t, fakeLifecycle.Opts.Network, "")
+			})
+
+			it("passes the network through to lifecycle", func() {
+				_, err := subject.Build(context.TODO(), BuildOptions{
+					Image:   "some/app",
+					Builder: builderName,
+					Network: "some-network",
+				})
+				h.AssertNil(t, err)
+				h.AssertEq(t, fakeLifecycle.Opts.Network, "some-network")
+			})
+		})
+
+		when("Volumes option", func() {
+			it("passes the volumes through to lifecycle as read-only binds by default", func() {
+				_, err := subject.Build(context.TODO(), BuildOptions{
+					Image:   "some/app",
+					Builder: builderName,
+					Volumes: []string{"/some/host/dir:/some/target", "some-volume:/other/target:rw"},
+				})
+				h.AssertNil(t, err)
+				h.AssertEq(t, fakeLifecycle.Opts.Volumes, []string{"/some/host/dir:/some/target:ro", "some-volume:/other/target:rw"})
+			})
+
+			it("resolves relative host paths", func() {
+				_, err := subject.Build(context.TODO(), BuildOptions{
+					Image:   "some/app",
+					Builder: builderName,
+					Volumes: []string{"./some/dir:/some/target"},
+				})
+				h.AssertNil(t, err)
+				wd, err := os.Getwd()
+				h.AssertNil(t, err)
+				h.AssertEq(t, fakeLifecycle.Opts.Volumes, []string{filepath.Join(wd, "some", "dir") + ":/some/target:ro"})
+			})
+
+			it("keeps the drive letter of Windows host paths", func() {
+				_, err := subject.Build(context.TODO(), BuildOptions{
+					Image:   "some/app",
+					Builder: builderName,
+					Volumes: []string{`C:\deps:/deps:ro`, `d:/other/deps:/other/deps`},
+				})
+				h.AssertNil(t, err)
+				h.AssertEq(t, fakeLifecycle.Opts.Volumes, []string{`C:\deps:/deps:ro`, `d:/other/deps:/other/deps:ro`})
+			})
+
+			for _, target := range []string{"/layers", "/workspace/sub", "/cnb/buildpacks", "/platform", "/"} {
+				target := target
+				it(fmt.Sprintf("errors when the target shadows a lifecycle path: %s", target), func() {
+					_, err := subject.Build(context.TODO(), BuildOptions{
+						Image:   "some/app",
+						Builder: builderName,
+						Volumes: []string{"/some/host/dir:" + target},
+					})
+					h.AssertError(t, err, "cannot be mounted over")
+				})
+			}
+
+			it("errors when the volume is malformed", func() {
+				_, err := subject.Build(context.TODO(), BuildOptions{
+					Image:   "some/app",
+					Builder: builderName,
+					Volumes: []string{"/some/host/dir"},
+				})
+				h.AssertError(t, err, "volume '/some/host/dir' must be in the form '<host path>:<target path>[:<mode>]'")
+			})
+
+			it("errors when the target is not absolute", func() {
+				_, err := subject.Build(context.TODO(), BuildOptions{
+					Image:   "some/app",
+					Builder: builderName,
+					Volumes: []string{"/some/host/dir:relative"},
+				})
+				h.AssertError(t, err, "target path of volume '/some/host/dir:relative' must be absolute")
+			})
+
+			it("errors when the mode is invalid", func() {
+				_, err := subject.Build(context.TODO(), BuildOptions{
+					Image:   "some/app",
+					Builder: builderName,
+					Volumes: []string{"/some/host/dir:/some/target:xx"},
+				})
+				h.AssertError(t, err, "mode of volume '/some/host/dir:/some/target:xx' must be 'ro' or 'rw'")
+			})
+		})
+
+		when("Secrets option", func() {
+			it("passes the secrets through to lifecycle", func() {
+				_, err := subject.Build(context.TODO(), BuildOptions{
+					Image:   "some/app",
+					Builder: builderName,
+					Secrets: map[string]string{"SOME_TOKEN": "some-secret"},
+				})
+				h.AssertNil(t, err)
+				h.AssertEq(t, fakeLifecycle.Opts.Secrets, map[string]string{"SOME_TOKEN": "some-secret"})
+			})
+
+			it("does not write the secrets to the ephemeral builder", func() {
+				_, err := subject.Build(context.TODO(), BuildOptions{
+					Image:   "some/app",
+					Builder: builderName,
+					Env:     map[string]string{"key1": "value1"},
+					Secrets: map[string]string{"SOME_TOKEN": "some-secret"},
+				})
+				h.AssertNil(t, err)
+				layerTar, err := defaultBuilderImage.FindLayerWithPath("/platform/env/key1")
+				h.AssertNil(t, err)
+				exist, _ := tarFileContents(t, layerTar, "/platform/env/SOME_TOKEN")
+				h.AssertEq(t, exist, false)
+			})
+
+			it("errors when a secret name is not valid", func() {
+				_, err := subject.Build(context.TODO(), BuildOptions{
+					Image:   "some/app",
+					Builder: builderName,
+					Secrets: map[string]string{"../SOME_TOKEN": "some-secret"},
+				})
+				h.AssertError(t, err, "secret name '../SOME_TOKEN' is not a valid env var name")
+			})
+		})
+
+		when("Timeout options", func() {
+			it("has no deadline by default", func() {
+				_, err := subject.Build(context.TODO(), BuildOptions{
+					Image:   "some/app",
+					Builder: builderName,
+				})
+				h.AssertNil(t, err)
+				h.AssertEq(t, fakeLifecycle.Deadline.IsZero(), true)
+			})
+
+			it("sets a deadline for the whole build", func() {
+				start := time.Now()
+				_, err := subject.Build(context.TODO(), BuildOptions{
+					Image:   "some/app",
+					Builder: builderName,
+					Timeout: time.Hour,
+				})
+				h.AssertNil(t, err)
+				h.AssertEq(t, fakeLifecycle.Deadline.After(start), true)
+				h.AssertEq(t, fakeLifecycle.Deadline.Before(start.Add(time.Hour+time.Minute)), true)
+			})
+
+			it("passes the phase timeouts through to lifecycle", func() {
+				_, err := subject.Build(context.TODO(), BuildOptions{
+					Image:         "some/app",
+					Builder:       builderName,
+					PhaseTimeouts: map[string]time.Duration{"builder": time.Minute},
+				})
+				h.AssertNil(t, err)
+				h.AssertEq(t, fakeLifecycle.Opts.PhaseTimeouts, map[string]time.Duration{"builder": time.Minute})
+			})
+
+			it("errors when a phase is unknown", func() {
+				_, err := subject.Build(context.TODO(), BuildOptions{
+					Image:         "some/app",
+					Builder:       builderName,
+					PhaseTimeouts: map[string]time.Duration{"compiler": time.Minute},
+				})
+				h.AssertError(t, err, "unknown phase 'compiler'")
+			})
+
+			it("errors when a timeout is negative", func() {
+				_, err := subject.Build(context.TODO(), BuildOptions{
+					Image:   "some/app",
+					Builder: builderName,
+					Timeout: -time.Minute,
+				})
+				h.AssertError(t, err, "build timeout '-1m0s' must not be negative")
+			})
+		})
+
 		when("Env option", func() {
 			it("should set the env on the ephemeral builder", func() {
-				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+				_, err := subject.Build(context.TODO(), BuildOptions{
 					Image:   "some/app",
 					Builder: builderName,
 					Env: map[string]string{
 						"key1": "value1",
 						"key2": "value2",
 					},
-				}))
+				})
+				h.AssertNil(t, err)
 				layerTar, err := defaultBuilderImage.FindLayerWithPath("/platform/env/key1")
 				h.AssertNil(t, err)
 				assertTarFileContents(t, layerTar, "/platform/env/key1", `value1`)
@@ -778,11 +1423,12 @@ func testBuild(t *testing.T, when spec.G, it spec.S) {
 				})
 
 				it("uses a remote run image", func() {
-					h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+					_, err := subject.Build(context.TODO(), BuildOptions{
 						Image:   "some/app",
 						Builder: builderName,
 						Publish: true,
-					}))
+					})
+					h.AssertNil(t, err)
 					h.AssertEq(t, fakeLifecycle.Opts.Publish, true)
 
 					args := fakeImageFetcher.FetchCalls["default/run"]
@@ -792,60 +1438,121 @@ func testBuild(t *testing.T, when spec.G, it spec.S) {
 					h.AssertEq(t, args.Daemon, true)
 				})
 
+				when("the image is on an insecure registry only reachable over plain HTTP", func() {
+					it.Before(func() {
+						subject.registries = registry.New([]config.Registry{{Name: "registry.example.com", Insecure: true}})
+					})
+
+					it("exports to the daemon and pushes the image from there", func() {
+						_, err := subject.Build(context.TODO(), BuildOptions{
+							Image:   "registry.example.com/some/app",
+							Builder: builderName,
+							Publish: true,
+						})
+						h.AssertError(t, err, "saving image 'registry.example.com/some/app:latest'")
+						h.AssertEq(t, fakeLifecycle.Opts.Publish, false)
+						h.AssertEq(t, fakeImageFetcher.FetchCalls["default/run"].Daemon, true)
+					})
+
+					it("errors when the cache image is on the registry", func() {
+						_, err := subject.Build(context.TODO(), BuildOptions{
+							Image:      "some/app",
+							Builder:    builderName,
+							Publish:    true,
+							CacheImage: "registry.example.com/some/cache-image",
+						})
+						h.AssertError(t, err, "cache image must not be on an insecure registry")
+					})
+				})
+
+				it("passes the credentials of the registry config to lifecycle", func() {
+					subject.registries = registry.New([]config.Registry{{Name: "registry.example.com", Username: "some-user"}})
+					_, err := subject.Build(context.TODO(), BuildOptions{
+						Image:   "some/app",
+						Builder: builderName,
+						Publish: true,
+					})
+					h.AssertNil(t, err)
+
+					reg, err := name.NewRegistry("registry.example.com")
+					h.AssertNil(t, err)
+					auth, err := fakeLifecycle.Opts.Keychain.Resolve(reg)
+					h.AssertNil(t, err)
+					header, err := auth.Authorization()
+					h.AssertNil(t, err)
+					h.AssertEq(t, header, "Basic "+base64.StdEncoding.EncodeToString([]byte("some-user:")))
+				})
+
 				when("false", func() {
 					it("uses a local run image", func() {
-						h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+						_, err := subject.Build(context.TODO(), BuildOptions{
 							Image:   "some/app",
 							Builder: builderName,
 							Publish: false,
-						}))
+						})
+						h.AssertNil(t, err)
 						h.AssertEq(t, fakeLifecycle.Opts.Publish, false)
 
 						args := fakeImageFetcher.FetchCalls["default/run"]
 						h.AssertEq(t, args.Daemon, true)
-						h.AssertEq(t, args.Pull, true)
+						h.AssertEq(t, args.PullPolicy, image.PullAlways)
 
 						args = fakeImageFetcher.FetchCalls[builderName]
 						h.AssertEq(t, args.Daemon, true)
-						h.AssertEq(t, args.Pull, true)
+						h.AssertEq(t, args.PullPolicy, image.PullAlways)
 					})
 				})
 			})
 
-			when("NoPull option", func() {
-				when("true", func() {
+			when("PullPolicy option", func() {
+				when("never", func() {
 					it("uses the local builder and run images without updating", func() {
-						h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
-							Image:   "some/app",
-							Builder: builderName,
-							NoPull:  true,
-						}))
+						_, err := subject.Build(context.TODO(), BuildOptions{
+							Image:      "some/app",
+							Builder:    builderName,
+							PullPolicy: image.PullNever,
+						})
+						h.AssertNil(t, err)
 
 						args := fakeImageFetcher.FetchCalls["default/run"]
 						h.AssertEq(t, args.Daemon, true)
-						h.AssertEq(t, args.Pull, false)
+						h.AssertEq(t, args.PullPolicy, image.PullNever)
 
 						args = fakeImageFetcher.FetchCalls[builderName]
 						h.AssertEq(t, args.Daemon, true)
-						h.AssertEq(t, args.Pull, false)
+						h.AssertEq(t, args.PullPolicy, image.PullNever)
 					})
 				})
 
-				when("false", func() {
-					it("uses pulls the builder and run image before using them", func() {
-						h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+				when("if-not-present", func() {
+					it("passes the policy for the builder and run images", func() {
+						_, err := subject.Build(context.TODO(), BuildOptions{
+							Image:      "some/app",
+							Builder:    builderName,
+							PullPolicy: image.PullIfNotPresent,
+						})
+						h.AssertNil(t, err)
+
+						h.AssertEq(t, fakeImageFetcher.FetchCalls["default/run"].PullPolicy, image.PullIfNotPresent)
+						h.AssertEq(t, fakeImageFetcher.FetchCalls[builderName].PullPolicy, image.PullIfNotPresent)
+					})
+				})
+
+				when("not set", func() {
+					it("pulls the builder and run image before using them", func() {
+						_, err := subject.Build(context.TODO(), BuildOptions{
 							Image:   "some/app",
 							Builder: builderName,
-							NoPull:  false,
-						}))
+						})
+						h.AssertNil(t, err)
 
 						args := fakeImageFetcher.FetchCalls["default/run"]
 						h.AssertEq(t, args.Daemon, true)
-						h.AssertEq(t, args.Pull, true)
+						h.AssertEq(t, args.PullPolicy, image.PullAlways)
 
 						args = fakeImageFetcher.FetchCalls[builderName]
 						h.AssertEq(t, args.Daemon, true)
-						h.AssertEq(t, args.Pull, true)
+						h.AssertEq(t, args.PullPolicy, image.PullAlways)
 					})
 				})
 			})
@@ -872,10 +1579,11 @@ func testBuild(t *testing.T, when spec.G, it spec.S) {
 						})
 
 						it("defaults to the *_PROXY environment variables", func() {
-							h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+							_, err := subject.Build(context.TODO(), BuildOptions{
 								Image:   "some/app",
 								Builder: builderName,
-							}))
+							})
+							h.AssertNil(t, err)
 							h.AssertEq(t, fakeLifecycle.Opts.HTTPProxy, "some-http-proxy")
 							h.AssertEq(t, fakeLifecycle.Opts.HTTPSProxy, "some-https-proxy")
 							h.AssertEq(t, fakeLifecycle.Opts.NoProxy, "some-no-proxy")
@@ -883,10 +1591,11 @@ func testBuild(t *testing.T, when spec.G, it spec.S) {
 					})
 
 					it("falls back to the *_proxy environment variables", func() {
-						h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+						_, err := subject.Build(context.TODO(), BuildOptions{
 							Image:   "some/app",
 							Builder: builderName,
-						}))
+						})
+						h.AssertNil(t, err)
 						h.AssertEq(t, fakeLifecycle.Opts.HTTPProxy, "other-http-proxy")
 						h.AssertEq(t, fakeLifecycle.Opts.HTTPSProxy, "other-https-proxy")
 						h.AssertEq(t, fakeLifecycle.Opts.NoProxy, "other-no-proxy")
@@ -895,7 +1604,7 @@ func testBuild(t *testing.T, when spec.G, it spec.S) {
 
 				when("ProxyConfig is not nil", func() {
 					it("passes the values through", func() {
-						h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+						_, err := subject.Build(context.TODO(), BuildOptions{
 							Image:   "some/app",
 							Builder: builderName,
 							ProxyConfig: &ProxyConfig{
@@ -903,7 +1612,8 @@ func testBuild(t *testing.T, when spec.G, it spec.S) {
 								HTTPSProxy: "custom-https-proxy",
 								NoProxy:    "custom-no-proxy",
 							},
-						}))
+						})
+						h.AssertNil(t, err)
 						h.AssertEq(t, fakeLifecycle.Opts.HTTPProxy, "custom-http-proxy")
 						h.AssertEq(t, fakeLifecycle.Opts.HTTPSProxy, "custom-https-proxy")
 						h.AssertEq(t, fakeLifecycle.Opts.NoProxy, "custom-no-proxy")
@@ -11,6 +11,7 @@ import (
 	"path"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strconv"
 	"time"
 
@@ -35,6 +36,7 @@ const (
 	stackLabel    = "io.buildpacks.stack.id"
 	envUID        = "CNB_USER_ID"
 	envGID        = "CNB_GROUP_ID"
+	envSourceDate = "SOURCE_DATE_EPOCH"
 )
 
 type Builder struct {
@@ -42,12 +44,14 @@ type Builder struct {
 	lifecycle            Lifecycle
 	lifecycleDescriptor  LifecycleDescriptor
 	additionalBuildpacks []Buildpack
+	removedBuildpacks    []BuildpackMetadata
 	metadata             Metadata
 	env                  map[string]string
 	UID, GID             int
 	StackID              string
 	replaceOrder         bool
 	order                Order
+	layerTime            time.Time
 }
 
 type orderTOML struct {
@@ -57,12 +61,12 @@ type orderTOML struct {
 type Order []OrderEntry
 
 type OrderEntry struct {
-	Group []BuildpackRef `toml:"group"`
+	Group []BuildpackRef `toml:"group" json:"group"`
 }
 
 type BuildpackRef struct {
 	BuildpackInfo
-	Optional bool `toml:"optional,omitempty"`
+	Optional bool `toml:"optional,omitempty" json:"optional,omitempty"`
 }
 
 // GetBuilder constructs builder from builder image
@@ -176,9 +180,40 @@ func (b *Builder) AddBuildpack(bp Buildpack) {
 	b.additionalBuildpacks = append(b.additionalBuildpacks, bp)
 	b.metadata.Buildpacks = append(b.metadata.Buildpacks, BuildpackMetadata{
 		BuildpackInfo: bp.Descriptor().Info,
+		Order:         bp.Descriptor().Order,
 	})
 }
 
+// RemoveBuildpack removes the buildpack with the given ID from the builder, only the given version unless it is
+// empty, and returns the versions removed. Their layers are kept, but their files are hidden by a layer of whiteouts.
+func (b *Builder) RemoveBuildpack(id, version string) []BuildpackInfo {
+	var (
+		removed    []BuildpackInfo
+		buildpacks []BuildpackMetadata
+	)
+	for _, bp := range b.metadata.Buildpacks {
+		if bp.ID == id && (version == "" || bp.Version == version) {
+			removed = append(removed, bp.BuildpackInfo)
+			b.removedBuildpacks = append(b.removedBuildpacks, bp)
+			continue
+		}
+		buildpacks = append(buildpacks, bp)
+	}
+	b.metadata.Buildpacks = buildpacks
+
+	var additional []Buildpack
+	for _, bp := range b.additionalBuildpacks {
+		info := bp.Descriptor().Info
+		if info.ID == id && (version == "" || info.Version == version) {
+			continue
+		}
+		additional = append(additional, bp)
+	}
+	b.additionalBuildpacks = additional
+
+	return removed
+}
+
 func (b *Builder) SetLifecycle(lifecycle Lifecycle) error {
 	b.lifecycle = lifecycle
 	b.lifecycleDescriptor = lifecycle.Descriptor()
@@ -208,6 +243,11 @@ func (b *Builder) SetStackInfo(stackConfig StackConfig) {
 }
 
 func (b *Builder) Save() error {
+	var err error
+	if b.layerTime, err = layerTime(); err != nil {
+		return err
+	}
+
 	if err := processOrder(b.metadata.Buildpacks, &b.order); err != nil {
 		return errors.Wrap(err, "processing order")
 	}
@@ -243,11 +283,21 @@ func (b *Builder) Save() error {
 		}
 	}
 
-	if err := validateBuildpacks(b.StackID, b.GetLifecycleDescriptor(), b.additionalBuildpacks); err != nil {
+	if err := validateBuildpacks(b.StackID, b.GetLifecycleDescriptor(), b.metadata.Buildpacks, b.additionalBuildpacks); err != nil {
 		return errors.Wrap(err, "validating buildpacks")
 	}
 
-	for _, bp := range b.additionalBuildpacks {
+	if len(b.removedBuildpacks) > 0 {
+		removedTar, err := b.removedBuildpacksLayer(tmpDir)
+		if err != nil {
+			return err
+		}
+		if err := b.image.AddLayer(removedTar); err != nil {
+			return errors.Wrap(err, "adding removed buildpacks layer")
+		}
+	}
+
+	for _, bp := range b.sortedBuildpacks() {
 		bpLayerTar, err := b.buildpackLayer(tmpDir, bp)
 		if err != nil {
 			return err
@@ -351,12 +401,13 @@ func hasBuildpackWithVersion(bps []BuildpackInfo, version string) bool {
 	return false
 }
 
-func validateBuildpacks(stackID string, lifecycleDescriptor LifecycleDescriptor, bps []Buildpack) error {
+// validateBuildpacks validates the buildpacks added to the builder, with order buildpacks resolved against all the
+// buildpacks on the builder
+func validateBuildpacks(stackID string, lifecycleDescriptor LifecycleDescriptor, allBuildpacks []BuildpackMetadata, bps []Buildpack) error {
 	bpLookup := map[string]interface{}{}
 
-	for _, bp := range bps {
-		bpInfo := bp.Descriptor().Info
-		bpLookup[bpInfo.ID+"@"+bpInfo.Version] = nil
+	for _, bp := range allBuildpacks {
+		bpLookup[bp.ID+"@"+bp.Version] = nil
 	}
 
 	for _, bp := range bps {
@@ -436,29 +487,27 @@ func (b *Builder) defaultDirsLayer(dest string) (string, error) {
 	tw := tar.NewWriter(fh)
 	defer tw.Close()
 
-	now := time.Now()
-
-	if err := tw.WriteHeader(b.packOwnedDir(workspaceDir, now)); err != nil {
+	if err := tw.WriteHeader(b.packOwnedDir(workspaceDir, b.layerTime)); err != nil {
 		return "", errors.Wrapf(err, "creating %s dir in layer", style.Symbol(workspaceDir))
 	}
 
-	if err := tw.WriteHeader(b.packOwnedDir(layersDir, now)); err != nil {
+	if err := tw.WriteHeader(b.packOwnedDir(layersDir, b.layerTime)); err != nil {
 		return "", errors.Wrapf(err, "creating %s dir in layer", style.Symbol(layersDir))
 	}
 
-	if err := tw.WriteHeader(b.rootOwnedDir(cnbDir, now)); err != nil {
+	if err := tw.WriteHeader(b.rootOwnedDir(cnbDir, b.layerTime)); err != nil {
 		return "", errors.Wrapf(err, "creating %s dir in layer", style.Symbol(cnbDir))
 	}
 
-	if err := tw.WriteHeader(b.rootOwnedDir(buildpacksDir, now)); err != nil {
+	if err := tw.WriteHeader(b.rootOwnedDir(buildpacksDir, b.layerTime)); err != nil {
 		return "", errors.Wrapf(err, "creating %s dir in layer", style.Symbol(buildpacksDir))
 	}
 
-	if err := tw.WriteHeader(b.rootOwnedDir(platformDir, now)); err != nil {
+	if err := tw.WriteHeader(b.rootOwnedDir(platformDir, b.layerTime)); err != nil {
 		return "", errors.Wrapf(err, "creating %s dir in layer", style.Symbol(platformDir))
 	}
 
-	if err := tw.WriteHeader(b.rootOwnedDir(platformDir+"/env", now)); err != nil {
+	if err := tw.WriteHeader(b.rootOwnedDir(platformDir+"/env", b.layerTime)); err != nil {
 		return "", errors.Wrapf(err, "creating %s dir in layer", style.Symbol(platformDir+"/env"))
 	}
 
@@ -492,7 +541,7 @@ func (b *Builder) orderLayer(dest string) (string, error) {
 	}
 
 	layerTar := filepath.Join(dest, "order.tar")
-	err = archive.CreateSingleFileTar(layerTar, orderPath, contents)
+	err = b.singleFileLayer(layerTar, orderPath, contents)
 	if err != nil {
 		return "", errors.Wrapf(err, "failed to create order.toml layer tar")
 	}
@@ -527,7 +576,7 @@ func (b *Builder) stackLayer(dest string) (string, error) {
 	}
 
 	layerTar := filepath.Join(dest, "stack.tar")
-	err = archive.CreateSingleFileTar(layerTar, stackPath, buf.String())
+	err = b.singleFileLayer(layerTar, stackPath, buf.String())
 	if err != nil {
 		return "", errors.Wrapf(err, "failed to create stack.toml layer tar")
 	}
@@ -535,6 +584,42 @@ func (b *Builder) stackLayer(dest string) (string, error) {
 	return layerTar, nil
 }
 
+// removedBuildpacksLayer whites out the directories of the removed buildpacks and their compat symlinks, hiding them
+// in the layers below. The 'latest' symlink is only whited out when it points to a removed buildpack.
+func (b *Builder) removedBuildpacksLayer(dest string) (string, error) {
+	fh, err := os.Create(filepath.Join(dest, "removed-buildpacks.tar"))
+	if err != nil {
+		return "", err
+	}
+	defer fh.Close()
+
+	tw := tar.NewWriter(fh)
+	defer tw.Close()
+
+	for _, bp := range b.removedBuildpacks {
+		bpd := BuildpackDescriptor{Info: bp.BuildpackInfo}
+		whiteouts := []string{
+			path.Join(buildpacksDir, bpd.EscapedID(), ".wh."+bp.Version),
+			path.Join(compatBuildpacksDir, bpd.EscapedID(), ".wh."+bp.Version),
+		}
+		if bp.Latest {
+			whiteouts = append(whiteouts, path.Join(compatBuildpacksDir, bpd.EscapedID(), ".wh.latest"))
+		}
+		for _, whiteout := range whiteouts {
+			if err := tw.WriteHeader(&tar.Header{
+				Typeflag: tar.TypeReg,
+				Name:     whiteout,
+				Mode:     0644,
+				ModTime:  b.layerTime,
+			}); err != nil {
+				return "", errors.Wrapf(err, "removing buildpack %s", style.Symbol(bp.ID+"@"+bp.Version))
+			}
+		}
+	}
+
+	return fh.Name(), nil
+}
+
 // Output:
 //
 // layer tar = {ID}.{V}.tar
@@ -553,13 +638,11 @@ func (b *Builder) buildpackLayer(dest string, bp Buildpack) (string, error) {
 	tw := tar.NewWriter(fh)
 	defer tw.Close()
 
-	now := time.Now()
-
 	if err := tw.WriteHeader(&tar.Header{
 		Typeflag: tar.TypeDir,
 		Name:     path.Join(buildpacksDir, bpd.EscapedID()),
 		Mode:     0755,
-		ModTime:  now,
+		ModTime:  b.layerTime,
 	}); err != nil {
 		return "", err
 	}
@@ -569,7 +652,7 @@ func (b *Builder) buildpackLayer(dest string, bp Buildpack) (string, error) {
 		Typeflag: tar.TypeDir,
 		Name:     baseTarDir,
 		Mode:     0755,
-		ModTime:  now,
+		ModTime:  b.layerTime,
 	}); err != nil {
 		return "", err
 	}
@@ -610,6 +693,7 @@ func (b *Builder) embedBuildpackTar(tw *tar.Writer, bp Buildpack, baseTarDir str
 		header.Name = path.Clean(path.Join(baseTarDir, header.Name))
 		header.Uid = b.UID
 		header.Gid = b.GID
+		b.normalizeTime(header)
 		err = tw.WriteHeader(header)
 		if err != nil {
 			return errors.Wrapf(err, "failed to write header for '%s'", header.Name)
@@ -652,6 +736,7 @@ func (b *Builder) embedLifecycleTar(tw *tar.Writer) error {
 			binaryName := pathMatches[1]
 
 			header.Name = lifecycleDir + "/" + binaryName
+			b.normalizeTime(header)
 			err = tw.WriteHeader(header)
 			if err != nil {
 				return errors.Wrapf(err, "failed to write header for '%s'", header.Name)
@@ -682,14 +767,19 @@ func (b *Builder) envLayer(dest string, env map[string]string) (string, error) {
 	tw := tar.NewWriter(fh)
 	defer tw.Close()
 
-	now := time.Now()
+	var keys []string
+	for k := range env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
 
-	for k, v := range env {
+	for _, k := range keys {
+		v := env[k]
 		if err := tw.WriteHeader(&tar.Header{
 			Name:    path.Join(platformDir, "env", k),
 			Size:    int64(len(v)),
 			Mode:    0644,
-			ModTime: now,
+			ModTime: b.layerTime,
 		}); err != nil {
 			return "", err
 		}
@@ -711,13 +801,11 @@ func (b *Builder) lifecycleLayer(dest string) (string, error) {
 	tw := tar.NewWriter(fh)
 	defer tw.Close()
 
-	now := time.Now()
-
 	if err := tw.WriteHeader(&tar.Header{
 		Typeflag: tar.TypeDir,
 		Name:     lifecycleDir,
 		Mode:     0755,
-		ModTime:  now,
+		ModTime:  b.layerTime,
 	}); err != nil {
 		return "", err
 	}
@@ -729,3 +817,63 @@ func (b *Builder) lifecycleLayer(dest string) (string, error) {
 
 	return fh.Name(), nil
 }
+
+func (b *Builder) singleFileLayer(tarFile, path, txt string) error {
+	fh, err := os.Create(tarFile)
+	if err != nil {
+		return fmt.Errorf("create file for tar: %s", err)
+	}
+	defer fh.Close()
+
+	tw := tar.NewWriter(fh)
+	defer tw.Close()
+	return b.addFile(tw, path, txt)
+}
+
+func (b *Builder) addFile(tw *tar.Writer, path, txt string) error {
+	if err := tw.WriteHeader(&tar.Header{
+		Name:    path,
+		Size:    int64(len(txt)),
+		Mode:    0644,
+		ModTime: b.layerTime,
+	}); err != nil {
+		return err
+	}
+	_, err := tw.Write([]byte(txt))
+	return err
+}
+
+func (b *Builder) normalizeTime(header *tar.Header) {
+	header.ModTime = b.layerTime
+	header.AccessTime = time.Time{}
+	header.ChangeTime = time.Time{}
+}
+
+// sortedBuildpacks orders buildpack layers by ID and version so that the layer order does not depend on the order in
+// which buildpacks were added
+func (b *Builder) sortedBuildpacks() []Buildpack {
+	bps := append([]Buildpack{}, b.additionalBuildpacks...)
+	sort.SliceStable(bps, func(i, j int) bool {
+		a, b := bps[i].Descriptor().Info, bps[j].Descriptor().Info
+		if a.ID != b.ID {
+			return a.ID < b.ID
+		}
+		return a.Version < b.Version
+	})
+	return bps
+}
+
+// layerTime is the modification time given to every entry in the builder layers, SOURCE_DATE_EPOCH if set, otherwise
+// a fixed date, so that creating a builder from the same inputs produces the same layers
+func layerTime() (time.Time, error) {
+	epoch := os.Getenv(envSourceDate)
+	if epoch == "" {
+		return archive.NormalizedDateTime, nil
+	}
+
+	seconds, err := strconv.ParseInt(epoch, 10, 64)
+	if err != nil {
+		return time.Time{}, errors.Wrapf(err, "parsing %s value %s", envSourceDate, style.Symbol(epoch))
+	}
+	return time.Unix(seconds, 0).UTC(), nil
+}
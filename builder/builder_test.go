@@ -8,6 +8,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/Masterminds/semver"
 
@@ -30,6 +31,67 @@ func TestBuilder(t *testing.T) {
 	spec.Run(t, "Builder", testBuilder, spec.Parallel(), spec.Report(report.Terminal{}))
 }
 
+func TestBuilderSourceDateEpoch(t *testing.T) {
+	color.NoColor = true
+	spec.Run(t, "Builder SOURCE_DATE_EPOCH", testBuilderSourceDateEpoch, spec.Report(report.Terminal{}))
+}
+
+func testBuilderSourceDateEpoch(t *testing.T, when spec.G, it spec.S) {
+	var (
+		baseImage *fakes.Image
+		subject   *builder.Builder
+	)
+
+	it.Before(func() {
+		var err error
+		baseImage = fakes.NewImage("base/image", "", "")
+		h.AssertNil(t, baseImage.SetEnv("CNB_USER_ID", "1234"))
+		h.AssertNil(t, baseImage.SetEnv("CNB_GROUP_ID", "4321"))
+		h.AssertNil(t, baseImage.SetLabel("io.buildpacks.stack.id", "some.stack.id"))
+		subject, err = builder.New(baseImage, "some/builder")
+		h.AssertNil(t, err)
+	})
+
+	it.After(func() {
+		baseImage.Cleanup()
+		h.AssertNil(t, os.Unsetenv("SOURCE_DATE_EPOCH"))
+	})
+
+	when("SOURCE_DATE_EPOCH is set", func() {
+		it.Before(func() {
+			h.AssertNil(t, os.Setenv("SOURCE_DATE_EPOCH", "1570000000"))
+		})
+
+		it("uses it as the modification time of layer entries", func() {
+			subject.SetEnv(map[string]string{"SOME_KEY": "some-val"})
+			h.AssertNil(t, subject.Save())
+
+			expected := time.Unix(1570000000, 0)
+			layerTar, err := baseImage.FindLayerWithPath("/workspace")
+			h.AssertNil(t, err)
+			h.AssertOnTarEntry(t, layerTar, "/workspace", h.HasModTime(expected))
+
+			layerTar, err = baseImage.FindLayerWithPath("/cnb/stack.toml")
+			h.AssertNil(t, err)
+			h.AssertOnTarEntry(t, layerTar, "/cnb/stack.toml", h.HasModTime(expected))
+
+			layerTar, err = baseImage.FindLayerWithPath("/platform/env/SOME_KEY")
+			h.AssertNil(t, err)
+			h.AssertOnTarEntry(t, layerTar, "/platform/env/SOME_KEY", h.HasModTime(expected))
+		})
+	})
+
+	when("SOURCE_DATE_EPOCH is invalid", func() {
+		it.Before(func() {
+			h.AssertNil(t, os.Setenv("SOURCE_DATE_EPOCH", "not-a-number"))
+		})
+
+		it("returns an error", func() {
+			h.AssertError(t, subject.Save(), "parsing SOURCE_DATE_EPOCH value 'not-a-number'")
+		})
+	})
+}
+
 func testBuilder(t *testing.T, when spec.G, it spec.S) {
 	var (
 		baseImage      *fakes.Image
@@ -47,7 +109,7 @@ func testBuilder(t *testing.T, when spec.G, it spec.S) {
 		mockController = gomock.NewController(t)
 		mockLifecycle = testmocks.NewMockLifecycle(mockController)
 		mockLifecycle.EXPECT().Open().Return(archive.ReadDirAsTar(
-			filepath.Join("testdata", "lifecycle"), ".", 0, 0, 0755), nil).AnyTimes()
+			filepath.Join("testdata", "lifecycle"), ".", 0, 0, 0755, nil), nil).AnyTimes()
 		mockLifecycle.EXPECT().Descriptor().Return(builder.LifecycleDescriptor{
 			Info: builder.LifecycleInfo{
 				Version: &builder.Version{Version: *semver.MustParse("1.2.3")},
@@ -259,6 +321,23 @@ func testBuilder(t *testing.T, when spec.G, it spec.S) {
 				)
 			})
 
+			it("uses a fixed modification time for layer entries", func() {
+				h.AssertNil(t, subject.Save())
+				h.AssertEq(t, baseImage.IsSaved(), true)
+
+				layerTar, err := baseImage.FindLayerWithPath("/workspace")
+				h.AssertNil(t, err)
+				h.AssertOnTarEntry(t, layerTar, "/workspace", h.HasModTime(archive.NormalizedDateTime))
+
+				layerTar, err = baseImage.FindLayerWithPath("/cnb/lifecycle/detector")
+				h.AssertNil(t, err)
+				h.AssertOnTarEntry(t, layerTar, "/cnb/lifecycle/detector", h.HasModTime(archive.NormalizedDateTime))
+
+				layerTar, err = baseImage.FindLayerWithPath("/cnb/stack.toml")
+				h.AssertNil(t, err)
+				h.AssertOnTarEntry(t, layerTar, "/cnb/stack.toml", h.HasModTime(archive.NormalizedDateTime))
+			})
+
 			it("sets the working dir to the layers dir", func() {
 				h.AssertNil(t, subject.Save())
 				h.AssertEq(t, baseImage.IsSaved(), true)
@@ -580,6 +659,8 @@ func testBuilder(t *testing.T, when spec.G, it spec.S) {
 				h.AssertEq(t, metadata.Buildpacks[3].ID, "order-buildpack-id")
 				h.AssertEq(t, metadata.Buildpacks[3].Version, "order-buildpack-version")
 				h.AssertEq(t, metadata.Buildpacks[3].Latest, true)
+				h.AssertEq(t, metadata.Buildpacks[3].Order, bpOrder.Descriptor().Order)
+				h.AssertEq(t, len(metadata.Buildpacks[0].Order), 0)
 			})
 
 			when("base image already has metadata", func() {
@@ -742,6 +823,18 @@ func testBuilder(t *testing.T, when spec.G, it spec.S) {
 				h.AssertOnTarEntry(t, layerTar, "/platform/env/SOME_KEY", h.ContentEquals(`some-val`))
 				h.AssertOnTarEntry(t, layerTar, "/platform/env/OTHER_KEY", h.ContentEquals(`other-val`))
 			})
+
+			it("adds the env vars in sorted order", func() {
+				layerTar, err := baseImage.FindLayerWithPath("/platform/env/SOME_KEY")
+				h.AssertNil(t, err)
+				headers, err := h.ListTarContents(layerTar)
+				h.AssertNil(t, err)
+				var names []string
+				for _, header := range headers {
+					names = append(names, header.Name)
+				}
+				h.AssertEq(t, names, []string{"/platform/env/OTHER_KEY", "/platform/env/SOME_KEY"})
+			})
 		})
 	})
 
@@ -794,7 +887,7 @@ func (f *fakeBuildpack) Descriptor() builder.BuildpackDescriptor {
 }
 
 func (f *fakeBuildpack) Open() (io.ReadCloser, error) {
-	return archive.ReadDirAsTar(filepath.Join("testdata", "buildpack"), ".", 0, 0, 0755), nil
+	return archive.ReadDirAsTar(filepath.Join("testdata", "buildpack"), ".", 0, 0, 0755, nil), nil
 }
 
 func assertImageHasBPLayer(t *testing.T, image *fakes.Image, bp builder.Buildpack) {
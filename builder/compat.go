@@ -12,7 +12,6 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/buildpack/pack/api"
-	"github.com/buildpack/pack/internal/archive"
 	"github.com/buildpack/pack/style"
 )
 
@@ -77,7 +76,7 @@ func (b *Builder) compatLayer(dest string) (string, error) {
 	defer tw.Close()
 
 	if b.lifecycle != nil {
-		if err := compatLifecycle(tw); err != nil {
+		if err := compatLifecycle(tw, b.layerTime); err != nil {
 			return "", err
 		}
 	}
@@ -99,31 +98,30 @@ func (b *Builder) compatLayer(dest string) (string, error) {
 	return compatTar, nil
 }
 
-func compatLifecycle(tw *tar.Writer) error {
-	return addSymlink(tw, compatLifecycleDir, lifecycleDir)
+func compatLifecycle(tw *tar.Writer, modTime time.Time) error {
+	return addSymlink(tw, compatLifecycleDir, lifecycleDir, modTime)
 }
 
 func (b *Builder) compatBuildpacks(tw *tar.Writer) error {
-	now := time.Now()
-	if err := tw.WriteHeader(b.rootOwnedDir(compatBuildpacksDir, now)); err != nil {
+	if err := tw.WriteHeader(b.rootOwnedDir(compatBuildpacksDir, b.layerTime)); err != nil {
 		return errors.Wrapf(err, "creating %s dir in layer", style.Symbol(buildpacksDir))
 	}
 	for _, bp := range b.additionalBuildpacks {
 		descriptor := bp.Descriptor()
 
 		compatDir := path.Join(compatBuildpacksDir, descriptor.EscapedID())
-		if err := tw.WriteHeader(b.rootOwnedDir(compatDir, now)); err != nil {
+		if err := tw.WriteHeader(b.rootOwnedDir(compatDir, b.layerTime)); err != nil {
 			return errors.Wrapf(err, "creating %s dir in layer", style.Symbol(compatDir))
 		}
 		compatLink := path.Join(compatDir, descriptor.Info.Version)
 		bpDir := path.Join(buildpacksDir, descriptor.EscapedID(), descriptor.Info.Version)
-		if err := addSymlink(tw, compatLink, bpDir); err != nil {
+		if err := addSymlink(tw, compatLink, bpDir, b.layerTime); err != nil {
 			return err
 		}
 
 		bpAPIVersion := b.lifecycleDescriptor.API.BuildpackVersion
 		if bpAPIVersion != nil && bpAPIVersion.Equal(api.MustParse("0.1")) {
-			if err := symlinkLatest(tw, bpDir, descriptor, b.metadata); err != nil {
+			if err := symlinkLatest(tw, bpDir, descriptor, b.metadata, b.layerTime); err != nil {
 				return err
 			}
 		}
@@ -136,7 +134,7 @@ func (b *Builder) compatStack(tw *tar.Writer) error {
 	if err := toml.NewEncoder(stackBuf).Encode(b.metadata.Stack); err != nil {
 		return errors.Wrapf(err, "failed to marshal stack.toml")
 	}
-	return archive.AddFileToTar(tw, compatStackPath, stackBuf.String())
+	return b.addFile(tw, compatStackPath, stackBuf.String())
 }
 
 func (b *Builder) compatOrder(tw *tar.Writer) error {
@@ -144,15 +142,16 @@ func (b *Builder) compatOrder(tw *tar.Writer) error {
 	if err != nil {
 		return err
 	}
-	return archive.AddFileToTar(tw, compatOrderPath, orderContents)
+	return b.addFile(tw, compatOrderPath, orderContents)
 }
 
-func addSymlink(tw *tar.Writer, name, linkName string) error {
+func addSymlink(tw *tar.Writer, name, linkName string, modTime time.Time) error {
 	if err := tw.WriteHeader(&tar.Header{
 		Name:     name,
 		Linkname: linkName,
 		Typeflag: tar.TypeSymlink,
 		Mode:     0644,
+		ModTime:  modTime,
 	}); err != nil {
 		return errors.Wrapf(err, "creating %s symlink", style.Symbol(name))
 	}
@@ -161,11 +160,11 @@ func addSymlink(tw *tar.Writer, name, linkName string) error {
 
 // Deprecated: The 'latest' symlink is in place for backwards compatibility only. This should be removed as soon
 // as we no longer support older releases that rely on it.
-func symlinkLatest(tw *tar.Writer, baseTarDir string, bp BuildpackDescriptor, metadata Metadata) error {
+func symlinkLatest(tw *tar.Writer, baseTarDir string, bp BuildpackDescriptor, metadata Metadata, modTime time.Time) error {
 	for _, b := range metadata.Buildpacks {
 		if b.ID == bp.Info.ID && b.Version == bp.Info.Version && b.Latest {
 			name := fmt.Sprintf("%s/%s/%s", compatBuildpacksDir, bp.EscapedID(), "latest")
-			if err := addSymlink(tw, name, baseTarDir); err != nil {
+			if err := addSymlink(tw, name, baseTarDir, modTime); err != nil {
 				return errors.Wrapf(err, "creating latest symlink for buildpack '%s:%s'", bp.Info.ID, bp.Info.Version)
 			}
 			break
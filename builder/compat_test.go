@@ -44,7 +44,7 @@ func testCompat(t *testing.T, when spec.G, it spec.S) {
 		mockLifecycle = testmocks.NewMockLifecycle(mockController)
 
 		mockLifecycle.EXPECT().Open().Return(archive.ReadDirAsTar(
-			filepath.Join("testdata", "lifecycle"), ".", 0, 0, -1), nil).AnyTimes()
+			filepath.Join("testdata", "lifecycle"), ".", 0, 0, -1, nil), nil).AnyTimes()
 
 		bp1v1 = &fakeBuildpack{descriptor: builder.BuildpackDescriptor{
 			API: api.MustParse("0.1"),
@@ -12,15 +12,18 @@ import (
 	"github.com/BurntSushi/toml"
 	"github.com/pkg/errors"
 
+	"github.com/buildpack/pack/blob"
 	"github.com/buildpack/pack/internal/paths"
 )
 
 type Config struct {
-	Description string            `toml:"description"`
-	Buildpacks  []BuildpackConfig `toml:"buildpacks"`
-	Order       Order             `toml:"order"`
-	Stack       StackConfig       `toml:"stack"`
-	Lifecycle   LifecycleConfig   `toml:"lifecycle"`
+	Description      string            `toml:"description"`
+	BaseBuilder      string            `toml:"base-builder"`      // builder image to extend instead of creating the builder from stack.build-image
+	Buildpacks       []BuildpackConfig `toml:"buildpacks"`        // with a base builder, replace its versions of the same buildpack
+	RemoveBuildpacks []BuildpackInfo   `toml:"remove-buildpacks"` // buildpacks of the base builder to remove, every version unless one is given
+	Order            Order             `toml:"order"`             // with a base builder, replaces its order when not empty
+	Stack            StackConfig       `toml:"stack"`
+	Lifecycle        LifecycleConfig   `toml:"lifecycle"`
 }
 
 type BuildpackConfig struct {
@@ -67,7 +70,7 @@ func ReadConfig(path string) (config Config, warnings []string, err error) {
 		return Config{}, nil, errors.Wrapf(err, "parse contents of '%s'", path)
 	}
 
-	if len(config.Order) == 0 {
+	if len(config.Order) == 0 && config.BaseBuilder == "" {
 		warnings = append(warnings, fmt.Sprintf("empty %s definition", style.Symbol("order")))
 	}
 
@@ -132,6 +135,10 @@ func parseConfig(reader io.Reader, relativeToDir string) (Config, error) {
 }
 
 func transformRelativePath(uri, relativeTo string) (string, error) {
+	if blob.IsImageURI(uri) {
+		return uri, nil
+	}
+
 	parsed, err := url.Parse(uri)
 	if err != nil {
 		return "", err
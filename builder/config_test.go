@@ -57,6 +57,21 @@ func testConfig(t *testing.T, when spec.G, it spec.S) {
 			})
 		})
 
+		when("buildpack URIs reference images", func() {
+			it.Before(func() {
+				h.AssertNil(t, ioutil.WriteFile(builderConfigPath, []byte(`
+[[buildpacks]]
+  uri = "docker://some-bp:1.0"
+`), 0666))
+			})
+
+			it("keeps the URIs", func() {
+				builderConfig, _, err := builder.ReadConfig(builderConfigPath)
+				h.AssertNil(t, err)
+				h.AssertEq(t, builderConfig.Buildpacks[0].URI, "docker://some-bp:1.0")
+			})
+		})
+
 		when("an error occurs while reading", func() {
 			it("bubbles up the error", func() {
 				_, _, err := builder.ReadConfig(builderConfigPath)
@@ -136,6 +151,30 @@ func testConfig(t *testing.T, when spec.G, it spec.S) {
 					h.AssertSliceContains(t, warns, "empty 'order' definition")
 				})
 			})
+
+			when("'order' is missing with a base builder", func() {
+				it.Before(func() {
+					h.AssertNil(t, ioutil.WriteFile(builderConfigPath, []byte(`
+base-builder = "some/base-builder"
+
+[[buildpacks]]
+  id = "some.buildpack"
+
+[[remove-buildpacks]]
+  id = "other.buildpack"
+  version = "other.buildpack.version"
+`), 0666))
+				})
+
+				it("keeps the order of the base builder without warnings", func() {
+					builderConfig, warns, err := builder.ReadConfig(builderConfigPath)
+					h.AssertNil(t, err)
+
+					h.AssertEq(t, len(warns), 0)
+					h.AssertEq(t, builderConfig.BaseBuilder, "some/base-builder")
+					h.AssertEq(t, builderConfig.RemoveBuildpacks, []builder.BuildpackInfo{{ID: "other.buildpack", Version: "other.buildpack.version"}})
+				})
+			})
 		})
 	})
 }
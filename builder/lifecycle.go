@@ -22,6 +22,9 @@ const (
 	DefaultLifecycleVersion    = "0.4.0"
 	DefaultBuildpackAPIVersion = "0.2"
 	DefaultPlatformAPIVersion  = "0.1"
+
+	// AdditionalTagsLifecycleVersion is the first lifecycle whose exporter accepts more than one tag
+	AdditionalTagsLifecycleVersion = "0.5.0"
 )
 
 var (
@@ -0,0 +1,506 @@
+package builder
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/Masterminds/semver"
+	"github.com/pkg/errors"
+
+	"github.com/buildpack/pack/blob"
+	"github.com/buildpack/pack/internal/paths"
+	"github.com/buildpack/pack/style"
+)
+
+type IssueSeverity int
+
+const (
+	SeverityWarning IssueSeverity = iota
+	SeverityError
+)
+
+func (s IssueSeverity) String() string {
+	if s == SeverityError {
+		return "error"
+	}
+	return "warning"
+}
+
+// ConfigPosition is a position in a builder config file, Line is 0 for issues with the file as a whole
+type ConfigPosition struct {
+	File string
+	Line int
+}
+
+func (p ConfigPosition) String() string {
+	if p.Line == 0 {
+		return p.File
+	}
+	return fmt.Sprintf("%s:%d", p.File, p.Line)
+}
+
+// ConfigIssue is a problem found in a builder config by LintConfig
+type ConfigIssue struct {
+	Position ConfigPosition
+	Severity IssueSeverity
+	Message  string
+}
+
+func (i ConfigIssue) String() string {
+	return fmt.Sprintf("%s: %s: %s", i.Position, i.Severity, i.Message)
+}
+
+// LintConfig checks the builder config at path without fetching any images. Buildpacks at local paths or file URIs
+// are read to check their buildpack.toml against the config, other buildpacks are only checked by their config. The
+// issues are ordered by position, and an error is only returned when the config cannot be read.
+func LintConfig(path string) ([]ConfigIssue, error) {
+	config, warnings, err := ReadConfig(path)
+	if err != nil {
+		return nil, err
+	}
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading config file")
+	}
+	var raw Config // before relative paths are resolved, which turns missing URIs into the config's directory
+	if _, err := toml.Decode(string(contents), &raw); err != nil {
+		return nil, errors.Wrap(err, "decoding toml contents")
+	}
+
+	l := &configLinter{
+		config:    config,
+		raw:       raw,
+		file:      path,
+		positions: indexConfigPositions(contents),
+		versions:  map[string]map[string]bool{},
+	}
+	for _, warning := range warnings {
+		l.add(0, SeverityWarning, "%s", warning)
+	}
+	l.lintStack()
+	l.lintLifecycle()
+	l.lintBuildpacks()
+	l.lintOrder()
+
+	sort.SliceStable(l.issues, func(i, j int) bool { return l.issues[i].Position.Line < l.issues[j].Position.Line })
+	return l.issues, nil
+}
+
+type configLinter struct {
+	config    Config
+	raw       Config
+	file      string
+	positions configPositions
+	issues    []ConfigIssue
+
+	versions        map[string]map[string]bool // versions of the configured buildpacks by ID, an empty version is any version
+	unknownVersions []string                   // of the buildpacks that have no ID in the config and could not be read
+	descriptors     []*BuildpackDescriptor     // of the configured buildpacks that could be read, by index
+}
+
+func (l *configLinter) add(line int, severity IssueSeverity, format string, args ...interface{}) {
+	l.issues = append(l.issues, ConfigIssue{
+		Position: ConfigPosition{File: l.file, Line: line},
+		Severity: severity,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+func (l *configLinter) lintStack() {
+	stack := l.config.Stack
+	pos := l.positions.stack
+	if l.config.BaseBuilder != "" {
+		if stack.BuildImage != "" {
+			l.add(pos.key("build-image"), SeverityError, "%s cannot be used with %s", style.Symbol("stack.build-image"), style.Symbol("base-builder"))
+		}
+		return
+	}
+
+	for _, key := range []struct{ name, value string }{
+		{"id", stack.ID},
+		{"build-image", stack.BuildImage},
+		{"run-image", stack.RunImage},
+	} {
+		if key.value == "" {
+			l.add(pos.missingKey(key.name), SeverityError, "%s is required", style.Symbol("stack."+key.name))
+		}
+	}
+	if len(l.config.RemoveBuildpacks) > 0 {
+		l.add(l.positions.removeBuildpacks.at(0).line, SeverityError, "%s requires %s", style.Symbol("remove-buildpacks"), style.Symbol("base-builder"))
+	}
+}
+
+func (l *configLinter) lintLifecycle() {
+	lifecycle := l.config.Lifecycle
+	pos := l.positions.lifecycle
+	if lifecycle.Version != "" && lifecycle.URI != "" {
+		l.add(pos.line, SeverityError, "%s can only declare %s or %s, not both", style.Symbol("lifecycle"), style.Symbol("version"), style.Symbol("uri"))
+	}
+	if lifecycle.Version != "" {
+		if _, err := semver.NewVersion(lifecycle.Version); err != nil {
+			l.add(pos.key("version"), SeverityError, "%s must be a valid semver", style.Symbol("lifecycle.version"))
+		}
+	}
+	if lifecycle.URI != "" {
+		l.lintURI(pos.key("uri"), "lifecycle", lifecycle.URI, "file", "http", "https")
+	}
+}
+
+// lintURI checks the scheme of the URI, and that the files of local paths and file URIs exist. It returns the local
+// path of those.
+func (l *configLinter) lintURI(line int, subject, uri string, schemes ...string) string {
+	supports := func(scheme string) bool {
+		for _, s := range schemes {
+			if s == scheme {
+				return true
+			}
+		}
+		l.add(line, SeverityError, "%s URI %s has unsupported protocol %s", subject, style.Symbol(uri), style.Symbol(scheme))
+		return false
+	}
+
+	if blob.IsImageURI(uri) {
+		if !supports(blob.ImageScheme) {
+			return ""
+		}
+		if _, err := blob.ParseImageURI(uri); err != nil {
+			l.add(line, SeverityError, "%s URI %s is invalid: %s", subject, style.Symbol(uri), err)
+		}
+		return ""
+	}
+
+	path := uri
+	if paths.IsURI(uri) {
+		parsed, err := url.Parse(uri)
+		if err != nil {
+			l.add(line, SeverityError, "%s URI %s is invalid: %s", subject, style.Symbol(uri), err)
+			return ""
+		}
+		if !supports(parsed.Scheme) || parsed.Scheme != "file" {
+			return ""
+		}
+		if path, err = paths.UriToFilePath(uri); err != nil {
+			l.add(line, SeverityError, "%s URI %s is invalid: %s", subject, style.Symbol(uri), err)
+			return ""
+		}
+	}
+	if _, err := os.Stat(path); err != nil {
+		l.add(line, SeverityError, "%s not found at %s", subject, style.Symbol(path))
+		return ""
+	}
+	return path
+}
+
+func (l *configLinter) lintBuildpacks() {
+	stackID := l.config.Stack.ID
+	seen := map[string]int{}
+	l.descriptors = make([]*BuildpackDescriptor, len(l.config.Buildpacks))
+
+	for i, bp := range l.config.Buildpacks {
+		pos := l.positions.buildpacks.at(i)
+		info := bp.BuildpackInfo
+
+		if l.raw.Buildpacks[i].URI == "" {
+			l.add(pos.line, SeverityError, "buildpack is missing a %s", style.Symbol("uri"))
+		} else if path := l.lintURI(pos.key("uri"), "buildpack", bp.URI, "file", "http", "https", "docker"); path != "" {
+			buildpack, err := NewBuildpack(blob.NewBlob(path))
+			if err != nil {
+				l.add(pos.key("uri"), SeverityError, "invalid buildpack at %s: %s", style.Symbol(path), err)
+			} else {
+				bpd := buildpack.Descriptor()
+				l.descriptors[i] = &bpd
+				info = l.lintDescriptor(pos, bp.BuildpackInfo, bpd, stackID)
+			}
+		}
+
+		if info.ID == "" {
+			l.add(pos.line, SeverityWarning, "buildpack has no %s and cannot be read offline, order references to it are not checked", style.Symbol("id"))
+			l.unknownVersions = append(l.unknownVersions, info.Version)
+			continue
+		}
+
+		ref := info.ID + "@" + info.Version
+		if previous, ok := seen[ref]; ok && info.Version != "" {
+			l.add(pos.line, SeverityError, "buildpack %s is configured more than once, first on line %d", style.Symbol(ref), previous)
+		}
+		seen[ref] = pos.line
+
+		if l.versions[info.ID] == nil {
+			l.versions[info.ID] = map[string]bool{}
+		}
+		l.versions[info.ID][info.Version] = true
+	}
+
+	for i, bpd := range l.descriptors {
+		if bpd == nil {
+			continue
+		}
+		pos := l.positions.buildpacks.at(i)
+		for _, group := range bpd.Order {
+			for _, ref := range group.Group {
+				if problem := l.resolve(ref.BuildpackInfo); problem != "" {
+					l.add(pos.line, l.missingSeverity(), "order of buildpack %s references %s", style.Symbol(bpd.Info.ID+"@"+bpd.Info.Version), problem)
+				}
+			}
+		}
+	}
+}
+
+// lintDescriptor checks a buildpack.toml against its config and returns the info of the buildpack
+func (l *configLinter) lintDescriptor(pos *tablePosition, config BuildpackInfo, bpd BuildpackDescriptor, stackID string) BuildpackInfo {
+	if config.ID != "" && config.ID != bpd.Info.ID {
+		l.add(pos.key("id"), SeverityError, "buildpack has ID %s in its buildpack.toml but %s in the builder config", style.Symbol(bpd.Info.ID), style.Symbol(config.ID))
+	}
+	if config.Version != "" && config.Version != bpd.Info.Version {
+		l.add(pos.key("version"), SeverityError, "buildpack %s has version %s in its buildpack.toml but %s in the builder config", style.Symbol(bpd.Info.ID), style.Symbol(bpd.Info.Version), style.Symbol(config.Version))
+	}
+
+	if len(bpd.Stacks) > 0 && stackID != "" && !bpd.SupportsStack(stackID) {
+		var stacks []string
+		for _, stack := range bpd.Stacks {
+			stacks = append(stacks, stack.ID)
+		}
+		l.add(pos.line, SeverityError, "buildpack %s does not support stack %s, its buildpack.toml declares %s",
+			style.Symbol(bpd.Info.ID+"@"+bpd.Info.Version), style.Symbol(stackID), strings.Join(stacks, ", "))
+	}
+	return bpd.Info
+}
+
+func (l *configLinter) lintOrder() {
+	type optionalGroup struct {
+		index int
+		ids   map[string]bool
+	}
+	var (
+		seen           = map[string]int{}
+		optionalGroups []optionalGroup
+	)
+
+	for o, entry := range l.config.Order {
+		order := l.positions.order.at(o)
+		if len(entry.Group) == 0 {
+			l.add(order.line, SeverityError, "group %d is empty", o+1)
+			continue
+		}
+
+		var (
+			refs         []string
+			ids          = map[string]bool{}
+			optionalOnly = true
+		)
+		for r, ref := range entry.Group {
+			line := order.groupLine(r)
+			if ref.ID == "" {
+				l.add(line, SeverityError, "group %d has an entry without an %s", o+1, style.Symbol("id"))
+				continue
+			}
+			if problem := l.resolve(ref.BuildpackInfo); problem != "" {
+				l.add(line, l.missingSeverity(), "group %d references %s", o+1, problem)
+			}
+			refs = append(refs, fmt.Sprintf("%s@%s:%t", ref.ID, ref.Version, ref.Optional))
+			ids[ref.ID] = true
+			optionalOnly = optionalOnly && ref.Optional
+		}
+
+		key := strings.Join(refs, ",")
+		if previous, ok := seen[key]; ok {
+			l.add(order.line, SeverityWarning, "group %d is a duplicate of group %d", o+1, previous+1)
+			continue
+		}
+		seen[key] = o
+
+		if !optionalOnly {
+			continue
+		}
+		for _, earlier := range optionalGroups {
+			if containsAll(earlier.ids, ids) {
+				l.add(order.line, SeverityWarning, "group %d is unreachable, group %d passes detection whenever it would as it only has optional buildpacks, including all of those in group %d", o+1, earlier.index+1, o+1)
+				break
+			}
+		}
+		optionalGroups = append(optionalGroups, optionalGroup{index: o, ids: ids})
+	}
+}
+
+// resolve returns what is wrong with a reference to a buildpack of the config, or an empty string if it resolves or
+// could be to a buildpack without an ID
+func (l *configLinter) resolve(ref BuildpackInfo) string {
+	versions, ok := l.versions[ref.ID]
+	if !ok {
+		if l.couldBeUnknown(ref) {
+			return ""
+		}
+		return fmt.Sprintf("buildpack %s which is not in %s", style.Symbol(ref.ID), style.Symbol("buildpacks"))
+	}
+	if versions[""] {
+		return ""
+	}
+	if ref.Version == "" {
+		if len(versions) > 1 {
+			return fmt.Sprintf("buildpack %s without a version, but there are multiple versions of it", style.Symbol(ref.ID))
+		}
+		return ""
+	}
+	if !versions[ref.Version] && !l.couldBeUnknown(ref) {
+		return fmt.Sprintf("buildpack %s which is not in %s", style.Symbol(ref.ID+"@"+ref.Version), style.Symbol("buildpacks"))
+	}
+	return ""
+}
+
+// couldBeUnknown reports whether the reference could be to one of the buildpacks without an ID, going by their versions
+func (l *configLinter) couldBeUnknown(ref BuildpackInfo) bool {
+	for _, version := range l.unknownVersions {
+		if version == "" || ref.Version == "" || version == ref.Version {
+			return true
+		}
+	}
+	return false
+}
+
+// missingSeverity is the severity of references to buildpacks that are not configured, which may be on the base
+// builder
+func (l *configLinter) missingSeverity() IssueSeverity {
+	if l.config.BaseBuilder != "" {
+		return SeverityWarning
+	}
+	return SeverityError
+}
+
+func containsAll(set, subset map[string]bool) bool {
+	for id := range subset {
+		if !set[id] {
+			return false
+		}
+	}
+	return true
+}
+
+// tablePosition holds the line of a table of the config and of its keys
+type tablePosition struct {
+	line int
+	keys map[string]int
+	last int // line of the last key of the table
+}
+
+// key returns the line of the key, or of the table if the key is not set
+func (t *tablePosition) key(name string) int {
+	if line, ok := t.keys[name]; ok {
+		return line
+	}
+	return t.line
+}
+
+// missingKey returns the line of the key if it is set, or else the line after which it would be added to the table
+func (t *tablePosition) missingKey(name string) int {
+	if line, ok := t.keys[name]; ok {
+		return line
+	}
+	if t.last > 0 {
+		return t.last
+	}
+	return t.line
+}
+
+type tablePositions []*tablePosition
+
+// at returns the position of the table at index i, or an unknown position
+func (t tablePositions) at(i int) *tablePosition {
+	if i < len(t) {
+		return t[i]
+	}
+	return &tablePosition{}
+}
+
+type orderPosition struct {
+	*tablePosition
+	groups tablePositions
+}
+
+type orderPositions []*orderPosition
+
+func (o orderPositions) at(i int) *orderPosition {
+	if i < len(o) {
+		return o[i]
+	}
+	return &orderPosition{tablePosition: &tablePosition{}}
+}
+
+// groupLine returns the line of the ID of entry i of the group, or of the group key for inline groups
+func (o *orderPosition) groupLine(i int) int {
+	if i < len(o.groups) {
+		return o.groups[i].key("id")
+	}
+	return o.key("group")
+}
+
+type configPositions struct {
+	buildpacks       tablePositions
+	removeBuildpacks tablePositions
+	order            orderPositions
+	stack            *tablePosition
+	lifecycle        *tablePosition
+}
+
+// indexConfigPositions finds the lines of the tables and keys of a builder config. It only understands the layout of
+// builder configs, and leaves the positions of anything else unknown.
+func indexConfigPositions(contents []byte) configPositions {
+	positions := configPositions{
+		stack:     &tablePosition{keys: map[string]int{}},
+		lifecycle: &tablePosition{keys: map[string]int{}},
+	}
+
+	current := &tablePosition{keys: map[string]int{}}
+	scanner := bufio.NewScanner(bytes.NewReader(contents))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		table := &tablePosition{line: line, keys: map[string]int{}}
+
+		switch {
+		case strings.HasPrefix(text, "[["):
+			current = table
+			switch tableName(text, "[[", "]]") {
+			case "buildpacks":
+				positions.buildpacks = append(positions.buildpacks, table)
+			case "remove-buildpacks":
+				positions.removeBuildpacks = append(positions.removeBuildpacks, table)
+			case "order":
+				positions.order = append(positions.order, &orderPosition{tablePosition: table})
+			case "order.group":
+				if len(positions.order) > 0 {
+					last := positions.order[len(positions.order)-1]
+					last.groups = append(last.groups, table)
+				}
+			}
+		case strings.HasPrefix(text, "["):
+			current = table
+			switch tableName(text, "[", "]") {
+			case "stack":
+				positions.stack = table
+			case "lifecycle":
+				positions.lifecycle = table
+			}
+		case strings.Contains(text, "=") && !strings.HasPrefix(text, "#"):
+			key := strings.Trim(strings.TrimSpace(strings.SplitN(text, "=", 2)[0]), `"'`)
+			if _, ok := current.keys[key]; !ok {
+				current.keys[key] = line
+			}
+			current.last = line
+		}
+	}
+	return positions
+}
+
+func tableName(text, open, close string) string {
+	end := strings.Index(text, close)
+	if end < 0 {
+		return ""
+	}
+	return strings.TrimSpace(text[len(open):end])
+}
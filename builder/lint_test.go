@@ -0,0 +1,427 @@
+package builder_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpack/pack/builder"
+	h "github.com/buildpack/pack/testhelpers"
+)
+
+func TestLintConfig(t *testing.T) {
+	color.NoColor = true
+	spec.Run(t, "testLintConfig", testLintConfig, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testLintConfig(t *testing.T, when spec.G, it spec.S) {
+	when("#LintConfig", func() {
+		var (
+			tmpDir            string
+			builderConfigPath string
+		)
+
+		writeConfig := func(contents string) {
+			h.AssertNil(t, ioutil.WriteFile(builderConfigPath, []byte(contents), 0666))
+		}
+
+		writeBuildpack := func(dir, descriptor string) {
+			h.AssertNil(t, os.MkdirAll(filepath.Join(tmpDir, dir), 0755))
+			h.AssertNil(t, ioutil.WriteFile(filepath.Join(tmpDir, dir, "buildpack.toml"), []byte(descriptor), 0666))
+		}
+
+		lint := func() []string {
+			issues, err := builder.LintConfig(builderConfigPath)
+			h.AssertNil(t, err)
+			var messages []string
+			for _, issue := range issues {
+				rel, err := filepath.Rel(tmpDir, issue.Position.File)
+				h.AssertNil(t, err)
+				issue.Position.File = rel
+				messages = append(messages, issue.String())
+			}
+			return messages
+		}
+
+		it.Before(func() {
+			var err error
+			tmpDir, err = ioutil.TempDir("", "lint-config-test")
+			h.AssertNil(t, err)
+			builderConfigPath = filepath.Join(tmpDir, "builder.toml")
+
+			writeBuildpack("bp-one", `
+[buildpack]
+id = "bp.one"
+version = "1.0.0"
+
+[[stacks]]
+id = "some.stack"
+`)
+		})
+
+		it.After(func() {
+			h.AssertNil(t, os.RemoveAll(tmpDir))
+		})
+
+		it("reports no issues for a valid config", func() {
+			writeConfig(`
+[[buildpacks]]
+  uri = "bp-one"
+
+[[order]]
+[[order.group]]
+  id = "bp.one"
+
+[stack]
+  id = "some.stack"
+  build-image = "some/build"
+  run-image = "some/run"
+
+[lifecycle]
+  version = "0.5.0"
+`)
+			h.AssertEq(t, len(lint()), 0)
+		})
+
+		it("reports order references to missing buildpacks and versions", func() {
+			writeConfig(`
+[[buildpacks]]
+  id = "bp.one"
+  uri = "bp-one"
+
+[[buildpacks]]
+  id = "bp.remote"
+  version = "2.0.0"
+  uri = "https://example.com/bp.tgz"
+
+[[order]]
+[[order.group]]
+  id = "bp.one"
+[[order.group]]
+  id = "bp.missing"
+
+[[order]]
+  group = [ { id = "bp.remote", version = "1.0.0" }, { version = "1.0.0" } ]
+
+[stack]
+  id = "some.stack"
+  build-image = "some/build"
+  run-image = "some/run"
+`)
+			h.AssertEq(t, lint(), []string{
+				"builder.toml:15: error: group 1 references buildpack 'bp.missing' which is not in 'buildpacks'",
+				"builder.toml:18: error: group 2 references buildpack 'bp.remote@1.0.0' which is not in 'buildpacks'",
+				"builder.toml:18: error: group 2 has an entry without an 'id'",
+			})
+		})
+
+		it("reports references without a version to buildpacks with multiple versions", func() {
+			writeBuildpack("bp-one-v2", `
+[buildpack]
+id = "bp.one"
+version = "2.0.0"
+
+[[stacks]]
+id = "some.stack"
+`)
+			writeConfig(`
+[[buildpacks]]
+  uri = "bp-one"
+[[buildpacks]]
+  uri = "bp-one-v2"
+
+[[order]]
+[[order.group]]
+  id = "bp.one"
+
+[stack]
+  id = "some.stack"
+  build-image = "some/build"
+  run-image = "some/run"
+`)
+			h.AssertEq(t, lint(), []string{
+				"builder.toml:9: error: group 1 references buildpack 'bp.one' without a version, but there are multiple versions of it",
+			})
+		})
+
+		it("reports duplicate and unreachable optional groups", func() {
+			writeBuildpack("bp-two", `
+[buildpack]
+id = "bp.two"
+version = "1.0.0"
+
+[[stacks]]
+id = "some.stack"
+`)
+			writeConfig(`
+[[buildpacks]]
+  uri = "bp-one"
+[[buildpacks]]
+  uri = "bp-two"
+
+[[order]]
+  group = [ { id = "bp.one", optional = true }, { id = "bp.two", optional = true } ]
+
+[[order]]
+  group = [ { id = "bp.two", optional = true } ]
+
+[[order]]
+  group = [ { id = "bp.one", optional = true }, { id = "bp.two", optional = true } ]
+
+[[order]]
+  group = [ { id = "bp.two" } ]
+
+[stack]
+  id = "some.stack"
+  build-image = "some/build"
+  run-image = "some/run"
+`)
+			h.AssertEq(t, lint(), []string{
+				"builder.toml:10: warning: group 2 is unreachable, group 1 passes detection whenever it would as it only has optional buildpacks, including all of those in group 2",
+				"builder.toml:13: warning: group 3 is a duplicate of group 1",
+			})
+		})
+
+		it("reports buildpacks that do not support the stack or do not match their config", func() {
+			writeBuildpack("bp-other", `
+[buildpack]
+id = "bp.other"
+version = "1.0.0"
+
+[[stacks]]
+id = "other.stack"
+
+[[stacks]]
+id = "another.stack"
+`)
+			writeConfig(`
+[[buildpacks]]
+  id = "bp.wrong"
+  version = "1.0.0"
+  uri = "bp-one"
+
+[[buildpacks]]
+  uri = "bp-other"
+
+[stack]
+  id = "some.stack"
+  build-image = "some/build"
+  run-image = "some/run"
+`)
+			h.AssertEq(t, lint(), []string{
+				"builder.toml: warning: empty 'order' definition",
+				"builder.toml:3: error: buildpack has ID 'bp.one' in its buildpack.toml but 'bp.wrong' in the builder config",
+				"builder.toml:7: error: buildpack 'bp.other@1.0.0' does not support stack 'some.stack', its buildpack.toml declares other.stack, another.stack",
+			})
+		})
+
+		it("reports order buildpacks referencing missing buildpacks", func() {
+			writeBuildpack("meta", `
+[buildpack]
+id = "bp.meta"
+version = "1.0.0"
+
+[[order]]
+[[order.group]]
+id = "bp.one"
+version = "1.0.0"
+[[order.group]]
+id = "bp.missing"
+`)
+			writeConfig(`
+[[buildpacks]]
+  uri = "bp-one"
+
+[[buildpacks]]
+  uri = "meta"
+
+[[order]]
+[[order.group]]
+  id = "bp.meta"
+
+[stack]
+  id = "some.stack"
+  build-image = "some/build"
+  run-image = "some/run"
+`)
+			h.AssertEq(t, lint(), []string{
+				"builder.toml:5: error: order of buildpack 'bp.meta@1.0.0' references buildpack 'bp.missing' which is not in 'buildpacks'",
+			})
+		})
+
+		it("reports missing buildpack URIs and files", func() {
+			writeConfig(`
+[[buildpacks]]
+  id = "bp.one"
+
+[[buildpacks]]
+  uri = "missing-dir"
+
+[[buildpacks]]
+  id = "bp.ftp"
+  uri = "ftp://example.com/bp.tgz"
+
+[[order]]
+[[order.group]]
+  id = "bp.one"
+
+[stack]
+  id = "some.stack"
+  build-image = "some/build"
+  run-image = "some/run"
+`)
+			messages := lint()
+			h.AssertEq(t, len(messages), 4)
+			h.AssertEq(t, messages[0], "builder.toml:2: error: buildpack is missing a 'uri'")
+			h.AssertEq(t, messages[1], "builder.toml:5: warning: buildpack has no 'id' and cannot be read offline, order references to it are not checked")
+			h.AssertContains(t, messages[2], "builder.toml:6: error: buildpack not found at")
+			h.AssertEq(t, messages[3], "builder.toml:10: error: buildpack URI 'ftp://example.com/bp.tgz' has unsupported protocol 'ftp'")
+		})
+
+		it("only skips the order references that could be to buildpacks without an ID", func() {
+			writeConfig(`
+[[buildpacks]]
+  uri = "bp-one"
+
+[[buildpacks]]
+  version = "2.0.0"
+  uri = "https://example.com/bp.tgz"
+
+[[order]]
+  group = [ { id = "bp.one" }, { id = "bp.remote", version = "2.0.0" } ]
+
+[[order]]
+  group = [ { id = "bp.remote" } ]
+
+[[order]]
+  group = [ { id = "bp.missing", version = "1.0.0" } ]
+
+[[order]]
+  group = [ { id = "bp.one", version = "3.0.0" } ]
+
+[stack]
+  id = "some.stack"
+  build-image = "some/build"
+  run-image = "some/run"
+`)
+			h.AssertEq(t, lint(), []string{
+				"builder.toml:5: warning: buildpack has no 'id' and cannot be read offline, order references to it are not checked",
+				"builder.toml:16: error: group 3 references buildpack 'bp.missing' which is not in 'buildpacks'",
+				"builder.toml:19: error: group 4 references buildpack 'bp.one@3.0.0' which is not in 'buildpacks'",
+			})
+		})
+
+		it("reads buildpacks at absolute paths and accepts image URIs", func() {
+			writeConfig(`
+[[buildpacks]]
+  uri = "` + filepath.ToSlash(filepath.Join(tmpDir, "bp-one")) + `"
+
+[[buildpacks]]
+  id = "bp.image"
+  uri = "docker://some-bp:1.0"
+
+[[buildpacks]]
+  id = "bp.invalid-image"
+  uri = "docker://some-bp:not a tag"
+
+[[order]]
+[[order.group]]
+  id = "bp.one"
+[[order.group]]
+  id = "bp.image"
+
+[stack]
+  id = "other.stack"
+  build-image = "some/build"
+  run-image = "some/run"
+`)
+			messages := lint()
+			h.AssertEq(t, len(messages), 2)
+			h.AssertEq(t, messages[0], "builder.toml:2: error: buildpack 'bp.one@1.0.0' does not support stack 'other.stack', its buildpack.toml declares some.stack")
+			h.AssertContains(t, messages[1], "builder.toml:11: error: buildpack URI 'docker://some-bp:not a tag' is invalid")
+		})
+
+		it("reports invalid lifecycle version and URI combinations", func() {
+			writeConfig(`
+[[buildpacks]]
+  uri = "bp-one"
+
+[[order]]
+[[order.group]]
+  id = "bp.one"
+
+[stack]
+  id = "some.stack"
+  build-image = "some/build"
+  run-image = "some/run"
+
+[lifecycle]
+  version = "not-a-version"
+  uri = "missing-lifecycle.tgz"
+`)
+			messages := lint()
+			h.AssertEq(t, len(messages), 3)
+			h.AssertEq(t, messages[0], "builder.toml:14: error: 'lifecycle' can only declare 'version' or 'uri', not both")
+			h.AssertEq(t, messages[1], "builder.toml:15: error: 'lifecycle.version' must be a valid semver")
+			h.AssertContains(t, messages[2], "builder.toml:16: error: lifecycle not found at")
+		})
+
+		it("reports missing stack fields", func() {
+			writeConfig(`
+[[buildpacks]]
+  uri = "bp-one"
+
+[[remove-buildpacks]]
+  id = "bp.old"
+
+[[order]]
+[[order.group]]
+  id = "bp.one"
+
+[stack]
+  build-image = ""
+  id = "some.stack"
+`)
+			h.AssertEq(t, lint(), []string{
+				"builder.toml:5: error: 'remove-buildpacks' requires 'base-builder'",
+				"builder.toml:13: error: 'stack.build-image' is required",
+				"builder.toml:14: error: 'stack.run-image' is required",
+			})
+		})
+
+		when("the config extends a base builder", func() {
+			it("reports references to buildpacks that are not configured as warnings", func() {
+				writeConfig(`
+base-builder = "some/builder"
+
+[[buildpacks]]
+  uri = "bp-one"
+
+[[order]]
+[[order.group]]
+  id = "bp.one"
+[[order.group]]
+  id = "bp.from-base"
+
+[stack]
+  id = "some.stack"
+  build-image = "some/build"
+`)
+				h.AssertEq(t, lint(), []string{
+					"builder.toml:11: warning: group 1 references buildpack 'bp.from-base' which is not in 'buildpacks'",
+					"builder.toml:15: error: 'stack.build-image' cannot be used with 'base-builder'",
+				})
+			})
+		})
+
+		it("errors when the config cannot be read", func() {
+			_, err := builder.LintConfig(filepath.Join(tmpDir, "missing.toml"))
+			h.AssertError(t, err, "opening config file")
+		})
+	})
+}
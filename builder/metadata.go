@@ -12,7 +12,8 @@ type Metadata struct {
 
 type BuildpackMetadata struct {
 	BuildpackInfo
-	Latest bool `json:"latest"` // deprecated
+	Latest bool  `json:"latest"`          // deprecated
+	Order  Order `json:"order,omitempty"` // of order buildpacks
 }
 
 type LifecycleMetadata struct {
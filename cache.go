@@ -0,0 +1,135 @@
+package pack
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/buildpack/pack/cache"
+	"github.com/buildpack/pack/logging"
+	"github.com/buildpack/pack/style"
+)
+
+type CacheInfo struct {
+	Name     string
+	Image    string    // empty for volumes created before caches were labelled
+	Size     int64     // -1 when the daemon does not report a size
+	Created  time.Time // zero when the daemon does not report a creation time
+	LastUsed time.Time // zero when no build has recorded a use
+}
+
+// ListCaches returns the cache volumes on the docker daemon, sorted by name.
+func (c *Client) ListCaches(ctx context.Context) ([]CacheInfo, error) {
+	du, err := c.docker.DiskUsage(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "listing volumes")
+	}
+
+	lastUsed, err := c.cacheUsage.LastUsed()
+	if err != nil {
+		return nil, err
+	}
+
+	var caches []CacheInfo
+	for _, vol := range du.Volumes {
+		if !strings.HasPrefix(vol.Name, cache.VolumePrefix) {
+			continue
+		}
+
+		info := CacheInfo{
+			Name:     vol.Name,
+			Image:    vol.Labels[cache.ImageLabel],
+			Size:     -1,
+			LastUsed: lastUsed[vol.Name],
+		}
+		if vol.UsageData != nil {
+			info.Size = vol.UsageData.Size
+		}
+		if created, err := time.Parse(time.RFC3339, vol.CreatedAt); err == nil {
+			info.Created = created
+		}
+		caches = append(caches, info)
+	}
+
+	sort.Slice(caches, func(i, j int) bool {
+		return caches[i].Name < caches[j].Name
+	})
+	return caches, nil
+}
+
+// InspectCache returns the cache volumes belonging to the named image.
+func (c *Client) InspectCache(ctx context.Context, imageName string) ([]CacheInfo, error) {
+	imageRef, err := c.parseTagReference(imageName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid image name '%s'", imageName)
+	}
+
+	names := map[string]bool{}
+	for _, suffix := range []string{"build", "launch"} {
+		names[cache.NewVolumeCache(imageRef, suffix, c.docker).Name()] = true
+	}
+
+	caches, err := c.ListCaches(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var found []CacheInfo
+	for _, info := range caches {
+		if names[info.Name] {
+			found = append(found, info)
+		}
+	}
+	return found, nil
+}
+
+// PruneCaches removes the cache volumes that have not been used within the given duration.
+// Caches with no recorded use are aged from their creation time.
+func (c *Client) PruneCaches(ctx context.Context, olderThan time.Duration) ([]CacheInfo, error) {
+	caches, err := c.ListCaches(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		removed []CacheInfo
+		names   []string
+	)
+	for _, info := range expiredCaches(caches, time.Now().Add(-olderThan)) {
+		if err := c.docker.VolumeRemove(ctx, info.Name, false); err != nil {
+			c.logger.Warnf("Skipping cache %s: %s", style.Symbol(info.Name), err)
+			continue
+		}
+		logging.LogEvent(c.logger, logging.Event{Type: logging.EventCache, Cache: info.Name, Action: "remove"})
+		removed = append(removed, info)
+		names = append(names, info.Name)
+	}
+
+	if err := c.cacheUsage.Forget(names...); err != nil {
+		return removed, err
+	}
+	return removed, nil
+}
+
+func expiredCaches(caches []CacheInfo, cutoff time.Time) []CacheInfo {
+	var expired []CacheInfo
+	for _, info := range caches {
+		lastUsed := info.LastUsed
+		if lastUsed.IsZero() {
+			lastUsed = info.Created
+		}
+		if lastUsed.Before(cutoff) {
+			expired = append(expired, info)
+		}
+	}
+	return expired
+}
+
+func (c *Client) recordCacheUsage(names ...string) {
+	if err := c.cacheUsage.Touch(time.Now(), names...); err != nil {
+		c.logger.Warnf("Unable to record cache usage: %s", err)
+	}
+}
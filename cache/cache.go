@@ -0,0 +1,8 @@
+package cache
+
+type Type int
+
+const (
+	Volume Type = iota
+	Image
+)
@@ -2,24 +2,31 @@ package cache
 
 import (
 	"context"
-	"crypto/sha256"
-	"fmt"
 
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/client"
+	"github.com/google/go-containerregistry/pkg/authn"
 	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/pkg/errors"
+
+	"github.com/buildpack/pack/style"
 )
 
 type ImageCache struct {
-	docker *client.Client
-	image  string
+	docker   *client.Client
+	keychain authn.Keychain
+	ref      name.Reference
+	image    string
 }
 
-func NewImageCache(imageRef name.Reference, dockerClient *client.Client) *ImageCache {
-	sum := sha256.Sum256([]byte(imageRef.String()))
+func NewImageCache(imageRef name.Reference, dockerClient *client.Client, keychain authn.Keychain) *ImageCache {
 	return &ImageCache{
-		image:  fmt.Sprintf("pack-cache-%x", sum[:6]),
-		docker: dockerClient,
+		ref:      imageRef,
+		image:    imageRef.Name(),
+		docker:   dockerClient,
+		keychain: keychain,
 	}
 }
 
@@ -27,6 +34,12 @@ func (c *ImageCache) Name() string {
 	return c.image
 }
 
+func (c *ImageCache) Type() Type {
+	return Image
+}
+
+// Clear removes any local copy of the cache image and overwrites the cache image in its registry with an empty
+// image, so that the next build starts without cached layers.
 func (c *ImageCache) Clear(ctx context.Context) error {
 	_, err := c.docker.ImageRemove(ctx, c.Name(), types.ImageRemoveOptions{
 		Force: true,
@@ -34,5 +47,9 @@ func (c *ImageCache) Clear(ctx context.Context) error {
 	if err != nil && !client.IsErrNotFound(err) {
 		return err
 	}
+
+	if err := remote.Write(c.ref, empty.Image, remote.WithAuthFromKeychain(c.keychain)); err != nil {
+		return errors.Wrapf(err, "overwriting cache image %s", style.Symbol(c.Name()))
+	}
 	return nil
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"math/rand"
+	"os"
 	"testing"
 	"time"
 
@@ -11,7 +12,9 @@ import (
 	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/client"
 	"github.com/fatih/color"
+	"github.com/google/go-containerregistry/pkg/authn"
 	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
 	"github.com/sclevine/spec"
 	"github.com/sclevine/spec/report"
 
@@ -19,11 +22,18 @@ import (
 	h "github.com/buildpack/pack/testhelpers"
 )
 
+var registryConfig *h.TestRegistryConfig
+
 func TestImageCache(t *testing.T) {
 	h.RequireDocker(t)
 	color.NoColor = true
 	rand.Seed(time.Now().UTC().UnixNano())
 
+	registryConfig = h.RunRegistry(t, false)
+	defer registryConfig.StopRegistry(t)
+
+	os.Setenv("DOCKER_CONFIG", registryConfig.DockerConfigDir)
+
 	spec.Run(t, "ImageCache", testImageCache, spec.Parallel(), spec.Report(report.Terminal{}))
 }
 
@@ -37,11 +47,19 @@ func testImageCache(t *testing.T, when spec.G, it spec.S) {
 			h.AssertNil(t, err)
 		})
 
+		it("uses the image reference as the cache image name", func() {
+			ref, err := name.ParseReference("registry.com/my/cache:some-tag", name.WeakValidation)
+			h.AssertNil(t, err)
+			subject := cache.NewImageCache(ref, dockerClient, authn.DefaultKeychain)
+			h.AssertEq(t, subject.Name(), "registry.com/my/cache:some-tag")
+			h.AssertEq(t, subject.Type(), cache.Image)
+		})
+
 		it("reusing the same cache for the same repo name", func() {
 			ref, err := name.ParseReference("my/repo", name.WeakValidation)
 			h.AssertNil(t, err)
-			subject := cache.NewImageCache(ref, dockerClient)
-			expected := cache.NewImageCache(ref, dockerClient)
+			subject := cache.NewImageCache(ref, dockerClient, authn.DefaultKeychain)
+			expected := cache.NewImageCache(ref, dockerClient, authn.DefaultKeychain)
 			if subject.Name() != expected.Name() {
 				t.Fatalf("The same repo name should result in the same volume")
 			}
@@ -50,10 +68,10 @@ func testImageCache(t *testing.T, when spec.G, it spec.S) {
 		it("supplies different images for different tags", func() {
 			ref, err := name.ParseReference("my/repo:other-tag", name.WeakValidation)
 			h.AssertNil(t, err)
-			subject := cache.NewImageCache(ref, dockerClient)
+			subject := cache.NewImageCache(ref, dockerClient, authn.DefaultKeychain)
 			ref, err = name.ParseReference("my/repo", name.WeakValidation)
 			h.AssertNil(t, err)
-			notExpected := cache.NewImageCache(ref, dockerClient)
+			notExpected := cache.NewImageCache(ref, dockerClient, authn.DefaultKeychain)
 			if subject.Name() == notExpected.Name() {
 				t.Fatalf("Different image tags should result in different images")
 			}
@@ -62,10 +80,10 @@ func testImageCache(t *testing.T, when spec.G, it spec.S) {
 		it("supplies different images for different registries", func() {
 			ref, err := name.ParseReference("registry.com/my/repo:other-tag", name.WeakValidation)
 			h.AssertNil(t, err)
-			subject := cache.NewImageCache(ref, dockerClient)
+			subject := cache.NewImageCache(ref, dockerClient, authn.DefaultKeychain)
 			ref, err = name.ParseReference("my/repo", name.WeakValidation)
 			h.AssertNil(t, err)
-			notExpected := cache.NewImageCache(ref, dockerClient)
+			notExpected := cache.NewImageCache(ref, dockerClient, authn.DefaultKeychain)
 			if subject.Name() == notExpected.Name() {
 				t.Fatalf("Different image registries should result in different images")
 			}
@@ -74,10 +92,10 @@ func testImageCache(t *testing.T, when spec.G, it spec.S) {
 		it("resolves implied tag", func() {
 			ref, err := name.ParseReference("my/repo:latest", name.WeakValidation)
 			h.AssertNil(t, err)
-			subject := cache.NewImageCache(ref, dockerClient)
+			subject := cache.NewImageCache(ref, dockerClient, authn.DefaultKeychain)
 			ref, err = name.ParseReference("my/repo", name.WeakValidation)
 			h.AssertNil(t, err)
-			expected := cache.NewImageCache(ref, dockerClient)
+			expected := cache.NewImageCache(ref, dockerClient, authn.DefaultKeychain)
 			if subject.Name() != expected.Name() {
 				t.Fatalf("The same repo name should result in the same image")
 			}
@@ -86,10 +104,10 @@ func testImageCache(t *testing.T, when spec.G, it spec.S) {
 		it("resolves implied registry", func() {
 			ref, err := name.ParseReference("index.docker.io/my/repo", name.WeakValidation)
 			h.AssertNil(t, err)
-			subject := cache.NewImageCache(ref, dockerClient)
+			subject := cache.NewImageCache(ref, dockerClient, authn.DefaultKeychain)
 			ref, err = name.ParseReference("my/repo", name.WeakValidation)
 			h.AssertNil(t, err)
-			expected := cache.NewImageCache(ref, dockerClient)
+			expected := cache.NewImageCache(ref, dockerClient, authn.DefaultKeychain)
 			if subject.Name() != expected.Name() {
 				t.Fatalf("The same repo name should result in the same image")
 			}
@@ -98,6 +116,7 @@ func testImageCache(t *testing.T, when spec.G, it spec.S) {
 
 	when("#Clear", func() {
 		var (
+			repo         string
 			imageName    string
 			dockerClient *client.Client
 			subject      *cache.ImageCache
@@ -110,14 +129,14 @@ func testImageCache(t *testing.T, when spec.G, it spec.S) {
 			h.AssertNil(t, err)
 			ctx = context.TODO()
 
-			ref, err := name.ParseReference(h.RandString(10), name.WeakValidation)
-			h.AssertNil(t, err)
-			subject = cache.NewImageCache(ref, dockerClient)
+			repo = h.RandString(10)
+			ref, err := name.ParseReference(registryConfig.RepoName(repo), name.WeakValidation)
 			h.AssertNil(t, err)
+			subject = cache.NewImageCache(ref, dockerClient, authn.DefaultKeychain)
 			imageName = subject.Name()
 		})
 
-		when("there is a cache image", func() {
+		when("there is a local cache image", func() {
 			it.Before(func() {
 				h.CreateImageOnLocal(t, dockerClient, imageName, fmt.Sprintf(`
 FROM busybox
@@ -139,6 +158,28 @@ LABEL repo_name_for_randomisation=%s
 			})
 		})
 
+		when("there is a cache image in the registry", func() {
+			it.Before(func() {
+				h.CreateImageOnRemote(t, dockerClient, registryConfig, repo, fmt.Sprintf(`
+FROM busybox
+LABEL repo_name_for_randomisation=%s
+`, imageName))
+			})
+
+			it("overwrites the image with an empty image", func() {
+				err := subject.Clear(ctx)
+				h.AssertNil(t, err)
+
+				ref, err := name.ParseReference(imageName, name.WeakValidation)
+				h.AssertNil(t, err)
+				img, err := remote.Image(ref, remote.WithAuthFromKeychain(authn.DefaultKeychain))
+				h.AssertNil(t, err)
+				layers, err := img.Layers()
+				h.AssertNil(t, err)
+				h.AssertEq(t, len(layers), 0)
+			})
+		})
+
 		when("there is no cache image", func() {
 			it("does not fail", func() {
 				err := subject.Clear(ctx)
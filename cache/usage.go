@@ -0,0 +1,82 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/pkg/errors"
+)
+
+// Usage records when cache volumes were last used. Docker cannot relabel a volume
+// after it is created, so the record is kept in a file alongside the pack config.
+// Updates are serialized so that concurrent builds in one process do not lose records.
+type Usage struct {
+	path string
+	mu   sync.Mutex
+}
+
+type usageFile struct {
+	LastUsed map[string]time.Time `toml:"last-used"`
+}
+
+func NewUsage(path string) *Usage {
+	return &Usage{path: path}
+}
+
+// LastUsed returns the last recorded use of each cache, keyed by name
+func (u *Usage) LastUsed() (map[string]time.Time, error) {
+	file := usageFile{}
+	if _, err := toml.DecodeFile(u.path, &file); err != nil && !os.IsNotExist(err) {
+		return nil, errors.Wrapf(err, "failed to read cache usage at path %s", u.path)
+	}
+	if file.LastUsed == nil {
+		file.LastUsed = map[string]time.Time{}
+	}
+	return file.LastUsed, nil
+}
+
+// Touch records that the named caches were used at the given time
+func (u *Usage) Touch(at time.Time, names ...string) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	lastUsed, err := u.LastUsed()
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		lastUsed[name] = at.UTC()
+	}
+	return u.write(lastUsed)
+}
+
+// Forget drops the records for the named caches
+func (u *Usage) Forget(names ...string) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	lastUsed, err := u.LastUsed()
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		delete(lastUsed, name)
+	}
+	return u.write(lastUsed)
+}
+
+func (u *Usage) write(lastUsed map[string]time.Time) error {
+	if err := os.MkdirAll(filepath.Dir(u.path), 0777); err != nil {
+		return err
+	}
+	w, err := os.Create(u.path)
+	if err != nil {
+		return err
+	}
+	defer w.Close()
+
+	return toml.NewEncoder(w).Encode(usageFile{LastUsed: lastUsed})
+}
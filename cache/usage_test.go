@@ -0,0 +1,73 @@
+package cache_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpack/pack/cache"
+	h "github.com/buildpack/pack/testhelpers"
+)
+
+func TestUsage(t *testing.T) {
+	spec.Run(t, "Usage", testUsage, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testUsage(t *testing.T, when spec.G, it spec.S) {
+	var (
+		tmpDir  string
+		subject *cache.Usage
+	)
+
+	it.Before(func() {
+		var err error
+		tmpDir, err = ioutil.TempDir("", "cache-usage-test")
+		h.AssertNil(t, err)
+		subject = cache.NewUsage(filepath.Join(tmpDir, "some-dir", "cache-usage.toml"))
+	})
+
+	it.After(func() {
+		os.RemoveAll(tmpDir)
+	})
+
+	when("#LastUsed", func() {
+		it("returns no records when nothing has been used", func() {
+			lastUsed, err := subject.LastUsed()
+			h.AssertNil(t, err)
+			h.AssertEq(t, len(lastUsed), 0)
+		})
+	})
+
+	when("#Touch", func() {
+		it("records the time each cache was used", func() {
+			first := time.Date(2019, 5, 1, 10, 0, 0, 0, time.UTC)
+			second := first.Add(time.Hour)
+			h.AssertNil(t, subject.Touch(first, "some-cache", "other-cache"))
+			h.AssertNil(t, subject.Touch(second, "other-cache"))
+
+			lastUsed, err := subject.LastUsed()
+			h.AssertNil(t, err)
+			h.AssertEq(t, lastUsed["some-cache"].Equal(first), true)
+			h.AssertEq(t, lastUsed["other-cache"].Equal(second), true)
+		})
+	})
+
+	when("#Forget", func() {
+		it("removes the records for the given caches", func() {
+			h.AssertNil(t, subject.Touch(time.Now(), "some-cache", "other-cache"))
+			h.AssertNil(t, subject.Forget("some-cache"))
+
+			lastUsed, err := subject.LastUsed()
+			h.AssertNil(t, err)
+			_, ok := lastUsed["some-cache"]
+			h.AssertEq(t, ok, false)
+			_, ok = lastUsed["other-cache"]
+			h.AssertEq(t, ok, true)
+		})
+	})
+}
@@ -5,19 +5,28 @@ import (
 	"crypto/sha256"
 	"fmt"
 
+	"github.com/docker/docker/api/types/volume"
 	"github.com/docker/docker/client"
 	"github.com/google/go-containerregistry/pkg/name"
 )
 
+// ImageLabel records the image reference a cache volume belongs to
+const ImageLabel = "io.buildpacks.pack.cache.image"
+
+// VolumePrefix is the name prefix shared by all cache volumes
+const VolumePrefix = "pack-cache-"
+
 type VolumeCache struct {
 	docker *client.Client
 	volume string
+	image  string
 }
 
 func NewVolumeCache(imageRef name.Reference, suffix string, dockerClient *client.Client) *VolumeCache {
 	sum := sha256.Sum256([]byte(imageRef.String()))
 	return &VolumeCache{
-		volume: fmt.Sprintf("pack-cache-%x.%s", sum[:6], suffix),
+		volume: fmt.Sprintf("%s%x.%s", VolumePrefix, sum[:6], suffix),
+		image:  imageRef.Name(),
 		docker: dockerClient,
 	}
 }
@@ -26,6 +35,19 @@ func (c *VolumeCache) Name() string {
 	return c.volume
 }
 
+func (c *VolumeCache) Type() Type {
+	return Volume
+}
+
+// Create creates the volume labelled with its image reference. An existing volume is left untouched.
+func (c *VolumeCache) Create(ctx context.Context) error {
+	_, err := c.docker.VolumeCreate(ctx, volume.VolumeCreateBody{
+		Name:   c.Name(),
+		Labels: map[string]string{ImageLabel: c.image},
+	})
+	return err
+}
+
 func (c *VolumeCache) Clear(ctx context.Context) error {
 	err := c.docker.VolumeRemove(ctx, c.Name(), true)
 	if err != nil && !client.IsErrNotFound(err) {
@@ -105,6 +105,40 @@ func testCache(t *testing.T, when spec.G, it spec.S) {
 		})
 	})
 
+	when("#Create", func() {
+		var (
+			dockerClient *client.Client
+			subject      *cache.VolumeCache
+		)
+
+		it.Before(func() {
+			var err error
+			dockerClient, err = client.NewClientWithOpts(client.FromEnv, client.WithVersion("1.38"))
+			h.AssertNil(t, err)
+
+			ref, err := name.ParseReference(h.RandString(10), name.WeakValidation)
+			h.AssertNil(t, err)
+			subject = cache.NewVolumeCache(ref, "some-suffix", dockerClient)
+		})
+
+		it.After(func() {
+			h.AssertNil(t, subject.Clear(context.TODO()))
+		})
+
+		it("labels the volume with the image reference", func() {
+			h.AssertNil(t, subject.Create(context.TODO()))
+
+			vol, err := dockerClient.VolumeInspect(context.TODO(), subject.Name())
+			h.AssertNil(t, err)
+			h.AssertContains(t, vol.Labels[cache.ImageLabel], "index.docker.io/library/")
+		})
+
+		it("does not fail when the volume exists", func() {
+			h.AssertNil(t, subject.Create(context.TODO()))
+			h.AssertNil(t, subject.Create(context.TODO()))
+		})
+	})
+
 	when("#Clear", func() {
 		var (
 			volumeName   string
@@ -0,0 +1,43 @@
+package pack
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	h "github.com/buildpack/pack/testhelpers"
+)
+
+func TestCache(t *testing.T) {
+	spec.Run(t, "Cache", testCache, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testCache(t *testing.T, when spec.G, it spec.S) {
+	when("#expiredCaches", func() {
+		var (
+			cutoff = time.Date(2019, 5, 10, 0, 0, 0, 0, time.UTC)
+			before = cutoff.Add(-time.Hour)
+			after  = cutoff.Add(time.Hour)
+		)
+
+		it("selects caches last used before the cutoff", func() {
+			expired := expiredCaches([]CacheInfo{
+				{Name: "old", Created: before, LastUsed: before},
+				{Name: "recent", Created: before, LastUsed: after},
+			}, cutoff)
+			h.AssertEq(t, len(expired), 1)
+			h.AssertEq(t, expired[0].Name, "old")
+		})
+
+		it("falls back to the creation time when no use was recorded", func() {
+			expired := expiredCaches([]CacheInfo{
+				{Name: "old", Created: before},
+				{Name: "new", Created: after},
+			}, cutoff)
+			h.AssertEq(t, len(expired), 1)
+			h.AssertEq(t, expired[0].Name, "old")
+		})
+	})
+}
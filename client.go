@@ -9,8 +9,11 @@ import (
 
 	"github.com/buildpack/pack/blob"
 	"github.com/buildpack/pack/build"
+	"github.com/buildpack/pack/cache"
 	"github.com/buildpack/pack/config"
 	"github.com/buildpack/pack/image"
+	"github.com/buildpack/pack/internal/dockerhost"
+	"github.com/buildpack/pack/internal/registry"
 	"github.com/buildpack/pack/logging"
 )
 
@@ -19,7 +22,11 @@ type Client struct {
 	imageFetcher ImageFetcher
 	downloader   Downloader
 	lifecycle    Lifecycle
+	newLifecycle func(logger logging.Logger) Lifecycle // creates a lifecycle per build of BuildMany
 	docker       *dockerClient.Client
+	cacheUsage   *cache.Usage
+	registries   *registry.Registries
+	cacheDir     string // download cache, defaults to a directory in pack home
 }
 
 type ClientOption func(c *Client)
@@ -31,10 +38,10 @@ func WithLogger(l logging.Logger) ClientOption {
 	}
 }
 
-// WithLogger supply your own logger.
+// WithCacheDir supply your own download cache directory.
 func WithCacheDir(path string) ClientOption {
 	return func(c *Client) {
-		c.downloader = blob.NewDownloader(c.logger, path)
+		c.cacheDir = path
 	}
 }
 
@@ -45,6 +52,13 @@ func WithDockerClient(docker *dockerClient.Client) ClientOption {
 	}
 }
 
+// WithRegistries supply the registry settings of the pack config.
+func WithRegistries(registries []config.Registry) ClientOption {
+	return func(c *Client) {
+		c.registries = registry.New(registries)
+	}
+}
+
 func NewClient(opts ...ClientOption) (*Client, error) {
 	var client Client
 
@@ -58,22 +72,42 @@ func NewClient(opts ...ClientOption) (*Client, error) {
 
 	if client.docker == nil {
 		var err error
-		client.docker, err = dockerClient.NewClientWithOpts(dockerClient.FromEnv, dockerClient.WithVersion("1.38"))
+		client.docker, err = dockerhost.NewClient(os.Getenv(dockerhost.EnvHost))
 		if err != nil {
 			return nil, err
 		}
 	}
 
-	if client.downloader == nil {
-		packHome, err := config.PackHome()
-		if err != nil {
-			return nil, errors.Wrap(err, "getting pack home")
-		}
-		client.downloader = blob.NewDownloader(client.logger, filepath.Join(packHome, "download-cache"))
+	packHome, err := config.PackHome()
+	if err != nil {
+		return nil, errors.Wrap(err, "getting pack home")
 	}
 
-	client.imageFetcher = image.NewFetcher(client.logger, client.docker)
-	client.lifecycle = build.NewLifecycle(client.docker, client.logger)
+	if client.cacheDir == "" {
+		client.cacheDir = filepath.Join(packHome, "download-cache")
+	}
+	client.initLogging()
+
+	client.cacheUsage = cache.NewUsage(filepath.Join(packHome, "cache-usage.toml"))
+
+	client.newLifecycle = func(logger logging.Logger) Lifecycle {
+		return build.NewLifecycle(client.docker, logger)
+	}
 
 	return &client, nil
 }
+
+// ForLogger returns a copy of the client that logs to the given logger, with the same settings otherwise.
+func (c *Client) ForLogger(logger logging.Logger) *Client {
+	client := *c
+	client.logger = logger
+	client.initLogging()
+	return &client
+}
+
+// initLogging creates the components of the client that log
+func (c *Client) initLogging() {
+	c.imageFetcher = image.NewFetcher(c.logger, c.docker, c.registries)
+	c.downloader = blob.NewDownloader(c.logger, c.cacheDir, c.imageFetcher)
+	c.lifecycle = build.NewLifecycle(c.docker, c.logger)
+}
@@ -0,0 +1,40 @@
+package pack
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpack/pack/config"
+	ifakes "github.com/buildpack/pack/internal/fakes"
+	h "github.com/buildpack/pack/testhelpers"
+)
+
+func TestClient(t *testing.T) {
+	spec.Run(t, "Client", testClient, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testClient(t *testing.T, when spec.G, it spec.S) {
+	when("#ForLogger", func() {
+		it("keeps the settings of the client", func() {
+			var out, otherOut bytes.Buffer
+			client, err := NewClient(
+				WithLogger(ifakes.NewFakeLogger(&out)),
+				WithRegistries([]config.Registry{{Name: "example.com", Insecure: true}}),
+				WithCacheDir("some-cache-dir"),
+			)
+			h.AssertNil(t, err)
+
+			otherLogger := ifakes.NewFakeLogger(&otherOut)
+			forked := client.ForLogger(otherLogger)
+
+			h.AssertEq(t, forked.logger == otherLogger, true)
+			h.AssertEq(t, forked.registries == client.registries, true)
+			h.AssertEq(t, forked.docker == client.docker, true)
+			h.AssertEq(t, forked.cacheDir, "some-cache-dir")
+			h.AssertEq(t, client.logger == otherLogger, false)
+		})
+	})
+}
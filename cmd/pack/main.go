@@ -45,7 +45,7 @@ func main() {
 				}
 			}
 
-			packClient = initClient(logger)
+			packClient = initClient(logger, cfg)
 		},
 	}
 	rootCmd.PersistentFlags().Bool("no-color", false, "Disable color output")
@@ -54,10 +54,14 @@ func main() {
 	commands.AddHelpFlag(rootCmd, "pack")
 
 	rootCmd.AddCommand(commands.Build(logger, cfg, &packClient))
+	rootCmd.AddCommand(commands.BuildMany(logger, cfg, &packClient))
 	rootCmd.AddCommand(commands.Run(logger, cfg, &packClient))
 	rootCmd.AddCommand(commands.Rebase(logger, cfg, &packClient))
+	rootCmd.AddCommand(commands.InspectImage(logger, cfg, &packClient))
+	rootCmd.AddCommand(commands.Cache(logger, &packClient))
 
 	rootCmd.AddCommand(commands.CreateBuilder(logger, &packClient))
+	rootCmd.AddCommand(commands.ValidateBuilderConfig(logger))
 	rootCmd.AddCommand(commands.SetRunImagesMirrors(logger, cfg))
 	rootCmd.AddCommand(commands.InspectBuilder(logger, cfg, &packClient))
 	rootCmd.AddCommand(commands.SetDefaultBuilder(logger, cfg, &packClient))
@@ -89,8 +93,8 @@ func initConfig() (config.Config, error) {
 	return cfg, nil
 }
 
-func initClient(logger logging.Logger) pack.Client {
-	client, err := pack.NewClient(pack.WithLogger(logger))
+func initClient(logger logging.Logger, cfg config.Config) pack.Client {
+	client, err := pack.NewClient(pack.WithLogger(logger), pack.WithRegistries(cfg.Registries))
 	if err != nil {
 		exitError(logger, err)
 	}
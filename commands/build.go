@@ -1,82 +1,203 @@
 package commands
 
 import (
+	"bytes"
+	"fmt"
 	"io/ioutil"
 	"os"
 	"strings"
+	"text/tabwriter"
+	"time"
 
+	"github.com/fatih/color"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 
 	"github.com/buildpack/pack"
 	"github.com/buildpack/pack/config"
+	"github.com/buildpack/pack/image"
 	"github.com/buildpack/pack/logging"
 	"github.com/buildpack/pack/style"
 )
 
 type BuildFlags struct {
-	AppPath    string
-	Builder    string
-	RunImage   string
-	Env        []string
-	EnvFile    string
-	Publish    bool
-	NoPull     bool
-	ClearCache bool
-	Buildpacks []string
+	AppPath       string
+	Builder       string
+	RunImage      string
+	Env           []string
+	EnvFile       string
+	Secrets       []string
+	Publish       bool
+	PullPolicy    string
+	NoPull        bool
+	ClearCache    bool
+	CacheImage    string
+	Tags          []string
+	Buildpacks    []string
+	Exclude       []string
+	Network       string
+	Volumes       []string
+	Timeout       time.Duration
+	PhaseTimeouts []string
+	Output        string
 }
 
+const (
+	outputHuman = "human"
+	outputJSON  = "json"
+)
+
 func Build(logger logging.Logger, cfg config.Config, packClient *pack.Client) *cobra.Command {
 	var flags BuildFlags
 	ctx := createCancellableContext()
 
 	cmd := &cobra.Command{
-		Use:   "build <image-name>",
-		Args:  cobra.ExactArgs(1),
+		Use:   "build [<image-name>]",
+		Args:  cobra.MaximumNArgs(1),
 		Short: "Generate app image from source code",
-		RunE: logError(logger, func(cmd *cobra.Command, args []string) error {
-			imageName := args[0]
-			if flags.Builder == "" {
-				suggestSettingBuilder(logger, packClient)
-				return MakeSoftError()
-			}
-			env, err := parseEnv(flags.EnvFile, flags.Env)
-			if err != nil {
-				return err
-			}
-			if err := packClient.Build(ctx, pack.BuildOptions{
-				AppPath:           flags.AppPath,
-				Builder:           flags.Builder,
-				AdditionalMirrors: getMirrors(cfg),
-				RunImage:          flags.RunImage,
-				Env:               env,
-				Image:             imageName,
-				Publish:           flags.Publish,
-				NoPull:            flags.NoPull,
-				ClearCache:        flags.ClearCache,
-				Buildpacks:        flags.Buildpacks,
-			}); err != nil {
-				return err
-			}
-			logger.Infof("Successfully built image %s", style.Symbol(imageName))
-			return nil
-		}),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			buildLogger, buildClient, err := forOutputFormat(flags.Output, logger, packClient)
+			return logError(buildLogger, func(cmd *cobra.Command, args []string) error {
+				if err != nil {
+					return err
+				}
+				var imageName string
+				if len(args) > 0 {
+					imageName = args[0]
+				}
+				env, err := parseEnv(flags.EnvFile, flags.Env)
+				if err != nil {
+					return err
+				}
+				phaseTimeouts, err := parsePhaseTimeouts(flags.PhaseTimeouts)
+				if err != nil {
+					return err
+				}
+				pullPolicy, err := parsePullPolicy(flags.PullPolicy, flags.NoPull)
+				if err != nil {
+					return err
+				}
+				result, err := buildClient.Build(ctx, pack.BuildOptions{
+					AppPath:           flags.AppPath,
+					Builder:           flags.Builder,
+					DefaultBuilder:    cfg.DefaultBuilder,
+					AdditionalMirrors: getMirrors(cfg),
+					RunImage:          flags.RunImage,
+					Env:               env,
+					Secrets:           parseSecrets(flags.Secrets),
+					Image:             imageName,
+					AdditionalTags:    flags.Tags,
+					Publish:           flags.Publish,
+					PullPolicy:        pullPolicy,
+					ClearCache:        flags.ClearCache,
+					CacheImage:        flags.CacheImage,
+					Buildpacks:        flags.Buildpacks,
+					Exclude:           flags.Exclude,
+					Network:           flags.Network,
+					Volumes:           flags.Volumes,
+					Timeout:           flags.Timeout,
+					PhaseTimeouts:     phaseTimeouts,
+				})
+				if errors.Cause(err) == pack.ErrNoBuilder {
+					suggestSettingBuilder(buildLogger, buildClient)
+					return MakeSoftError()
+				}
+				if err != nil {
+					return err
+				}
+				if imageName == "" {
+					imageName = result.Image
+				}
+				if flags.Output == outputJSON {
+					logging.LogEvent(buildLogger, logging.Event{Type: logging.EventResult, Result: result})
+					return nil
+				}
+				buildLogger.Infof("Successfully built image %s", style.Symbol(imageName))
+				logBuildResult(buildLogger, result)
+				return nil
+			})(cmd, args)
+		},
 	}
 	buildCommandFlags(cmd, &flags, cfg)
 	cmd.Flags().BoolVar(&flags.Publish, "publish", false, "Publish to registry")
+	cmd.Flags().StringArrayVarP(&flags.Tags, "tag", "t", nil, "Additional tag to apply to the built image.\nThis flag may be specified multiple times.")
+	cmd.Flags().StringVar(&flags.CacheImage, "cache-image", "", "Registry image used to store the build cache (requires --publish)")
+	cmd.Flags().StringVar(&flags.Output, "output", outputHuman, fmt.Sprintf("Output format, either '%s' or '%s' (one event per line)", outputHuman, outputJSON))
 	AddHelpFlag(cmd, "build")
 	return cmd
 }
 
+// forOutputFormat returns the logger and client that produce the requested output format
+func forOutputFormat(format string, logger logging.Logger, client *pack.Client) (logging.Logger, *pack.Client, error) {
+	switch format {
+	case outputHuman:
+		return logger, client, nil
+	case outputJSON:
+		color.NoColor = true
+		jsonLogger := logging.NewJSONLogger(os.Stdout)
+		return jsonLogger, client.ForLogger(jsonLogger), nil
+	default:
+		return logger, client, fmt.Errorf("invalid output format %s, must be %s or %s", style.Symbol(format), style.Symbol(outputHuman), style.Symbol(outputJSON))
+	}
+}
+
+func logBuildResult(logger logging.Logger, result *pack.BuildResult) {
+	logger.Info("")
+	logger.Info("Build Summary:")
+	logger.Infof("  Image: %s", withDigest(result.Image, result.ImageDigest))
+	if result.ImageID != "" {
+		logger.Infof("  Image ID: %s", result.ImageID)
+	}
+	for _, tag := range result.AdditionalTags {
+		logger.Infof("  Tag: %s", tag)
+	}
+	logger.Infof("  Builder: %s", withDigest(result.Builder, result.BuilderDigest))
+	logger.Infof("  Run Image: %s", withDigest(result.RunImage, result.RunImageDigest))
+
+	if len(result.Buildpacks) > 0 {
+		logger.Info("  Buildpacks:")
+		for _, bp := range result.Buildpacks {
+			logger.Infof("    %s@%s", bp.ID, bp.Version)
+		}
+	}
+
+	logger.Info("  Caches:")
+	logger.Infof("    Build: %s", result.BuildCache)
+	logger.Infof("    Launch: %s", result.LaunchCache)
+
+	buf := &bytes.Buffer{}
+	tabWriter := new(tabwriter.Writer).Init(buf, 0, 0, 3, ' ', 0)
+	for _, phase := range result.PhaseTimings {
+		fmt.Fprintf(tabWriter, "\n    %s\t%s", phase.Name, phase.Duration.Round(time.Millisecond))
+	}
+	if err := tabWriter.Flush(); err != nil {
+		logger.Error(err.Error())
+	}
+	logger.Info("  Phases:" + buf.String())
+}
+
+func withDigest(name, digest string) string {
+	if digest == "" {
+		return name
+	}
+	return name + "@" + digest
+}
+
 func buildCommandFlags(cmd *cobra.Command, buildFlags *BuildFlags, cfg config.Config) {
 	cmd.Flags().StringVarP(&buildFlags.AppPath, "path", "p", "", "Path to app dir or zip-formatted file (defaults to current working directory)")
-	cmd.Flags().StringVar(&buildFlags.Builder, "builder", cfg.DefaultBuilder, "Builder image")
+	cmd.Flags().StringVar(&buildFlags.Builder, "builder", "", "Builder image (defaults to the builder in the app's project.toml, then the default builder)")
 	cmd.Flags().StringVar(&buildFlags.RunImage, "run-image", "", "Run image (defaults to default stack's run image)")
 	cmd.Flags().StringArrayVarP(&buildFlags.Env, "env", "e", []string{}, "Build-time environment variable, in the form 'VAR=VALUE' or 'VAR'.\nWhen using latter value-less form, value will be taken from current\n  environment at the time this command is executed.\nThis flag may be specified multiple times and will override\n  individual values defined by --env-file.")
+	cmd.Flags().StringArrayVar(&buildFlags.Secrets, "secret", nil, "Build-time secret, in the form 'VAR=VALUE' or 'VAR'.\nAvailable to buildpacks like an environment variable during detect and build,\n  but never written to an image and redacted from the build output.\nThis flag may be specified multiple times.")
 	cmd.Flags().StringVar(&buildFlags.EnvFile, "env-file", "", "Build-time environment variables file\nOne variable per line, of the form 'VAR=VALUE' or 'VAR'\nWhen using latter value-less form, value will be taken from current\n  environment at the time this command is executed")
-	cmd.Flags().BoolVar(&buildFlags.NoPull, "no-pull", false, "Skip pulling builder and run images before use")
+	addPullPolicyFlags(cmd, &buildFlags.PullPolicy, &buildFlags.NoPull, "builder and run images")
 	cmd.Flags().BoolVar(&buildFlags.ClearCache, "clear-cache", false, "Clear image's associated cache before building")
-	cmd.Flags().StringSliceVar(&buildFlags.Buildpacks, "buildpack", nil, "Buildpack ID, path to a Buildpack directory, or path/URL to a Buildpack .tgz file"+multiValueHelp("buildpack"))
+	cmd.Flags().StringSliceVar(&buildFlags.Buildpacks, "buildpack", nil, "Buildpack ID, path to a Buildpack directory, path/URL to a Buildpack .tgz file,\n  or buildpack image in the form 'docker://<image>'"+multiValueHelp("buildpack"))
+	cmd.Flags().StringVar(&buildFlags.Network, "network", "", "Docker network to run the lifecycle containers on, e.g. 'none' for offline builds or a user-defined network")
+	cmd.Flags().StringArrayVar(&buildFlags.Volumes, "volume", nil, "Mount a host volume into the detect and build phases, in the form '<host path>:<target path>[:<mode>]'.\n<mode> is 'ro' (default) or 'rw'.\nThis flag may be specified multiple times.")
+	cmd.Flags().DurationVar(&buildFlags.Timeout, "timeout", 0, "Fail the build if it takes longer than this, e.g. '30m' (no timeout by default)")
+	cmd.Flags().StringArrayVar(&buildFlags.PhaseTimeouts, "phase-timeout", nil, "Timeout for a single lifecycle phase, in the form '<phase>=<duration>', e.g. 'builder=20m'.\nThis flag may be specified multiple times.")
+	cmd.Flags().StringArrayVar(&buildFlags.Exclude, "exclude", nil, "Gitignore-style pattern of app files to leave out of the build.\nApplied after patterns in the app's .packignore file.\nThis flag may be specified multiple times.")
 }
 
 func parseEnv(envFile string, envVars []string) (map[string]string, error) {
@@ -94,6 +215,50 @@ func parseEnv(envFile string, envVars []string) (map[string]string, error) {
 	return env, nil
 }
 
+// addPullPolicyFlags adds --pull-policy, and --no-pull which is kept as a deprecated alias of '--pull-policy never'
+func addPullPolicyFlags(cmd *cobra.Command, pullPolicy *string, noPull *bool, images string) {
+	cmd.Flags().StringVar(pullPolicy, "pull-policy", image.PullAlways.String(), fmt.Sprintf("When to pull the %s: 'always', 'if-not-present' (only when missing on the daemon)\n  or 'never' (use the images on the daemon without contacting the registry)", images))
+	cmd.Flags().BoolVar(noPull, "no-pull", false, fmt.Sprintf("Skip pulling %s before use", images))
+	cmd.Flags().MarkDeprecated("no-pull", "use '--pull-policy never' instead")
+}
+
+func parsePullPolicy(name string, noPull bool) (image.PullPolicy, error) {
+	if noPull {
+		return image.PullNever, nil
+	}
+	return image.ParsePullPolicy(name)
+}
+
+func parsePhaseTimeouts(values []string) (map[string]time.Duration, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+	timeouts := map[string]time.Duration{}
+	for _, value := range values {
+		parts := strings.SplitN(value, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("phase timeout %s must be in the form %s", style.Symbol(value), style.Symbol("<phase>=<duration>"))
+		}
+		timeout, err := time.ParseDuration(parts[1])
+		if err != nil {
+			return nil, errors.Wrapf(err, "parsing phase timeout %s", style.Symbol(value))
+		}
+		timeouts[parts[0]] = timeout
+	}
+	return timeouts, nil
+}
+
+func parseSecrets(secrets []string) map[string]string {
+	if len(secrets) == 0 {
+		return nil
+	}
+	out := map[string]string{}
+	for _, secret := range secrets {
+		out = addEnvVar(out, secret)
+	}
+	return out
+}
+
 func parseEnvFile(filename string) (map[string]string, error) {
 	out := make(map[string]string, 0)
 	f, err := ioutil.ReadFile(filename)
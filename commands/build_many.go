@@ -0,0 +1,133 @@
+package commands
+
+import (
+	"bytes"
+	"fmt"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/buildpack/pack"
+	"github.com/buildpack/pack/config"
+	"github.com/buildpack/pack/logging"
+	"github.com/buildpack/pack/project"
+	"github.com/buildpack/pack/style"
+)
+
+type BuildManyFlags struct {
+	Builder     string
+	Publish     bool
+	PullPolicy  string
+	NoPull      bool
+	ClearCache  bool
+	Network     string
+	Timeout     time.Duration
+	Concurrency int
+}
+
+func BuildMany(logger logging.Logger, cfg config.Config, client PackClient) *cobra.Command {
+	var flags BuildManyFlags
+	ctx := createCancellableContext()
+
+	cmd := &cobra.Command{
+		Use:   "build-many <manifest>",
+		Args:  cobra.ExactArgs(1),
+		Short: "Generate app images for each app listed in a manifest",
+		Long: `Generate app images for each app listed in a manifest, for example:
+
+[[apps]]
+path = "api"                 # relative to the manifest
+image = "example.com/api"
+builder = "some/builder"     # optional, overrides --builder
+
+[[apps]]
+path = "web"
+image = "example.com/web"`,
+		RunE: logError(logger, func(cmd *cobra.Command, args []string) error {
+			manifest, err := project.ReadManifest(args[0])
+			if err != nil {
+				return err
+			}
+			pullPolicy, err := parsePullPolicy(flags.PullPolicy, flags.NoPull)
+			if err != nil {
+				return err
+			}
+
+			var builds []pack.BuildOptions
+			for _, app := range manifest.Apps {
+				builderName := flags.Builder
+				if app.Builder != "" {
+					builderName = app.Builder
+				}
+				builds = append(builds, pack.BuildOptions{
+					AppPath:           app.Path,
+					Image:             app.Image,
+					Builder:           builderName,
+					DefaultBuilder:    cfg.DefaultBuilder,
+					AdditionalMirrors: getMirrors(cfg),
+					RunImage:          app.RunImage,
+					Env:               app.EnvMap(),
+					Buildpacks:        app.Buildpacks,
+					Publish:           flags.Publish,
+					PullPolicy:        pullPolicy,
+					ClearCache:        flags.ClearCache,
+					Network:           flags.Network,
+					Timeout:           flags.Timeout,
+				})
+			}
+
+			results, err := client.BuildMany(ctx, pack.BuildManyOptions{
+				Builds:      builds,
+				Concurrency: flags.Concurrency,
+			})
+			if len(results) > 0 {
+				logBuildManyResults(logger, results)
+			}
+			if err != nil {
+				return err
+			}
+			logger.Infof("Successfully built %d app(s)", len(results))
+			return nil
+		}),
+	}
+	cmd.Flags().StringVar(&flags.Builder, "builder", "", "Builder image for apps that do not set one (defaults to the builder in the app's project.toml, then the default builder)")
+	cmd.Flags().BoolVar(&flags.Publish, "publish", false, "Publish to registry")
+	addPullPolicyFlags(cmd, &flags.PullPolicy, &flags.NoPull, "builder and run images")
+	cmd.Flags().BoolVar(&flags.ClearCache, "clear-cache", false, "Clear each image's associated cache before building")
+	cmd.Flags().StringVar(&flags.Network, "network", "", "Docker network to run the lifecycle containers on")
+	cmd.Flags().DurationVar(&flags.Timeout, "timeout", 0, "Fail a build if it takes longer than this, e.g. '30m' (no timeout by default)")
+	cmd.Flags().IntVar(&flags.Concurrency, "concurrency", 2, "Maximum number of apps built at once")
+	AddHelpFlag(cmd, "build-many")
+	return cmd
+}
+
+func logBuildManyResults(logger logging.Logger, results []pack.AppBuildResult) {
+	buf := &bytes.Buffer{}
+	tabWriter := new(tabwriter.Writer).Init(buf, 0, 0, 3, ' ', 0)
+	fmt.Fprint(tabWriter, "APP\tIMAGE\tRESULT\tDURATION")
+	for _, result := range results {
+		status := "succeeded"
+		if result.Err != nil {
+			status = "failed"
+		}
+		image := result.Image
+		if image == "" {
+			image = "<unknown>"
+		}
+		fmt.Fprintf(tabWriter, "\n%s\t%s\t%s\t%s", result.AppPath, image, status, result.Duration.Round(time.Millisecond))
+	}
+	if err := tabWriter.Flush(); err != nil {
+		logger.Error(err.Error())
+	}
+
+	logger.Info("")
+	logger.Info("Build Summary:")
+	logger.Info(buf.String())
+
+	for _, result := range results {
+		if result.Err != nil {
+			logger.Errorf("Failed to build %s: %s", style.Symbol(result.AppPath), result.Err)
+		}
+	}
+}
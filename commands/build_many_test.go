@@ -0,0 +1,129 @@
+package commands_test
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/golang/mock/gomock"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpack/pack"
+	"github.com/buildpack/pack/commands"
+	cmdmocks "github.com/buildpack/pack/commands/mocks"
+	"github.com/buildpack/pack/config"
+	"github.com/buildpack/pack/internal/fakes"
+	"github.com/buildpack/pack/logging"
+	h "github.com/buildpack/pack/testhelpers"
+)
+
+func TestBuildManyCommand(t *testing.T) {
+	color.NoColor = true
+	spec.Run(t, "Commands", testBuildManyCommand, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testBuildManyCommand(t *testing.T, when spec.G, it spec.S) {
+	var (
+		command        *cobra.Command
+		logger         logging.Logger
+		outBuf         bytes.Buffer
+		mockController *gomock.Controller
+		mockClient     *cmdmocks.MockPackClient
+		tmpDir         string
+		manifestPath   string
+	)
+
+	it.Before(func() {
+		var err error
+		tmpDir, err = ioutil.TempDir("", "build-many-command-test")
+		h.AssertNil(t, err)
+		manifestPath = filepath.Join(tmpDir, "apps.toml")
+		h.AssertNil(t, ioutil.WriteFile(manifestPath, []byte(`
+[[apps]]
+path = "some-app"
+image = "some/app"
+
+[[apps]]
+path = "other-app"
+image = "other/app"
+builder = "other/builder"
+`), 0644))
+
+		mockController = gomock.NewController(t)
+		mockClient = cmdmocks.NewMockPackClient(mockController)
+		logger = fakes.NewFakeLogger(&outBuf)
+
+		command = commands.BuildMany(logger, config.Config{DefaultBuilder: "default/builder"}, mockClient)
+	})
+
+	it.After(func() {
+		mockController.Finish()
+		os.RemoveAll(tmpDir)
+	})
+
+	when("#BuildMany", func() {
+		it("builds each app in the manifest with the shared flags", func() {
+			mockClient.EXPECT().BuildMany(gomock.Any(), pack.BuildManyOptions{
+				Builds: []pack.BuildOptions{
+					{
+						AppPath:           filepath.Join(tmpDir, "some-app"),
+						Image:             "some/app",
+						Builder:           "some/builder",
+						DefaultBuilder:    "default/builder",
+						AdditionalMirrors: map[string][]string{},
+						Env:               map[string]string{},
+						Publish:           true,
+					},
+					{
+						AppPath:           filepath.Join(tmpDir, "other-app"),
+						Image:             "other/app",
+						Builder:           "other/builder",
+						DefaultBuilder:    "default/builder",
+						AdditionalMirrors: map[string][]string{},
+						Env:               map[string]string{},
+						Publish:           true,
+					},
+				},
+				Concurrency: 3,
+			}).Return([]pack.AppBuildResult{
+				{AppPath: "some-app", Image: "index.docker.io/some/app:latest", Duration: 2 * time.Second},
+				{AppPath: "other-app", Image: "index.docker.io/other/app:latest", Duration: time.Second},
+			}, nil)
+
+			command.SetArgs([]string{manifestPath, "--builder", "some/builder", "--publish", "--concurrency", "3"})
+			h.AssertNil(t, command.Execute())
+
+			h.AssertContains(t, outBuf.String(), `APP         IMAGE                              RESULT      DURATION
+some-app    index.docker.io/some/app:latest    succeeded   2s
+other-app   index.docker.io/other/app:latest   succeeded   1s
+`)
+			h.AssertContains(t, outBuf.String(), "Successfully built 2 app(s)")
+		})
+
+		it("reports the apps that failed to build", func() {
+			mockClient.EXPECT().BuildMany(gomock.Any(), gomock.Any()).Return([]pack.AppBuildResult{
+				{AppPath: "some-app", Image: "index.docker.io/some/app:latest", Duration: 2 * time.Second},
+				{AppPath: "other-app", Image: "other/app", Err: errors.New("some-error")},
+			}, errors.New("1 of 2 builds failed"))
+
+			command.SetArgs([]string{manifestPath})
+			h.AssertError(t, command.Execute(), "1 of 2 builds failed")
+
+			h.AssertContains(t, outBuf.String(), "other-app   other/app                         failed")
+			h.AssertContains(t, outBuf.String(), "ERROR: Failed to build 'other-app': some-error")
+			h.AssertNotContains(t, outBuf.String(), "Successfully built")
+		})
+
+		it("fails when the manifest cannot be read", func() {
+			command.SetArgs([]string{filepath.Join(tmpDir, "missing.toml")})
+			h.AssertError(t, command.Execute(), "failed to read")
+		})
+	})
+}
@@ -0,0 +1,195 @@
+package commands
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpack/pack/logging"
+	"github.com/buildpack/pack/style"
+)
+
+func Cache(logger logging.Logger, client PackClient) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cache",
+		Short: "Manage build and launch cache volumes",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+	cmd.AddCommand(listCaches(logger, client))
+	cmd.AddCommand(inspectCache(logger, client))
+	cmd.AddCommand(pruneCaches(logger, client))
+	AddHelpFlag(cmd, "cache")
+	return cmd
+}
+
+func listCaches(logger logging.Logger, client PackClient) *cobra.Command {
+	ctx := createCancellableContext()
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List cache volumes",
+		Args:  cobra.NoArgs,
+		RunE: logError(logger, func(cmd *cobra.Command, args []string) error {
+			caches, err := client.ListCaches(ctx)
+			if err != nil {
+				return err
+			}
+
+			if len(caches) == 0 {
+				logger.Info("No caches found")
+				return nil
+			}
+
+			buf := &bytes.Buffer{}
+			tabWriter := new(tabwriter.Writer).Init(buf, 0, 0, 3, ' ', 0)
+			fmt.Fprint(tabWriter, "NAME\tIMAGE\tSIZE\tCREATED\tLAST USED")
+			for _, info := range caches {
+				image := info.Image
+				if image == "" {
+					image = "<unknown>"
+				}
+				fmt.Fprintf(tabWriter, "\n%s\t%s\t%s\t%s\t%s", info.Name, image, formatSize(info.Size), formatAge(info.Created), formatAge(info.LastUsed))
+			}
+			if err := tabWriter.Flush(); err != nil {
+				return err
+			}
+
+			logger.Info(buf.String())
+			return nil
+		}),
+	}
+	AddHelpFlag(cmd, "cache list")
+	return cmd
+}
+
+func inspectCache(logger logging.Logger, client PackClient) *cobra.Command {
+	ctx := createCancellableContext()
+	cmd := &cobra.Command{
+		Use:   "inspect <image-name>",
+		Short: "Show the cache volumes of an image",
+		Args:  cobra.ExactArgs(1),
+		RunE: logError(logger, func(cmd *cobra.Command, args []string) error {
+			imageName := args[0]
+			caches, err := client.InspectCache(ctx, imageName)
+			if err != nil {
+				return err
+			}
+
+			if len(caches) == 0 {
+				logger.Infof("No caches found for image %s", style.Symbol(imageName))
+				return nil
+			}
+
+			logger.Infof("Caches for image %s:", style.Symbol(imageName))
+			for _, info := range caches {
+				logger.Info("")
+				logger.Infof("  %s", info.Name)
+				logger.Infof("    Size: %s", formatSize(info.Size))
+				logger.Infof("    Created: %s", formatAge(info.Created))
+				logger.Infof("    Last Used: %s", formatAge(info.LastUsed))
+			}
+			return nil
+		}),
+	}
+	AddHelpFlag(cmd, "cache inspect")
+	return cmd
+}
+
+func pruneCaches(logger logging.Logger, client PackClient) *cobra.Command {
+	var olderThan string
+	ctx := createCancellableContext()
+	cmd := &cobra.Command{
+		Use:   "prune --older-than <duration>",
+		Short: "Remove cache volumes that have not been used recently",
+		Args:  cobra.NoArgs,
+		RunE: logError(logger, func(cmd *cobra.Command, args []string) error {
+			age, err := parseAge(olderThan)
+			if err != nil {
+				return errors.Wrapf(err, "invalid duration %s", style.Symbol(olderThan))
+			}
+
+			removed, err := client.PruneCaches(ctx, age)
+			if err != nil {
+				return err
+			}
+
+			var reclaimed int64
+			for _, info := range removed {
+				logger.Infof("Removed cache %s", style.Symbol(info.Name))
+				if info.Size > 0 {
+					reclaimed += info.Size
+				}
+			}
+			logger.Infof("Successfully pruned %d cache(s), reclaiming %s", len(removed), formatSize(reclaimed))
+			return nil
+		}),
+	}
+	cmd.Flags().StringVar(&olderThan, "older-than", "", "Remove caches not used within this duration, e.g. '72h' or '7d' (required)")
+	cmd.MarkFlagRequired("older-than")
+	AddHelpFlag(cmd, "cache prune")
+	return cmd
+}
+
+// parseAge extends time.ParseDuration with a 'd' suffix for whole days
+func parseAge(value string) (time.Duration, error) {
+	var (
+		age time.Duration
+		err error
+	)
+	if strings.HasSuffix(value, "d") {
+		var days int
+		days, err = strconv.Atoi(strings.TrimSuffix(value, "d"))
+		age = time.Duration(days) * 24 * time.Hour
+	} else {
+		age, err = time.ParseDuration(value)
+	}
+	if err != nil {
+		return 0, err
+	}
+	if age < 0 {
+		return 0, errors.New("duration must not be negative")
+	}
+	return age, nil
+}
+
+func formatSize(size int64) string {
+	if size < 0 {
+		return "-"
+	}
+	units := []string{"B", "KB", "MB", "GB", "TB"}
+	value := float64(size)
+	i := 0
+	for value >= 1000 && i < len(units)-1 {
+		value /= 1000
+		i++
+	}
+	if i == 0 {
+		return fmt.Sprintf("%d%s", size, units[i])
+	}
+	return fmt.Sprintf("%.1f%s", value, units[i])
+}
+
+func formatAge(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	d := time.Since(t)
+	switch {
+	case d < time.Minute:
+		return "less than a minute ago"
+	case d < time.Hour:
+		return fmt.Sprintf("%d minute(s) ago", int(d.Minutes()))
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%d hour(s) ago", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%d day(s) ago", int(d.Hours()/24))
+	}
+}
@@ -0,0 +1,140 @@
+package commands_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpack/pack"
+	"github.com/buildpack/pack/commands"
+	cmdmocks "github.com/buildpack/pack/commands/mocks"
+	"github.com/buildpack/pack/internal/fakes"
+	"github.com/buildpack/pack/logging"
+	h "github.com/buildpack/pack/testhelpers"
+)
+
+func TestCacheCommand(t *testing.T) {
+	spec.Run(t, "Commands", testCacheCommand, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testCacheCommand(t *testing.T, when spec.G, it spec.S) {
+	var (
+		command        *cobra.Command
+		logger         logging.Logger
+		outBuf         bytes.Buffer
+		mockController *gomock.Controller
+		mockClient     *cmdmocks.MockPackClient
+	)
+
+	it.Before(func() {
+		mockController = gomock.NewController(t)
+		mockClient = cmdmocks.NewMockPackClient(mockController)
+		logger = fakes.NewFakeLogger(&outBuf)
+
+		command = commands.Cache(logger, mockClient)
+	})
+
+	it.After(func() {
+		mockController.Finish()
+	})
+
+	when("list", func() {
+		it("displays each cache", func() {
+			mockClient.EXPECT().ListCaches(gomock.Any()).Return([]pack.CacheInfo{
+				{
+					Name:     "pack-cache-abc.build",
+					Image:    "index.docker.io/some/app:latest",
+					Size:     2500000,
+					Created:  time.Now().Add(-72 * time.Hour),
+					LastUsed: time.Now().Add(-2 * time.Hour),
+				},
+				{
+					Name: "pack-cache-def.launch",
+					Size: -1,
+				},
+			}, nil)
+
+			command.SetArgs([]string{"list"})
+			h.AssertNil(t, command.Execute())
+
+			h.AssertContains(t, outBuf.String(), `NAME                    IMAGE                             SIZE    CREATED        LAST USED
+pack-cache-abc.build    index.docker.io/some/app:latest   2.5MB   3 day(s) ago   2 hour(s) ago
+pack-cache-def.launch   <unknown>                         -       -              -
+`)
+		})
+
+		it("reports when there are no caches", func() {
+			mockClient.EXPECT().ListCaches(gomock.Any()).Return(nil, nil)
+
+			command.SetArgs([]string{"list"})
+			h.AssertNil(t, command.Execute())
+
+			h.AssertContains(t, outBuf.String(), "No caches found")
+		})
+	})
+
+	when("inspect", func() {
+		it("displays the caches of the image", func() {
+			mockClient.EXPECT().InspectCache(gomock.Any(), "some/app").Return([]pack.CacheInfo{
+				{
+					Name:    "pack-cache-abc.build",
+					Size:    512,
+					Created: time.Now().Add(-30 * time.Minute),
+				},
+			}, nil)
+
+			command.SetArgs([]string{"inspect", "some/app"})
+			h.AssertNil(t, command.Execute())
+
+			h.AssertContains(t, outBuf.String(), `Caches for image 'some/app':
+
+  pack-cache-abc.build
+    Size: 512B
+    Created: 30 minute(s) ago
+    Last Used: -
+`)
+		})
+
+		it("reports when the image has no caches", func() {
+			mockClient.EXPECT().InspectCache(gomock.Any(), "some/app").Return(nil, nil)
+
+			command.SetArgs([]string{"inspect", "some/app"})
+			h.AssertNil(t, command.Execute())
+
+			h.AssertContains(t, outBuf.String(), "No caches found for image 'some/app'")
+		})
+	})
+
+	when("prune", func() {
+		it("removes caches older than the given duration", func() {
+			mockClient.EXPECT().PruneCaches(gomock.Any(), 36*time.Hour).Return([]pack.CacheInfo{
+				{Name: "pack-cache-abc.build", Size: 1500},
+				{Name: "pack-cache-abc.launch", Size: -1},
+			}, nil)
+
+			command.SetArgs([]string{"prune", "--older-than", "36h"})
+			h.AssertNil(t, command.Execute())
+
+			h.AssertContains(t, outBuf.String(), "Removed cache 'pack-cache-abc.build'")
+			h.AssertContains(t, outBuf.String(), "Removed cache 'pack-cache-abc.launch'")
+			h.AssertContains(t, outBuf.String(), "Successfully pruned 2 cache(s), reclaiming 1.5KB")
+		})
+
+		it("accepts a duration in days", func() {
+			mockClient.EXPECT().PruneCaches(gomock.Any(), 7*24*time.Hour).Return(nil, nil)
+
+			command.SetArgs([]string{"prune", "--older-than", "7d"})
+			h.AssertNil(t, command.Execute())
+		})
+
+		it("errors on an invalid duration", func() {
+			command.SetArgs([]string{"prune", "--older-than", "soon"})
+			h.AssertError(t, command.Execute(), "invalid duration 'soon'")
+		})
+	})
+}
@@ -8,6 +8,7 @@ import (
 	"os/signal"
 	"syscall"
 	"text/tabwriter"
+	"time"
 
 	"github.com/spf13/cobra"
 
@@ -20,8 +21,14 @@ import (
 //go:generate mockgen -package mocks -destination mocks/pack_client.go github.com/buildpack/pack/commands PackClient
 type PackClient interface {
 	InspectBuilder(string, bool) (*pack.BuilderInfo, error)
-	Rebase(context.Context, pack.RebaseOptions) error
+	InspectImage(context.Context, string, bool) (*pack.ImageInfo, error)
+	Rebase(context.Context, pack.RebaseOptions) (*pack.RebaseResult, error)
+	RebaseMany(context.Context, pack.RebaseManyOptions) ([]pack.ImageRebaseResult, error)
+	BuildMany(context.Context, pack.BuildManyOptions) ([]pack.AppBuildResult, error)
 	CreateBuilder(context.Context, pack.CreateBuilderOptions) error
+	ListCaches(context.Context) ([]pack.CacheInfo, error)
+	InspectCache(context.Context, string) ([]pack.CacheInfo, error)
+	PruneCaches(context.Context, time.Duration) ([]pack.CacheInfo, error)
 }
 
 type suggestedBuilder struct {
@@ -15,6 +15,7 @@ import (
 type CreateBuilderFlags struct {
 	BuilderTomlPath string
 	Publish         bool
+	PullPolicy      string
 	NoPull          bool
 }
 
@@ -34,12 +35,17 @@ func CreateBuilder(logger logging.Logger, client PackClient) *cobra.Command {
 				logger.Warnf("builder configuration: %s", w)
 			}
 
+			pullPolicy, err := parsePullPolicy(flags.PullPolicy, flags.NoPull)
+			if err != nil {
+				return err
+			}
+
 			imageName := args[0]
 			if err := client.CreateBuilder(ctx, pack.CreateBuilderOptions{
 				BuilderName:   imageName,
 				BuilderConfig: builderConfig,
 				Publish:       flags.Publish,
-				NoPull:        flags.NoPull,
+				PullPolicy:    pullPolicy,
 			}); err != nil {
 				return err
 			}
@@ -48,7 +54,7 @@ func CreateBuilder(logger logging.Logger, client PackClient) *cobra.Command {
 			return nil
 		}),
 	}
-	cmd.Flags().BoolVar(&flags.NoPull, "no-pull", false, "Skip pulling build image before use")
+	addPullPolicyFlags(cmd, &flags.PullPolicy, &flags.NoPull, "build image or base builder")
 	cmd.Flags().StringVarP(&flags.BuilderTomlPath, "builder-config", "b", "", "Path to builder TOML file (required)")
 	cmd.MarkFlagRequired("builder-config")
 	cmd.Flags().BoolVar(&flags.Publish, "publish", false, "Publish to registry")
@@ -12,8 +12,10 @@ import (
 	"github.com/sclevine/spec/report"
 	"github.com/spf13/cobra"
 
+	"github.com/buildpack/pack"
 	"github.com/buildpack/pack/commands"
 	cmdmocks "github.com/buildpack/pack/commands/mocks"
+	"github.com/buildpack/pack/image"
 	"github.com/buildpack/pack/internal/fakes"
 	"github.com/buildpack/pack/logging"
 	h "github.com/buildpack/pack/testhelpers"
@@ -74,5 +76,63 @@ func testCreateBuilderCommand(t *testing.T, when spec.G, it spec.S) {
 				h.AssertContains(t, outBuf.String(), "Warning: builder configuration: empty 'order' definition")
 			})
 		})
+
+		when("a pull policy is given", func() {
+			it.Before(func() {
+				h.AssertNil(t, ioutil.WriteFile(builderConfigPath, []byte(`
+[[order]]
+  [[order.group]]
+  id = "some.buildpack"
+`), 0666))
+			})
+
+			it("passes the pull policy", func() {
+				mockClient.EXPECT().CreateBuilder(gomock.Any(), eqPullPolicy(image.PullIfNotPresent)).Return(nil)
+
+				command.SetArgs([]string{
+					"some/builder",
+					"--builder-config", builderConfigPath,
+					"--pull-policy", "if-not-present",
+				})
+				h.AssertNil(t, command.Execute())
+			})
+
+			it("treats --no-pull as pull policy never", func() {
+				mockClient.EXPECT().CreateBuilder(gomock.Any(), eqPullPolicy(image.PullNever)).Return(nil)
+
+				command.SetArgs([]string{
+					"some/builder",
+					"--builder-config", builderConfigPath,
+					"--no-pull",
+				})
+				h.AssertNil(t, command.Execute())
+			})
+
+			it("fails for an unknown pull policy", func() {
+				command.SetArgs([]string{
+					"some/builder",
+					"--builder-config", builderConfigPath,
+					"--pull-policy", "sometimes",
+				})
+				h.AssertError(t, command.Execute(), "invalid pull policy 'sometimes'")
+			})
+		})
 	})
 }
+
+func eqPullPolicy(policy image.PullPolicy) gomock.Matcher {
+	return pullPolicyMatcher{policy: policy}
+}
+
+type pullPolicyMatcher struct {
+	policy image.PullPolicy
+}
+
+func (m pullPolicyMatcher) Matches(x interface{}) bool {
+	opts, ok := x.(pack.CreateBuilderOptions)
+	return ok && opts.PullPolicy == m.policy
+}
+
+func (m pullPolicyMatcher) String() string {
+	return "has pull policy " + m.policy.String()
+}
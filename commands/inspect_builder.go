@@ -3,6 +3,8 @@ package commands
 import (
 	"bytes"
 	"fmt"
+	"io"
+	"strings"
 	"text/tabwriter"
 
 	"github.com/spf13/cobra"
@@ -55,7 +57,6 @@ func InspectBuilder(logger logging.Logger, cfg config.Config, client PackClient)
 	return cmd
 }
 
-// TODO: present buildpack order (inc. nested) [https://github.com/buildpack/pack/issues/253].
 func inspectBuilderOutput(logger logging.Logger, client PackClient, imageName string, local bool, cfg config.Config) {
 	info, err := client.InspectBuilder(imageName, local)
 	if err != nil {
@@ -153,32 +154,77 @@ func logBuildpacksInfo(logger logging.Logger, info *pack.BuilderInfo) {
 }
 
 func logDetectionOrderInfo(logger logging.Logger, info *pack.BuilderInfo) {
+	orders := map[string]builder.Order{}
+	for _, bp := range info.Buildpacks {
+		if len(bp.Order) > 0 {
+			orders[bp.ID+"@"+bp.Version] = bp.Order
+		}
+	}
+
+	buf := &bytes.Buffer{}
+	tabWriter := new(tabwriter.Writer).Init(buf, 0, 0, 4, ' ', 0)
+	writeDetectionOrder(tabWriter, info.Groups, info.Buildpacks, orders, "  ", nil)
+	if err := tabWriter.Flush(); err != nil {
+		logger.Error(err.Error())
+	}
+
+	var lines []string
+	for _, line := range strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n") {
+		lines = append(lines, strings.TrimRight(line, " "))
+	}
 	logger.Info("\nDetection Order:")
-	for i, group := range info.Groups {
-		logger.Infof("  Group #%d:", i+1)
-		buf := &bytes.Buffer{}
-		tabWriter := new(tabwriter.Writer).Init(buf, 0, 0, 4, ' ', 0)
-		for i, bp := range group.Group {
-			var optional string
+	logger.Info(strings.Join(lines, "\n"))
+}
+
+// writeDetectionOrder writes the groups of the order as a tree, expanding order buildpacks into their own groups.
+// The path holds the order buildpacks being expanded, so that cycles between them are marked instead of expanded.
+func writeDetectionOrder(w io.Writer, order builder.Order, buildpacks []builder.BuildpackMetadata, orders map[string]builder.Order, indent string, path []string) {
+	for i, group := range order {
+		fmt.Fprintf(w, "%sGroup #%d:\n", indent, i+1)
+		for _, bp := range group.Group {
+			ref := bp.ID + "@" + resolveBuildpackVersion(bp.BuildpackInfo, buildpacks)
+
+			var markers []string
 			if bp.Optional {
-				optional = "(optional)"
+				markers = append(markers, "(optional)")
 			}
-
-			if _, err := fmt.Fprintf(tabWriter, "    %s@%s\t%s", bp.ID, bp.Version, optional); err != nil {
-				logger.Error(err.Error())
+			cyclic := containsString(path, ref)
+			if cyclic {
+				markers = append(markers, "(cycle detected)")
 			}
+			fmt.Fprintf(w, "%s  %s\t%s\n", indent, ref, strings.Join(markers, " "))
 
-			if i < len(group.Group)-1 {
-				if _, err := fmt.Fprint(tabWriter, "\n"); err != nil {
-					logger.Error(err.Error())
-				}
+			if nested, ok := orders[ref]; ok && !cyclic {
+				writeDetectionOrder(w, nested, buildpacks, orders, indent+"    ", append(path, ref))
 			}
 		}
-		if err := tabWriter.Flush(); err != nil {
-			logger.Error(err.Error())
+	}
+}
+
+// resolveBuildpackVersion returns the version of the buildpack, or the only version on the builder if it has none
+func resolveBuildpackVersion(bp builder.BuildpackInfo, buildpacks []builder.BuildpackMetadata) string {
+	if bp.Version != "" {
+		return bp.Version
+	}
+	var versions []string
+	for _, candidate := range buildpacks {
+		if candidate.ID == bp.ID {
+			versions = append(versions, candidate.Version)
+		}
+	}
+	if len(versions) == 1 {
+		return versions[0]
+	}
+	return "(unresolved)"
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
 		}
-		logger.Info(buf.String())
 	}
+	return false
 }
 
 func getLocalMirrors(runImage string, cfg config.Config) []string {
@@ -338,6 +338,51 @@ Detection Order:
     test.bp.one@1.0.0
   Group #2:
     test.bp.two@2.0.0    (optional)
+`)
+				})
+			})
+
+			when("the builder has order buildpacks", func() {
+				it.Before(func() {
+					one := builder.BuildpackInfo{ID: "test.bp.one", Version: "1.0.0"}
+					two := builder.BuildpackInfo{ID: "test.bp.two", Version: "2.0.0"}
+					meta := builder.BuildpackInfo{ID: "test.bp.meta", Version: "3.0.0"}
+					loop := builder.BuildpackInfo{ID: "test.bp.loop", Version: "4.0.0"}
+
+					remoteInfo.Buildpacks = []builder.BuildpackMetadata{
+						{BuildpackInfo: one},
+						{BuildpackInfo: two},
+						{BuildpackInfo: meta, Order: builder.Order{
+							{Group: []builder.BuildpackRef{{BuildpackInfo: one}, {BuildpackInfo: two, Optional: true}}},
+							{Group: []builder.BuildpackRef{{BuildpackInfo: loop}}},
+						}},
+						{BuildpackInfo: loop, Order: builder.Order{
+							{Group: []builder.BuildpackRef{{BuildpackInfo: meta, Optional: true}}},
+						}},
+					}
+					remoteInfo.Groups = builder.Order{
+						{Group: []builder.BuildpackRef{{BuildpackInfo: meta, Optional: true}, {BuildpackInfo: builder.BuildpackInfo{ID: "test.bp.two"}}}},
+					}
+
+					command.SetArgs([]string{"some/image"})
+					mockClient.EXPECT().InspectBuilder("some/image", false).Return(remoteInfo, nil)
+					mockClient.EXPECT().InspectBuilder("some/image", true).Return(nil, nil)
+				})
+
+				it("displays the nested order as a tree", func() {
+					h.AssertNil(t, command.Execute())
+					h.AssertContains(t, outBuf.String(), `
+Detection Order:
+  Group #1:
+    test.bp.meta@3.0.0    (optional)
+      Group #1:
+        test.bp.one@1.0.0
+        test.bp.two@2.0.0    (optional)
+      Group #2:
+        test.bp.loop@4.0.0
+          Group #1:
+            test.bp.meta@3.0.0    (optional) (cycle detected)
+    test.bp.two@2.0.0
 `)
 				})
 			})
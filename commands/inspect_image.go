@@ -0,0 +1,114 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/buildpack/pack"
+	"github.com/buildpack/pack/config"
+	"github.com/buildpack/pack/logging"
+	"github.com/buildpack/pack/style"
+)
+
+func InspectImage(logger logging.Logger, cfg config.Config, client PackClient) *cobra.Command {
+	ctx := createCancellableContext()
+	cmd := &cobra.Command{
+		Use:   "inspect-image <image-name>",
+		Short: "Show information about a built image",
+		Args:  cobra.ExactArgs(1),
+		RunE: logError(logger, func(cmd *cobra.Command, args []string) error {
+			imageName := args[0]
+			logger.Infof("Inspecting image: %s", style.Symbol(imageName))
+			logger.Info("")
+
+			logger.Info("Remote")
+			logger.Info("------")
+			inspectImageOutput(ctx, logger, client, imageName, false, cfg)
+
+			logger.Info("")
+			logger.Info("Local")
+			logger.Info("-----")
+			inspectImageOutput(ctx, logger, client, imageName, true, cfg)
+
+			return nil
+		}),
+	}
+	AddHelpFlag(cmd, "inspect-image")
+	return cmd
+}
+
+func inspectImageOutput(ctx context.Context, logger logging.Logger, client PackClient, imageName string, local bool, cfg config.Config) {
+	info, err := client.InspectImage(ctx, imageName, local)
+	if err != nil {
+		logger.Info("")
+		logger.Error(err.Error())
+		return
+	}
+
+	if info == nil {
+		logger.Info("")
+		logger.Info("Not present")
+		return
+	}
+
+	logger.Info("")
+	logger.Infof("Stack: %s", info.StackID)
+	logger.Info("")
+
+	logger.Info("Base Image:")
+	logger.Infof("  Digest: %s", info.Base.SHA)
+	logger.Infof("  Top Layer: %s", info.Base.TopLayer)
+	logger.Info("")
+
+	if info.Stack.RunImage.Image == "" {
+		logger.Warnf("%s does not specify a run image", style.Symbol(imageName))
+	} else {
+		logger.Info("Run Images:")
+		for _, r := range getLocalMirrors(info.Stack.RunImage.Image, cfg) {
+			logger.Infof("  %s (user-configured)", r)
+		}
+		logger.Infof("  %s", info.Stack.RunImage.Image)
+		for _, r := range info.Stack.RunImage.Mirrors {
+			logger.Infof("  %s", r)
+		}
+	}
+
+	if len(info.Buildpacks) == 0 {
+		logger.Info("")
+		logger.Warnf("%s has no buildpacks", style.Symbol(imageName))
+	} else {
+		logImageBuildpacksInfo(logger, info)
+	}
+}
+
+func logImageBuildpacksInfo(logger logging.Logger, info *pack.ImageInfo) {
+	buf := &bytes.Buffer{}
+	tabWriter := new(tabwriter.Writer).Init(buf, 0, 0, 8, ' ', 0)
+	if _, err := fmt.Fprint(tabWriter, "\n  ID\tVERSION\tLAYERS"); err != nil {
+		logger.Error(err.Error())
+	}
+
+	for _, bp := range info.Buildpacks {
+		var layers []string
+		for name := range bp.Layers {
+			layers = append(layers, name)
+		}
+		sort.Strings(layers)
+
+		if _, err := fmt.Fprint(tabWriter, fmt.Sprintf("\n  %s\t%s\t%s", bp.ID, bp.Version, strings.Join(layers, ", "))); err != nil {
+			logger.Error(err.Error())
+		}
+	}
+
+	if err := tabWriter.Flush(); err != nil {
+		logger.Error(err.Error())
+	}
+
+	logger.Info("\nBuildpacks:" + buf.String())
+}
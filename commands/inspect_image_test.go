@@ -0,0 +1,206 @@
+package commands_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/buildpack/lifecycle/metadata"
+	"github.com/golang/mock/gomock"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpack/pack"
+	"github.com/buildpack/pack/commands"
+	cmdmocks "github.com/buildpack/pack/commands/mocks"
+	"github.com/buildpack/pack/config"
+	"github.com/buildpack/pack/internal/fakes"
+	"github.com/buildpack/pack/logging"
+	h "github.com/buildpack/pack/testhelpers"
+)
+
+func TestInspectImageCommand(t *testing.T) {
+	spec.Run(t, "Commands", testInspectImageCommand, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testInspectImageCommand(t *testing.T, when spec.G, it spec.S) {
+	var (
+		command        *cobra.Command
+		logger         logging.Logger
+		outBuf         bytes.Buffer
+		mockController *gomock.Controller
+		mockClient     *cmdmocks.MockPackClient
+		cfg            config.Config
+	)
+
+	it.Before(func() {
+		cfg = config.Config{
+			RunImages: []config.RunImage{
+				{Image: "some/run-image", Mirrors: []string{"first/local", "second/local"}},
+			},
+		}
+		mockController = gomock.NewController(t)
+		mockClient = cmdmocks.NewMockPackClient(mockController)
+		logger = fakes.NewFakeLogger(&outBuf)
+
+		command = commands.InspectImage(logger, cfg, mockClient)
+	})
+
+	it.After(func() {
+		mockController.Finish()
+	})
+
+	when("#InspectImage", func() {
+		when("image cannot be found", func() {
+			it("logs 'Not present'", func() {
+				mockClient.EXPECT().InspectImage(gomock.Any(), "some/image", false).Return(nil, nil)
+				mockClient.EXPECT().InspectImage(gomock.Any(), "some/image", true).Return(nil, nil)
+
+				command.SetArgs([]string{"some/image"})
+				h.AssertNil(t, command.Execute())
+
+				h.AssertContains(t, outBuf.String(), "Remote\n------\n\nNot present\n\nLocal\n-----\n\nNot present\n")
+			})
+		})
+
+		when("inspector returns an error", func() {
+			it("logs the error message", func() {
+				mockClient.EXPECT().InspectImage(gomock.Any(), "some/image", false).Return(nil, errors.New("some remote error"))
+				mockClient.EXPECT().InspectImage(gomock.Any(), "some/image", true).Return(nil, errors.New("some local error"))
+
+				command.SetArgs([]string{"some/image"})
+				h.AssertNil(t, command.Execute())
+
+				h.AssertContains(t, outBuf.String(), `Remote
+------
+
+ERROR: some remote error
+
+Local
+-----
+
+ERROR: some local error
+`)
+			})
+		})
+
+		when("the image has empty fields in info", func() {
+			it.Before(func() {
+				mockClient.EXPECT().InspectImage(gomock.Any(), "some/image", false).Return(&pack.ImageInfo{
+					StackID: "test.stack.id",
+				}, nil)
+				mockClient.EXPECT().InspectImage(gomock.Any(), "some/image", true).Return(&pack.ImageInfo{
+					StackID: "test.stack.id",
+				}, nil)
+
+				command.SetArgs([]string{"some/image"})
+			})
+
+			it("missing run image logs a warning", func() {
+				h.AssertNil(t, command.Execute())
+				h.AssertContains(t, outBuf.String(), "Warning: 'some/image' does not specify a run image")
+			})
+
+			it("missing buildpacks logs a warning", func() {
+				h.AssertNil(t, command.Execute())
+				h.AssertContains(t, outBuf.String(), "Warning: 'some/image' has no buildpacks")
+			})
+		})
+
+		when("is successful", func() {
+			it.Before(func() {
+				remoteInfo := &pack.ImageInfo{
+					StackID: "test.stack.id",
+					Stack: metadata.StackMetadata{
+						RunImage: metadata.StackRunImageMetadata{
+							Image:   "some/run-image",
+							Mirrors: []string{"first/default", "second/default"},
+						},
+					},
+					Base: metadata.RunImageMetadata{
+						TopLayer: "some-remote-top-layer",
+						SHA:      "some-remote-run-image-digest",
+					},
+					Buildpacks: []metadata.BuildpackMetadata{
+						{
+							ID:      "test.bp.one",
+							Version: "1.0.0",
+							Layers: map[string]metadata.LayerMetadata{
+								"layer-b": {SHA: "sha-b"},
+								"layer-a": {SHA: "sha-a"},
+							},
+						},
+						{ID: "test.bp.two", Version: "2.0.0"},
+					},
+				}
+				localInfo := &pack.ImageInfo{
+					StackID: "test.stack.id",
+					Stack: metadata.StackMetadata{
+						RunImage: metadata.StackRunImageMetadata{
+							Image: "some/run-image",
+						},
+					},
+					Base: metadata.RunImageMetadata{
+						TopLayer: "some-local-top-layer",
+						SHA:      "some-local-run-image-digest",
+					},
+					Buildpacks: []metadata.BuildpackMetadata{
+						{ID: "test.bp.one", Version: "1.0.0"},
+					},
+				}
+
+				mockClient.EXPECT().InspectImage(gomock.Any(), "some/image", false).Return(remoteInfo, nil)
+				mockClient.EXPECT().InspectImage(gomock.Any(), "some/image", true).Return(localInfo, nil)
+				command.SetArgs([]string{"some/image"})
+			})
+
+			it("displays image information for local and remote", func() {
+				h.AssertNil(t, command.Execute())
+				h.AssertContains(t, outBuf.String(), "Inspecting image: 'some/image'")
+				h.AssertContains(t, outBuf.String(), `
+Remote
+------
+
+Stack: test.stack.id
+
+Base Image:
+  Digest: some-remote-run-image-digest
+  Top Layer: some-remote-top-layer
+
+Run Images:
+  first/local (user-configured)
+  second/local (user-configured)
+  some/run-image
+  first/default
+  second/default
+
+Buildpacks:
+  ID                 VERSION        LAYERS
+  test.bp.one        1.0.0          layer-a, layer-b
+  test.bp.two        2.0.0
+`)
+
+				h.AssertContains(t, outBuf.String(), `
+Local
+-----
+
+Stack: test.stack.id
+
+Base Image:
+  Digest: some-local-run-image-digest
+  Top Layer: some-local-top-layer
+
+Run Images:
+  first/local (user-configured)
+  second/local (user-configured)
+  some/run-image
+
+Buildpacks:
+  ID                 VERSION        LAYERS
+  test.bp.one        1.0.0
+`)
+			})
+		})
+	})
+}
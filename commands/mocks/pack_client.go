@@ -7,6 +7,7 @@ package mocks
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 
@@ -36,6 +37,21 @@ func (m *MockPackClient) EXPECT() *MockPackClientMockRecorder {
 	return m.recorder
 }
 
+// BuildMany mocks base method
+func (m *MockPackClient) BuildMany(arg0 context.Context, arg1 pack.BuildManyOptions) ([]pack.AppBuildResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BuildMany", arg0, arg1)
+	ret0, _ := ret[0].([]pack.AppBuildResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BuildMany indicates an expected call of BuildMany
+func (mr *MockPackClientMockRecorder) BuildMany(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BuildMany", reflect.TypeOf((*MockPackClient)(nil).BuildMany), arg0, arg1)
+}
+
 // CreateBuilder mocks base method
 func (m *MockPackClient) CreateBuilder(arg0 context.Context, arg1 pack.CreateBuilderOptions) error {
 	m.ctrl.T.Helper()
@@ -65,12 +81,73 @@ func (mr *MockPackClientMockRecorder) InspectBuilder(arg0, arg1 interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InspectBuilder", reflect.TypeOf((*MockPackClient)(nil).InspectBuilder), arg0, arg1)
 }
 
+// InspectCache mocks base method
+func (m *MockPackClient) InspectCache(arg0 context.Context, arg1 string) ([]pack.CacheInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InspectCache", arg0, arg1)
+	ret0, _ := ret[0].([]pack.CacheInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InspectCache indicates an expected call of InspectCache
+func (mr *MockPackClientMockRecorder) InspectCache(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InspectCache", reflect.TypeOf((*MockPackClient)(nil).InspectCache), arg0, arg1)
+}
+
+// InspectImage mocks base method
+func (m *MockPackClient) InspectImage(arg0 context.Context, arg1 string, arg2 bool) (*pack.ImageInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InspectImage", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*pack.ImageInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InspectImage indicates an expected call of InspectImage
+func (mr *MockPackClientMockRecorder) InspectImage(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InspectImage", reflect.TypeOf((*MockPackClient)(nil).InspectImage), arg0, arg1, arg2)
+}
+
+// ListCaches mocks base method
+func (m *MockPackClient) ListCaches(arg0 context.Context) ([]pack.CacheInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCaches", arg0)
+	ret0, _ := ret[0].([]pack.CacheInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCaches indicates an expected call of ListCaches
+func (mr *MockPackClientMockRecorder) ListCaches(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCaches", reflect.TypeOf((*MockPackClient)(nil).ListCaches), arg0)
+}
+
+// PruneCaches mocks base method
+func (m *MockPackClient) PruneCaches(arg0 context.Context, arg1 time.Duration) ([]pack.CacheInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PruneCaches", arg0, arg1)
+	ret0, _ := ret[0].([]pack.CacheInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PruneCaches indicates an expected call of PruneCaches
+func (mr *MockPackClientMockRecorder) PruneCaches(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PruneCaches", reflect.TypeOf((*MockPackClient)(nil).PruneCaches), arg0, arg1)
+}
+
 // Rebase mocks base method
-func (m *MockPackClient) Rebase(arg0 context.Context, arg1 pack.RebaseOptions) error {
+func (m *MockPackClient) Rebase(arg0 context.Context, arg1 pack.RebaseOptions) (*pack.RebaseResult, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Rebase", arg0, arg1)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret0, _ := ret[0].(*pack.RebaseResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // Rebase indicates an expected call of Rebase
@@ -78,3 +155,18 @@ func (mr *MockPackClientMockRecorder) Rebase(arg0, arg1 interface{}) *gomock.Cal
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rebase", reflect.TypeOf((*MockPackClient)(nil).Rebase), arg0, arg1)
 }
+
+// RebaseMany mocks base method
+func (m *MockPackClient) RebaseMany(arg0 context.Context, arg1 pack.RebaseManyOptions) ([]pack.ImageRebaseResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RebaseMany", arg0, arg1)
+	ret0, _ := ret[0].([]pack.ImageRebaseResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RebaseMany indicates an expected call of RebaseMany
+func (mr *MockPackClientMockRecorder) RebaseMany(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RebaseMany", reflect.TypeOf((*MockPackClient)(nil).RebaseMany), arg0, arg1)
+}
@@ -1,6 +1,13 @@
 package commands
 
 import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 
 	"github.com/buildpack/pack/config"
@@ -10,27 +17,194 @@ import (
 	"github.com/buildpack/pack/style"
 )
 
+type RebaseFlags struct {
+	PullPolicy      string
+	NoPull          bool
+	FailIfUnchanged bool
+	FailIfChanged   bool
+	AllUsing        string
+	Concurrency     int
+}
+
 func Rebase(logger logging.Logger, cfg config.Config, client PackClient) *cobra.Command {
-	var opts pack.RebaseOptions
+	var (
+		opts  pack.RebaseOptions
+		flags RebaseFlags
+	)
 	ctx := createCancellableContext()
 
 	cmd := &cobra.Command{
 		Use:   "rebase <image-name>",
-		Args:  cobra.ExactArgs(1),
+		Args:  cobra.ArbitraryArgs,
 		Short: "Rebase app image with latest run image",
+		Long: `Rebase app image with latest run image.
+
+With --all-using, rebase every app image built on the given run image instead: the images on the daemon, or the
+registry images given as arguments when publishing, e.g.
+
+pack rebase --all-using some/run --publish example.com/api example.com/web`,
 		RunE: logError(logger, func(cmd *cobra.Command, args []string) error {
-			opts.RepoName = args[0]
+			if (flags.FailIfUnchanged || flags.FailIfChanged) && !opts.DryRun {
+				return errors.New("--fail-if-unchanged and --fail-if-changed require --dry-run")
+			}
+			if flags.FailIfUnchanged && flags.FailIfChanged {
+				return errors.New("--fail-if-unchanged and --fail-if-changed cannot be used together")
+			}
+
+			if opts.Rollback && opts.RunImage != "" {
+				return errors.New("--rollback and --run-image cannot be used together")
+			}
+
 			opts.AdditionalMirrors = getMirrors(cfg)
-			if err := client.Rebase(ctx, opts); err != nil {
+			var err error
+			opts.PullPolicy, err = parsePullPolicy(flags.PullPolicy, flags.NoPull)
+			if err != nil {
+				return err
+			}
+			if flags.AllUsing != "" {
+				return rebaseMany(ctx, logger, client, opts, flags, args)
+			}
+
+			if len(args) != 1 {
+				return fmt.Errorf("accepts 1 arg(s), received %d", len(args))
+			}
+			opts.RepoName = args[0]
+			result, err := client.Rebase(ctx, opts)
+			if err != nil {
 				return err
 			}
-			logger.Infof("Successfully rebased image %s", style.Symbol(opts.RepoName))
+			if !opts.DryRun {
+				if opts.Rollback {
+					logger.Infof("Successfully rolled back image %s to run image %s", style.Symbol(opts.RepoName), style.Symbol(result.RunImage))
+					return nil
+				}
+				logger.Infof("Successfully rebased image %s", style.Symbol(opts.RepoName))
+				return nil
+			}
+
+			logRebaseReport(logger, result)
+			if (flags.FailIfUnchanged && !result.Changed) || (flags.FailIfChanged && result.Changed) {
+				return MakeSoftError()
+			}
 			return nil
 		}),
 	}
 	cmd.Flags().BoolVar(&opts.Publish, "publish", false, "Publish to registry")
-	cmd.Flags().BoolVar(&opts.SkipPull, "no-pull", false, "Skip pulling app and run images before use")
+	addPullPolicyFlags(cmd, &flags.PullPolicy, &flags.NoPull, "app and run images")
 	cmd.Flags().StringVar(&opts.RunImage, "run-image", "", "Run image to use for rebasing")
+	cmd.Flags().BoolVar(&opts.DryRun, "dry-run", false, "Report the layers a rebase would replace without rebasing")
+	cmd.Flags().BoolVar(&flags.FailIfUnchanged, "fail-if-unchanged", false, "With --dry-run, exit with code 2 if the rebase would not change the image")
+	cmd.Flags().BoolVar(&flags.FailIfChanged, "fail-if-changed", false, "With --dry-run, exit with code 2 if the rebase would change the image")
+	cmd.Flags().BoolVar(&opts.Rollback, "rollback", false, "Rebase on the run image the image had before its last rebase")
+	cmd.Flags().StringVar(&flags.AllUsing, "all-using", "", "Rebase every app image built on this run image")
+	cmd.Flags().IntVar(&flags.Concurrency, "concurrency", 2, "With --all-using, maximum number of images rebased at once")
 	AddHelpFlag(cmd, "rebase")
 	return cmd
 }
+
+func rebaseMany(ctx context.Context, logger logging.Logger, client PackClient, opts pack.RebaseOptions, flags RebaseFlags, images []string) error {
+	if opts.Rollback {
+		return errors.New("--rollback and --all-using cannot be used together")
+	}
+	if opts.Publish && len(images) == 0 {
+		return errors.New("--all-using with --publish requires the registry images to rebase")
+	}
+	if !opts.Publish && len(images) > 0 {
+		return errors.New("--all-using rebases the given registry images only with --publish")
+	}
+
+	results, err := client.RebaseMany(ctx, pack.RebaseManyOptions{
+		UsingRunImage:     flags.AllUsing,
+		Images:            images,
+		RunImage:          opts.RunImage,
+		PullPolicy:        opts.PullPolicy,
+		AdditionalMirrors: opts.AdditionalMirrors,
+		DryRun:            opts.DryRun,
+		Concurrency:       flags.Concurrency,
+	})
+	if len(results) > 0 {
+		logRebaseManyResults(logger, results)
+	}
+	if err != nil {
+		return err
+	}
+	if len(results) == 0 {
+		logger.Infof("No images use run image %s", style.Symbol(flags.AllUsing))
+	}
+
+	changed := 0
+	for _, result := range results {
+		if result.Result.Changed {
+			changed++
+		}
+	}
+	if opts.DryRun {
+		if (flags.FailIfUnchanged && changed == 0) || (flags.FailIfChanged && changed > 0) {
+			return MakeSoftError()
+		}
+		return nil
+	}
+	logger.Infof("Successfully rebased %d image(s)", len(results))
+	return nil
+}
+
+func logRebaseManyResults(logger logging.Logger, results []pack.ImageRebaseResult) {
+	buf := &bytes.Buffer{}
+	tabWriter := new(tabwriter.Writer).Init(buf, 0, 0, 3, ' ', 0)
+	fmt.Fprint(tabWriter, "IMAGE\tPREVIOUS DIGEST\tNEW DIGEST\tRESULT")
+	for _, result := range results {
+		status := "rebased"
+		switch {
+		case result.Err != nil:
+			status = "failed"
+		case result.Digest != "":
+		case result.Result.Changed:
+			status = "would change"
+		default:
+			status = "unchanged"
+		}
+		fmt.Fprintf(tabWriter, "\n%s\t%s\t%s\t%s", strings.Join(append([]string{result.Image}, result.AdditionalTags...), ", "), orNone(result.PreviousDigest), orNone(result.Digest), status)
+	}
+	if err := tabWriter.Flush(); err != nil {
+		logger.Error(err.Error())
+	}
+
+	logger.Info("Rebase Summary:")
+	logger.Info(buf.String())
+
+	for _, result := range results {
+		if result.Err != nil {
+			logger.Errorf("Failed to rebase %s: %s", style.Symbol(result.Image), result.Err)
+		}
+	}
+}
+
+func logRebaseReport(logger logging.Logger, result *pack.RebaseResult) {
+	logger.Infof("Image: %s", style.Symbol(result.Image))
+	logger.Info("Current run image:")
+	logger.Infof("  Digest: %s", orNone(result.PreviousRunImageDigest))
+	logger.Infof("  Top Layer: %s", orNone(result.PreviousTopLayer))
+	logger.Infof("Candidate run image: %s", style.Symbol(result.RunImage))
+	logger.Infof("  Digest: %s", orNone(result.RunImageDigest))
+	logger.Infof("  Top Layer: %s", orNone(result.TopLayer))
+
+	if !result.Changed {
+		logger.Info("Rebase would not change the image")
+		return
+	}
+	logger.Info("Layers replaced:")
+	for _, layer := range result.ReplacedLayers {
+		logger.Infof("  - %s", layer)
+	}
+	for _, layer := range result.NewLayers {
+		logger.Infof("  + %s", layer)
+	}
+	logger.Info("Rebase would change the image")
+}
+
+func orNone(value string) string {
+	if value == "" {
+		return "(none)"
+	}
+	return value
+}
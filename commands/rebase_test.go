@@ -0,0 +1,262 @@
+package commands_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpack/pack"
+	"github.com/buildpack/pack/commands"
+	cmdmocks "github.com/buildpack/pack/commands/mocks"
+	"github.com/buildpack/pack/config"
+	"github.com/buildpack/pack/internal/fakes"
+	"github.com/buildpack/pack/logging"
+	h "github.com/buildpack/pack/testhelpers"
+)
+
+func TestRebaseCommand(t *testing.T) {
+	spec.Run(t, "Commands", testRebaseCommand, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testRebaseCommand(t *testing.T, when spec.G, it spec.S) {
+	var (
+		command        *cobra.Command
+		logger         logging.Logger
+		outBuf         bytes.Buffer
+		mockController *gomock.Controller
+		mockClient     *cmdmocks.MockPackClient
+	)
+
+	it.Before(func() {
+		mockController = gomock.NewController(t)
+		mockClient = cmdmocks.NewMockPackClient(mockController)
+		logger = fakes.NewFakeLogger(&outBuf)
+
+		command = commands.Rebase(logger, config.Config{}, mockClient)
+	})
+
+	it.After(func() {
+		mockController.Finish()
+	})
+
+	when("#Rebase", func() {
+		it("rebases the image", func() {
+			mockClient.EXPECT().
+				Rebase(gomock.Any(), pack.RebaseOptions{RepoName: "some/app", AdditionalMirrors: map[string][]string{}}).
+				Return(&pack.RebaseResult{Image: "some/app", Changed: true}, nil)
+
+			command.SetArgs([]string{"some/app"})
+			h.AssertNil(t, command.Execute())
+			h.AssertContains(t, outBuf.String(), "Successfully rebased image 'some/app'")
+		})
+
+		when("--dry-run", func() {
+			var changed, unchanged *pack.RebaseResult
+
+			it.Before(func() {
+				changed = &pack.RebaseResult{
+					Image:                  "some/app",
+					RunImage:               "some/run",
+					PreviousRunImageDigest: "old-digest",
+					PreviousTopLayer:       "old-top-layer",
+					RunImageDigest:         "new-digest",
+					TopLayer:               "new-top-layer",
+					Changed:                true,
+					ReplacedLayers:         []string{"old-base-layer", "old-top-layer"},
+					NewLayers:              []string{"new-top-layer"},
+				}
+				unchanged = &pack.RebaseResult{
+					Image:                  "some/app",
+					RunImage:               "some/run",
+					PreviousRunImageDigest: "new-digest",
+					PreviousTopLayer:       "new-top-layer",
+					RunImageDigest:         "new-digest",
+					TopLayer:               "new-top-layer",
+				}
+			})
+
+			it("reports the layers a rebase would replace", func() {
+				mockClient.EXPECT().
+					Rebase(gomock.Any(), pack.RebaseOptions{RepoName: "some/app", AdditionalMirrors: map[string][]string{}, DryRun: true}).
+					Return(changed, nil)
+
+				command.SetArgs([]string{"some/app", "--dry-run"})
+				h.AssertNil(t, command.Execute())
+				h.AssertContains(t, outBuf.String(), `Image: 'some/app'
+Current run image:
+  Digest: old-digest
+  Top Layer: old-top-layer
+Candidate run image: 'some/run'
+  Digest: new-digest
+  Top Layer: new-top-layer
+Layers replaced:
+  - old-base-layer
+  - old-top-layer
+  + new-top-layer
+Rebase would change the image`)
+				h.AssertNotContains(t, outBuf.String(), "Successfully rebased")
+			})
+
+			it("reports when the image would not change", func() {
+				mockClient.EXPECT().Rebase(gomock.Any(), gomock.Any()).Return(unchanged, nil)
+
+				command.SetArgs([]string{"some/app", "--dry-run"})
+				h.AssertNil(t, command.Execute())
+				h.AssertContains(t, outBuf.String(), "Rebase would not change the image")
+			})
+
+			when("--fail-if-unchanged", func() {
+				it("fails when the image would not change", func() {
+					mockClient.EXPECT().Rebase(gomock.Any(), gomock.Any()).Return(unchanged, nil)
+
+					command.SetArgs([]string{"some/app", "--dry-run", "--fail-if-unchanged"})
+					err := command.Execute()
+					h.AssertEq(t, commands.IsSoftError(err), true)
+				})
+
+				it("succeeds when the image would change", func() {
+					mockClient.EXPECT().Rebase(gomock.Any(), gomock.Any()).Return(changed, nil)
+
+					command.SetArgs([]string{"some/app", "--dry-run", "--fail-if-unchanged"})
+					h.AssertNil(t, command.Execute())
+				})
+			})
+
+			when("--fail-if-changed", func() {
+				it("fails when the image would change", func() {
+					mockClient.EXPECT().Rebase(gomock.Any(), gomock.Any()).Return(changed, nil)
+
+					command.SetArgs([]string{"some/app", "--dry-run", "--fail-if-changed"})
+					err := command.Execute()
+					h.AssertEq(t, commands.IsSoftError(err), true)
+				})
+
+				it("succeeds when the image would not change", func() {
+					mockClient.EXPECT().Rebase(gomock.Any(), gomock.Any()).Return(unchanged, nil)
+
+					command.SetArgs([]string{"some/app", "--dry-run", "--fail-if-changed"})
+					h.AssertNil(t, command.Execute())
+				})
+			})
+
+			it("fails when both exit code flags are set", func() {
+				command.SetArgs([]string{"some/app", "--dry-run", "--fail-if-changed", "--fail-if-unchanged"})
+				h.AssertError(t, command.Execute(), "cannot be used together")
+			})
+		})
+
+		when("--rollback", func() {
+			it("rolls back the image to its previous run image", func() {
+				mockClient.EXPECT().
+					Rebase(gomock.Any(), pack.RebaseOptions{RepoName: "some/app", AdditionalMirrors: map[string][]string{}, Rollback: true}).
+					Return(&pack.RebaseResult{Image: "some/app", RunImage: "some/run@sha256:old", Changed: true}, nil)
+
+				command.SetArgs([]string{"some/app", "--rollback"})
+				h.AssertNil(t, command.Execute())
+				h.AssertContains(t, outBuf.String(), "Successfully rolled back image 'some/app' to run image 'some/run@sha256:old'")
+			})
+
+			it("fails with --run-image", func() {
+				command.SetArgs([]string{"some/app", "--rollback", "--run-image", "some/run"})
+				h.AssertError(t, command.Execute(), "--rollback and --run-image cannot be used together")
+			})
+
+			it("fails with --all-using", func() {
+				command.SetArgs([]string{"--rollback", "--all-using", "some/run"})
+				h.AssertError(t, command.Execute(), "--rollback and --all-using cannot be used together")
+			})
+		})
+
+		when("--all-using", func() {
+			it("rebases the images on the daemon using the run image", func() {
+				mockClient.EXPECT().
+					RebaseMany(gomock.Any(), pack.RebaseManyOptions{
+						UsingRunImage:     "some/run",
+						Images:            []string{},
+						AdditionalMirrors: map[string][]string{},
+						Concurrency:       2,
+					}).
+					Return([]pack.ImageRebaseResult{
+						{Image: "some/app", PreviousDigest: "sha256:old", Digest: "sha256:new", Result: &pack.RebaseResult{Changed: true}},
+						{Image: "other/app", PreviousDigest: "sha256:other", Digest: "sha256:other-new", Result: &pack.RebaseResult{Changed: true}},
+					}, nil)
+
+				command.SetArgs([]string{"--all-using", "some/run"})
+				h.AssertNil(t, command.Execute())
+				h.AssertContains(t, outBuf.String(), `Rebase Summary:
+IMAGE       PREVIOUS DIGEST   NEW DIGEST         RESULT
+some/app    sha256:old        sha256:new         rebased
+other/app   sha256:other      sha256:other-new   rebased`)
+				h.AssertContains(t, outBuf.String(), "Successfully rebased 2 image(s)")
+			})
+
+			it("rebases the given registry images when publishing", func() {
+				mockClient.EXPECT().
+					RebaseMany(gomock.Any(), pack.RebaseManyOptions{
+						UsingRunImage:     "some/run",
+						Images:            []string{"example.com/some/app", "example.com/other/app"},
+						AdditionalMirrors: map[string][]string{},
+						Concurrency:       4,
+					}).
+					Return(nil, nil)
+
+				command.SetArgs([]string{"--all-using", "some/run", "--publish", "--concurrency", "4", "example.com/some/app", "example.com/other/app"})
+				h.AssertNil(t, command.Execute())
+				h.AssertContains(t, outBuf.String(), "No images use run image 'some/run'")
+			})
+
+			it("reports failed rebases", func() {
+				mockClient.EXPECT().
+					RebaseMany(gomock.Any(), gomock.Any()).
+					Return([]pack.ImageRebaseResult{
+						{Image: "some/app", Err: errors.New("some error")},
+					}, errors.New("1 of 1 rebases failed"))
+
+				command.SetArgs([]string{"--all-using", "some/run"})
+				h.AssertError(t, command.Execute(), "1 of 1 rebases failed")
+				h.AssertContains(t, outBuf.String(), "some/app   (none)            (none)       failed")
+				h.AssertContains(t, outBuf.String(), "ERROR: Failed to rebase 'some/app': some error")
+			})
+
+			it("fails on a dry run with --fail-if-changed when any image would change", func() {
+				mockClient.EXPECT().
+					RebaseMany(gomock.Any(), gomock.Any()).
+					Return([]pack.ImageRebaseResult{
+						{Image: "some/app", Result: &pack.RebaseResult{Changed: false}},
+						{Image: "other/app", Result: &pack.RebaseResult{Changed: true}},
+					}, nil)
+
+				command.SetArgs([]string{"--all-using", "some/run", "--dry-run", "--fail-if-changed"})
+				err := command.Execute()
+				h.AssertEq(t, commands.IsSoftError(err), true)
+				h.AssertContains(t, outBuf.String(), "some/app    (none)            (none)       unchanged")
+				h.AssertContains(t, outBuf.String(), "other/app   (none)            (none)       would change")
+			})
+
+			it("fails when publishing without registry images", func() {
+				command.SetArgs([]string{"--all-using", "some/run", "--publish"})
+				h.AssertError(t, command.Execute(), "requires the registry images to rebase")
+			})
+
+			it("fails when given images without publishing", func() {
+				command.SetArgs([]string{"--all-using", "some/run", "some/app"})
+				h.AssertError(t, command.Execute(), "only with --publish")
+			})
+		})
+
+		it("fails without an image", func() {
+			command.SetArgs([]string{})
+			h.AssertError(t, command.Execute(), "accepts 1 arg(s), received 0")
+		})
+
+		it("fails when an exit code flag is set without --dry-run", func() {
+			command.SetArgs([]string{"some/app", "--fail-if-changed"})
+			h.AssertError(t, command.Execute(), "require --dry-run")
+		})
+	})
+}
@@ -1,6 +1,7 @@
 package commands
 
 import (
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 
 	"github.com/buildpack/pack"
@@ -8,9 +9,18 @@ import (
 	"github.com/buildpack/pack/logging"
 )
 
+type RunFlags struct {
+	Ports       []string
+	Env         []string
+	Volumes     []string
+	Name        string
+	Detach      bool
+	ProcessType string
+}
+
 func Run(logger logging.Logger, cfg config.Config, packClient *pack.Client) *cobra.Command {
 	var flags BuildFlags
-	var ports []string
+	var runFlags RunFlags
 	ctx := createCancellableContext()
 
 	cmd := &cobra.Command{
@@ -18,28 +28,64 @@ func Run(logger logging.Logger, cfg config.Config, packClient *pack.Client) *cob
 		Args:  cobra.NoArgs,
 		Short: "Build and run app image (recommended for development only)",
 		RunE: logError(logger, func(cmd *cobra.Command, args []string) error {
-			if flags.Builder == "" {
+			env, err := parseEnv(flags.EnvFile, flags.Env)
+			if err != nil {
+				return err
+			}
+			phaseTimeouts, err := parsePhaseTimeouts(flags.PhaseTimeouts)
+			if err != nil {
+				return err
+			}
+			pullPolicy, err := parsePullPolicy(flags.PullPolicy, flags.NoPull)
+			if err != nil {
+				return err
+			}
+			runtimeEnv := map[string]string{}
+			for _, envVar := range runFlags.Env {
+				runtimeEnv = addEnvVar(runtimeEnv, envVar)
+			}
+			containerID, err := packClient.Run(ctx, pack.RunOptions{
+				AppPath:        flags.AppPath,
+				Builder:        flags.Builder,
+				DefaultBuilder: cfg.DefaultBuilder,
+				RunImage:       flags.RunImage,
+				Env:            env,
+				Secrets:        parseSecrets(flags.Secrets),
+				PullPolicy:     pullPolicy,
+				ClearCache:     flags.ClearCache,
+				Buildpacks:     flags.Buildpacks,
+				Exclude:        flags.Exclude,
+				Ports:          runFlags.Ports,
+				Network:        flags.Network,
+				Volumes:        flags.Volumes,
+				Timeout:        flags.Timeout,
+				PhaseTimeouts:  phaseTimeouts,
+				RuntimeEnv:     runtimeEnv,
+				RuntimeVolumes: runFlags.Volumes,
+				ContainerName:  runFlags.Name,
+				Detach:         runFlags.Detach,
+				ProcessType:    runFlags.ProcessType,
+			})
+			if errors.Cause(err) == pack.ErrNoBuilder {
 				suggestSettingBuilder(logger, packClient)
 				return MakeSoftError()
 			}
-			env, err := parseEnv(flags.EnvFile, flags.Env)
 			if err != nil {
 				return err
 			}
-			return packClient.Run(ctx, pack.RunOptions{
-				AppPath:    flags.AppPath,
-				Builder:    flags.Builder,
-				RunImage:   flags.RunImage,
-				Env:        env,
-				NoPull:     flags.NoPull,
-				ClearCache: flags.ClearCache,
-				Buildpacks: flags.Buildpacks,
-				Ports:      ports,
-			})
+			if runFlags.Detach {
+				logger.Info(containerID)
+			}
+			return nil
 		}),
 	}
 	buildCommandFlags(cmd, &flags, cfg)
-	cmd.Flags().StringSliceVar(&ports, "port", nil, "Port to publish (defaults to port(s) exposed by container)"+multiValueHelp("port"))
+	cmd.Flags().StringSliceVar(&runFlags.Ports, "port", nil, "Port to publish (defaults to port(s) exposed by container)"+multiValueHelp("port"))
+	cmd.Flags().StringArrayVar(&runFlags.Env, "run-env", nil, "Runtime environment variable of the app container, in the form 'VAR=VALUE' or 'VAR'.\nWhen using latter value-less form, value will be taken from current\n  environment at the time this command is executed.\nThis flag may be specified multiple times.")
+	cmd.Flags().StringArrayVar(&runFlags.Volumes, "run-volume", nil, "Mount a host volume into the app container, in the form '<host path>:<target path>[:<mode>]'.\n<mode> is 'ro' (default) or 'rw'.\nThis flag may be specified multiple times.")
+	cmd.Flags().StringVar(&runFlags.Name, "name", "", "Name of the app container")
+	cmd.Flags().BoolVarP(&runFlags.Detach, "detach", "d", false, "Run the app container in the background and print its ID")
+	cmd.Flags().StringVar(&runFlags.ProcessType, "process-type", "", "Process type from the app's launch metadata to run, e.g. 'worker' (defaults to 'web')")
 	AddHelpFlag(cmd, "run")
 	return cmd
 }
@@ -0,0 +1,46 @@
+package commands
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/buildpack/pack/builder"
+	"github.com/buildpack/pack/logging"
+	"github.com/buildpack/pack/style"
+)
+
+func ValidateBuilderConfig(logger logging.Logger) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "validate-builder-config <builder-config-path>",
+		Short: "Check a builder config for problems without creating the builder",
+		Long: `Check a builder config for problems without creating the builder.
+
+Buildpacks at local paths are read to check their buildpack.toml, no images or remote buildpacks are fetched.
+Exits with code 2 if the config has errors.`,
+		Args: cobra.ExactArgs(1),
+		RunE: logError(logger, func(cmd *cobra.Command, args []string) error {
+			issues, err := builder.LintConfig(args[0])
+			if err != nil {
+				return err
+			}
+
+			errs := 0
+			for _, issue := range issues {
+				if issue.Severity == builder.SeverityError {
+					errs++
+					logger.Errorf("%s: %s", issue.Position, issue.Message)
+				} else {
+					logger.Warnf("%s: %s", issue.Position, issue.Message)
+				}
+			}
+
+			if errs > 0 {
+				logger.Infof("Builder config %s has %d error(s) and %d warning(s)", style.Symbol(args[0]), errs, len(issues)-errs)
+				return MakeSoftError()
+			}
+			logger.Infof("Builder config %s is valid", style.Symbol(args[0]))
+			return nil
+		}),
+	}
+	AddHelpFlag(cmd, "validate-builder-config")
+	return cmd
+}
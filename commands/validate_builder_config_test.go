@@ -0,0 +1,94 @@
+package commands_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpack/pack/commands"
+	"github.com/buildpack/pack/internal/fakes"
+	h "github.com/buildpack/pack/testhelpers"
+)
+
+func TestValidateBuilderConfigCommand(t *testing.T) {
+	spec.Run(t, "Commands", testValidateBuilderConfigCommand, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testValidateBuilderConfigCommand(t *testing.T, when spec.G, it spec.S) {
+	var (
+		command           *cobra.Command
+		outBuf            bytes.Buffer
+		tmpDir            string
+		builderConfigPath string
+	)
+
+	it.Before(func() {
+		var err error
+		tmpDir, err = ioutil.TempDir("", "validate-builder-config-test")
+		h.AssertNil(t, err)
+		builderConfigPath = filepath.Join(tmpDir, "builder.toml")
+
+		command = commands.ValidateBuilderConfig(fakes.NewFakeLogger(&outBuf))
+	})
+
+	it.After(func() {
+		h.AssertNil(t, os.RemoveAll(tmpDir))
+	})
+
+	when("#ValidateBuilderConfig", func() {
+		it("reports a valid config", func() {
+			h.AssertNil(t, ioutil.WriteFile(builderConfigPath, []byte(`
+[[buildpacks]]
+  id = "some.bp"
+  uri = "https://example.com/some-bp.tgz"
+
+[[order]]
+[[order.group]]
+  id = "some.bp"
+
+[stack]
+  id = "some.stack"
+  build-image = "some/build"
+  run-image = "some/run"
+`), 0666))
+
+			command.SetArgs([]string{builderConfigPath})
+			h.AssertNil(t, command.Execute())
+			h.AssertContains(t, outBuf.String(), "is valid")
+		})
+
+		it("reports the issues of an invalid config with their positions", func() {
+			h.AssertNil(t, ioutil.WriteFile(builderConfigPath, []byte(`
+[[buildpacks]]
+  id = "some.bp"
+  uri = "https://example.com/some-bp.tgz"
+
+[[order]]
+[[order.group]]
+  id = "other.bp"
+
+[stack]
+  id = "some.stack"
+  run-image = "some/run"
+`), 0666))
+
+			command.SetArgs([]string{builderConfigPath})
+			err := command.Execute()
+			h.AssertEq(t, commands.IsSoftError(err), true)
+			h.AssertContains(t, outBuf.String(), "ERROR: "+builderConfigPath+":8: group 1 references buildpack 'other.bp' which is not in 'buildpacks'")
+			h.AssertContains(t, outBuf.String(), "ERROR: "+builderConfigPath+":12: 'stack.build-image' is required")
+			h.AssertContains(t, outBuf.String(), "has 2 error(s) and 0 warning(s)")
+		})
+
+		it("fails when the config cannot be read", func() {
+			command.SetArgs([]string{filepath.Join(tmpDir, "missing.toml")})
+			h.AssertError(t, command.Execute(), "opening config file")
+		})
+	})
+}
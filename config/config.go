@@ -1,16 +1,20 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 
 	"github.com/BurntSushi/toml"
 	"github.com/pkg/errors"
+
+	"github.com/buildpack/pack/style"
 )
 
 type Config struct {
 	RunImages      []RunImage `toml:"run-images"`
 	DefaultBuilder string     `toml:"default-builder-image,omitempty"`
+	Registries     []Registry `toml:"registries,omitempty"`
 }
 
 type RunImage struct {
@@ -18,6 +22,15 @@ type RunImage struct {
 	Mirrors []string `toml:"mirrors"`
 }
 
+// Registry holds the settings for the images of one registry, e.g. 'registry.example.com:5000'
+type Registry struct {
+	Name        string   `toml:"name"`
+	Insecure    bool     `toml:"insecure,omitempty"` // use plain HTTP
+	Mirrors     []string `toml:"mirrors,omitempty"`  // pull-through mirrors tried in order before the registry, e.g. 'mirror.example.com/hub'
+	Username    string   `toml:"username,omitempty"`
+	PasswordEnv string   `toml:"password-env,omitempty"` // environment variable holding the password of Username
+}
+
 func DefaultConfigPath() (string, error) {
 	home, err := PackHome()
 	if err != nil {
@@ -45,9 +58,31 @@ func Read(path string) (Config, error) {
 		return Config{}, errors.Wrapf(err, "failed to read config file at path %s", path)
 	}
 
+	if err := validateRegistries(cfg.Registries); err != nil {
+		return Config{}, errors.Wrapf(err, "invalid config file at path %s", path)
+	}
+
 	return cfg, nil
 }
 
+func validateRegistries(registries []Registry) error {
+	seen := map[string]bool{}
+	for i, registry := range registries {
+		if registry.Name == "" {
+			return fmt.Errorf("registry %d is missing a name", i+1)
+		}
+		if seen[registry.Name] {
+			return fmt.Errorf("registry %s is configured more than once", style.Symbol(registry.Name))
+		}
+		seen[registry.Name] = true
+
+		if registry.PasswordEnv != "" && registry.Username == "" {
+			return fmt.Errorf("registry %s sets %s without %s", style.Symbol(registry.Name), style.Symbol("password-env"), style.Symbol("username"))
+		}
+	}
+	return nil
+}
+
 func Write(cfg Config, path string) error {
 	if err := MkdirAll(filepath.Dir(path)); err != nil {
 		return err
@@ -47,6 +47,59 @@ func testConfig(t *testing.T, when spec.G, it spec.S) {
 				h.AssertEq(t, len(subject.RunImages), 0)
 			})
 		})
+
+		when("config has registries", func() {
+			it("reads the registry settings", func() {
+				h.AssertNil(t, ioutil.WriteFile(configPath, []byte(`
+[[registries]]
+  name = "registry.example.com:5000"
+  insecure = true
+  mirrors = ["mirror.example.com/registry"]
+  username = "some-user"
+  password-env = "SOME_PASSWORD"
+`), 0666))
+
+				subject, err := config.Read(configPath)
+				h.AssertNil(t, err)
+				h.AssertEq(t, subject.Registries, []config.Registry{{
+					Name:        "registry.example.com:5000",
+					Insecure:    true,
+					Mirrors:     []string{"mirror.example.com/registry"},
+					Username:    "some-user",
+					PasswordEnv: "SOME_PASSWORD",
+				}})
+			})
+
+			it("errors when a registry has no name", func() {
+				h.AssertNil(t, ioutil.WriteFile(configPath, []byte("[[registries]]\n  insecure = true\n"), 0666))
+
+				_, err := config.Read(configPath)
+				h.AssertError(t, err, "registry 1 is missing a name")
+			})
+
+			it("errors when a registry is configured twice", func() {
+				h.AssertNil(t, ioutil.WriteFile(configPath, []byte(`
+[[registries]]
+  name = "registry.example.com"
+[[registries]]
+  name = "registry.example.com"
+`), 0666))
+
+				_, err := config.Read(configPath)
+				h.AssertError(t, err, "registry 'registry.example.com' is configured more than once")
+			})
+
+			it("errors when a password is referenced without a username", func() {
+				h.AssertNil(t, ioutil.WriteFile(configPath, []byte(`
+[[registries]]
+  name = "registry.example.com"
+  password-env = "SOME_PASSWORD"
+`), 0666))
+
+				_, err := config.Read(configPath)
+				h.AssertError(t, err, "registry 'registry.example.com' sets 'password-env' without 'username'")
+			})
+		})
 	})
 
 	when("#Write", func() {
@@ -19,7 +19,7 @@ type CreateBuilderOptions struct {
 	BuilderName   string
 	BuilderConfig builder.Config
 	Publish       bool
-	NoPull        bool
+	PullPolicy    image.PullPolicy // for the build image or base builder and the buildpack images, with image.PullNever run images are only looked for on the daemon
 }
 
 func (c *Client) CreateBuilder(ctx context.Context, opts CreateBuilderOptions) error {
@@ -27,11 +27,15 @@ func (c *Client) CreateBuilder(ctx context.Context, opts CreateBuilderOptions) e
 		return errors.Wrap(err, "invalid builder config")
 	}
 
+	if opts.BuilderConfig.BaseBuilder != "" {
+		return c.extendBuilder(ctx, opts)
+	}
+
 	if err := c.validateRunImageConfig(ctx, opts); err != nil {
 		return err
 	}
 
-	baseImage, err := c.imageFetcher.Fetch(ctx, opts.BuilderConfig.Stack.BuildImage, !opts.Publish, !opts.NoPull)
+	baseImage, err := c.imageFetcher.Fetch(ctx, opts.BuilderConfig.Stack.BuildImage, !opts.Publish, opts.PullPolicy)
 	if err != nil {
 		return err
 	}
@@ -52,7 +56,7 @@ func (c *Client) CreateBuilder(ctx context.Context, opts CreateBuilderOptions) e
 		)
 	}
 
-	lifecycle, err := c.fetchLifecycle(opts.BuilderConfig.Lifecycle)
+	lifecycle, err := c.fetchLifecycle(opts.BuilderConfig.Lifecycle, opts.PullPolicy)
 	if err != nil {
 		return errors.Wrap(err, "fetch lifecycle")
 	}
@@ -61,34 +65,176 @@ func (c *Client) CreateBuilder(ctx context.Context, opts CreateBuilderOptions) e
 		return errors.Wrap(err, "setting lifecycle")
 	}
 
-	for _, b := range opts.BuilderConfig.Buildpacks {
-		err := ensureBPSupport(b.URI)
+	buildpacks, err := c.fetchBuildpacks(opts.BuilderConfig.Buildpacks, opts.PullPolicy)
+	if err != nil {
+		return err
+	}
+	for _, bp := range buildpacks {
+		builderImage.AddBuildpack(bp)
+	}
+
+	builderImage.SetOrder(opts.BuilderConfig.Order)
+	builderImage.SetStackInfo(opts.BuilderConfig.Stack)
+
+	return builderImage.Save()
+}
+
+// extendBuilder creates the builder on top of the base builder of the config, reusing the layers of its lifecycle and
+// buildpacks. The base's description, lifecycle, order and stack are kept unless the config sets them. Buildpacks of
+// the config replace the base's versions of the same buildpack.
+func (c *Client) extendBuilder(ctx context.Context, opts CreateBuilderOptions) error {
+	config := opts.BuilderConfig
+
+	baseImage, err := c.imageFetcher.Fetch(ctx, config.BaseBuilder, !opts.Publish, opts.PullPolicy)
+	if err != nil {
+		return err
+	}
+
+	builderImage, err := builder.GetBuilder(baseImage)
+	if err != nil {
+		return errors.Wrap(err, "invalid base builder")
+	}
+	c.logger.Debugf("Creating builder %s from base builder %s", style.Symbol(opts.BuilderName), style.Symbol(baseImage.Name()))
+	baseImage.Rename(opts.BuilderName)
+
+	if config.Stack.ID != "" && config.Stack.ID != builderImage.StackID {
+		return fmt.Errorf(
+			"stack %s from builder config is incompatible with stack %s from base builder",
+			style.Symbol(config.Stack.ID),
+			style.Symbol(builderImage.StackID),
+		)
+	}
+	config.Stack.ID = builderImage.StackID
+	if config.Stack.RunImage == "" {
+		config.Stack.RunImage = builderImage.GetStackInfo().RunImage.Image
+		if len(config.Stack.RunImageMirrors) == 0 {
+			config.Stack.RunImageMirrors = builderImage.GetStackInfo().RunImage.Mirrors
+		}
+	}
+	opts.BuilderConfig = config
+	if err := c.validateRunImageConfig(ctx, opts); err != nil {
+		return err
+	}
+
+	if config.Description != "" {
+		builderImage.SetDescription(config.Description)
+	}
+
+	if config.Lifecycle.URI != "" || config.Lifecycle.Version != "" {
+		lifecycle, err := c.fetchLifecycle(config.Lifecycle, opts.PullPolicy)
+		if err != nil {
+			return errors.Wrap(err, "fetch lifecycle")
+		}
+		if err := builderImage.SetLifecycle(lifecycle); err != nil {
+			return errors.Wrap(err, "setting lifecycle")
+		}
+	}
+
+	for _, bp := range config.RemoveBuildpacks {
+		if len(builderImage.RemoveBuildpack(bp.ID, bp.Version)) == 0 {
+			return fmt.Errorf("buildpack %s to remove was not found on base builder", style.Symbol(buildpackRefString(bp)))
+		}
+	}
+
+	buildpacks, err := c.fetchBuildpacks(config.Buildpacks, opts.PullPolicy)
+	if err != nil {
+		return err
+	}
+	replaced := map[string]bool{}
+	for _, bp := range buildpacks {
+		id := bp.Descriptor().Info.ID
+		if replaced[id] {
+			continue
+		}
+		replaced[id] = true
+		for _, removed := range builderImage.RemoveBuildpack(id, "") {
+			c.logger.Debugf("Replacing buildpack %s", style.Symbol(buildpackRefString(removed)))
+		}
+	}
+	for _, bp := range buildpacks {
+		builderImage.AddBuildpack(bp)
+	}
+
+	order := config.Order
+	if len(order) == 0 {
+		order, err = rewriteBaseOrder(builderImage.GetOrder(), builderImage.GetBuildpacks())
 		if err != nil {
 			return err
 		}
+	}
+	builderImage.SetOrder(order)
+	builderImage.SetStackInfo(config.Stack)
+
+	return builderImage.Save()
+}
+
+// rewriteBaseOrder returns a copy of the base builder's order for the buildpacks left on the builder. References to
+// versions no longer on the builder are left for the builder to resolve, and references to buildpacks that were
+// removed fail.
+func rewriteBaseOrder(baseOrder builder.Order, buildpacks []builder.BuildpackMetadata) (builder.Order, error) {
+	versions := map[string]map[string]bool{}
+	for _, bp := range buildpacks {
+		if versions[bp.ID] == nil {
+			versions[bp.ID] = map[string]bool{}
+		}
+		versions[bp.ID][bp.Version] = true
+	}
 
-		blob, err := c.downloader.Download(b.URI)
+	var order builder.Order
+	for _, entry := range baseOrder {
+		group := builder.OrderEntry{}
+		for _, ref := range entry.Group {
+			if versions[ref.ID] == nil {
+				return nil, fmt.Errorf(
+					"order of base builder includes removed buildpack %s, the builder config must define an %s",
+					style.Symbol(buildpackRefString(ref.BuildpackInfo)),
+					style.Symbol("order"),
+				)
+			}
+			if !versions[ref.ID][ref.Version] {
+				ref.Version = ""
+			}
+			group.Group = append(group.Group, ref)
+		}
+		order = append(order, group)
+	}
+	return order, nil
+}
+
+func buildpackRefString(bp builder.BuildpackInfo) string {
+	if bp.Version == "" {
+		return bp.ID
+	}
+	return bp.ID + "@" + bp.Version
+}
+
+// fetchBuildpacks downloads the buildpacks of the builder config and validates them against their config
+func (c *Client) fetchBuildpacks(configs []builder.BuildpackConfig, pullPolicy image.PullPolicy) ([]builder.Buildpack, error) {
+	var buildpacks []builder.Buildpack
+	for _, b := range configs {
+		err := ensureBPSupport(b.URI)
+		if err != nil {
+			return nil, err
+		}
+
+		blob, err := c.downloader.Download(b.URI, pullPolicy)
 		if err != nil {
-			return errors.Wrapf(err, "downloading buildpack from %s", style.Symbol(b.URI))
+			return nil, errors.Wrapf(err, "downloading buildpack from %s", style.Symbol(b.URI))
 		}
 
 		fetchedBp, err := builder.NewBuildpack(blob)
 		if err != nil {
-			return errors.Wrap(err, "creating buildpack")
+			return nil, errors.Wrap(err, "creating buildpack")
 		}
 
 		err = validateBuildpack(fetchedBp, b.URI, b.ID, b.Version)
 		if err != nil {
-			return errors.Wrap(err, "invalid buildpack")
+			return nil, errors.Wrap(err, "invalid buildpack")
 		}
 
-		builderImage.AddBuildpack(fetchedBp)
+		buildpacks = append(buildpacks, fetchedBp)
 	}
-
-	builderImage.SetOrder(opts.BuilderConfig.Order)
-	builderImage.SetStackInfo(opts.BuilderConfig.Stack)
-
-	return builderImage.Save()
+	return buildpacks, nil
 }
 
 func validateBuildpack(bp builder.Buildpack, source, expectedID, expectedBPVersion string) error {
@@ -113,7 +259,7 @@ func validateBuildpack(bp builder.Buildpack, source, expectedID, expectedBPVersi
 	return nil
 }
 
-func (c *Client) fetchLifecycle(config builder.LifecycleConfig) (builder.Lifecycle, error) {
+func (c *Client) fetchLifecycle(config builder.LifecycleConfig, pullPolicy image.PullPolicy) (builder.Lifecycle, error) {
 	if config.Version != "" && config.URI != "" {
 		return nil, errors.Errorf(
 			"%s can only declare %s or %s, not both",
@@ -135,7 +281,7 @@ func (c *Client) fetchLifecycle(config builder.LifecycleConfig) (builder.Lifecyc
 		uri = uriFromLifecycleVersion(*semver.MustParse(builder.DefaultLifecycleVersion))
 	}
 
-	b, err := c.downloader.Download(uri)
+	b, err := c.downloader.Download(uri, pullPolicy)
 	if err != nil {
 		return nil, errors.Wrap(err, "downloading lifecycle")
 	}
@@ -153,16 +299,26 @@ func uriFromLifecycleVersion(version semver.Version) string {
 }
 
 func validateBuilderConfig(conf builder.Config) error {
-	if conf.Stack.ID == "" {
-		return errors.New("stack.id is required")
-	}
+	if conf.BaseBuilder != "" {
+		if conf.Stack.BuildImage != "" {
+			return errors.New("stack.build-image cannot be used with base-builder")
+		}
+	} else {
+		if len(conf.RemoveBuildpacks) > 0 {
+			return errors.New("remove-buildpacks requires base-builder")
+		}
 
-	if conf.Stack.BuildImage == "" {
-		return errors.New("stack.build-image is required")
-	}
+		if conf.Stack.ID == "" {
+			return errors.New("stack.id is required")
+		}
 
-	if conf.Stack.RunImage == "" {
-		return errors.New("stack.run-image is required")
+		if conf.Stack.BuildImage == "" {
+			return errors.New("stack.build-image is required")
+		}
+
+		if conf.Stack.RunImage == "" {
+			return errors.New("stack.run-image is required")
+		}
 	}
 
 	if runtime.GOOS == "windows" {
@@ -180,7 +336,7 @@ func (c *Client) validateRunImageConfig(ctx context.Context, opts CreateBuilderO
 	var runImages []imgutil.Image
 	for _, i := range append([]string{opts.BuilderConfig.Stack.RunImage}, opts.BuilderConfig.Stack.RunImageMirrors...) {
 		if !opts.Publish {
-			img, err := c.imageFetcher.Fetch(ctx, i, true, false)
+			img, err := c.imageFetcher.Fetch(ctx, i, true, image.PullNever)
 			if err != nil {
 				if errors.Cause(err) != image.ErrNotFound {
 					return err
@@ -191,7 +347,12 @@ func (c *Client) validateRunImageConfig(ctx context.Context, opts CreateBuilderO
 			}
 		}
 
-		img, err := c.imageFetcher.Fetch(ctx, i, false, false)
+		if !opts.Publish && opts.PullPolicy == image.PullNever {
+			c.logger.Warnf("run image %s is not on the daemon", style.Symbol(i))
+			continue
+		}
+
+		img, err := c.imageFetcher.Fetch(ctx, i, false, image.PullNever)
 		if err != nil {
 			if errors.Cause(err) != image.ErrNotFound {
 				return err
@@ -20,6 +20,7 @@ import (
 
 	"github.com/buildpack/pack/blob"
 	"github.com/buildpack/pack/builder"
+	"github.com/buildpack/pack/image"
 	ifakes "github.com/buildpack/pack/internal/fakes"
 	"github.com/buildpack/pack/logging"
 	h "github.com/buildpack/pack/testhelpers"
@@ -69,9 +70,9 @@ func testCreateBuilder(t *testing.T, when spec.G, it spec.S) {
 			imageFetcher.LocalImages["some/run-image"] = fakeRunImage
 			imageFetcher.RemoteImages["localhost:5000/some-run-image"] = fakeRunImageMirror
 
-			mockDownloader.EXPECT().Download("https://example.fake/bp-one.tgz").Return(blob.NewBlob(filepath.Join("testdata", "buildpack")), nil).AnyTimes()
-			mockDownloader.EXPECT().Download("some/buildpack/dir").Return(blob.NewBlob(filepath.Join("testdata", "buildpack")), nil).AnyTimes()
-			mockDownloader.EXPECT().Download("file:///some-lifecycle").Return(blob.NewBlob(filepath.Join("testdata", "lifecycle")), nil).AnyTimes()
+			mockDownloader.EXPECT().Download("https://example.fake/bp-one.tgz", image.PullAlways).Return(blob.NewBlob(filepath.Join("testdata", "buildpack")), nil).AnyTimes()
+			mockDownloader.EXPECT().Download("some/buildpack/dir", image.PullAlways).Return(blob.NewBlob(filepath.Join("testdata", "buildpack")), nil).AnyTimes()
+			mockDownloader.EXPECT().Download("file:///some-lifecycle", image.PullAlways).Return(blob.NewBlob(filepath.Join("testdata", "lifecycle")), nil).AnyTimes()
 
 			subject = &Client{
 				logger:       log,
@@ -102,8 +103,8 @@ func testCreateBuilder(t *testing.T, when spec.G, it spec.S) {
 					},
 					Lifecycle: builder.LifecycleConfig{URI: "file:///some-lifecycle"},
 				},
-				Publish: false,
-				NoPull:  false,
+				Publish:    false,
+				PullPolicy: image.PullAlways,
 			}
 
 			var err error
@@ -205,6 +206,31 @@ func testCreateBuilder(t *testing.T, when spec.G, it spec.S) {
 					h.AssertNil(t, err)
 				})
 			})
+
+			when("pull policy is never", func() {
+				it.Before(func() {
+					opts.PullPolicy = image.PullNever
+				})
+
+				it("should only look for the run images on the daemon", func() {
+					mockDownloader.EXPECT().Download("https://example.fake/bp-one.tgz", image.PullNever).Return(blob.NewBlob(filepath.Join("testdata", "buildpack")), nil).AnyTimes()
+					mockDownloader.EXPECT().Download("file:///some-lifecycle", image.PullNever).Return(blob.NewBlob(filepath.Join("testdata", "lifecycle")), nil).AnyTimes()
+
+					err := subject.CreateBuilder(context.TODO(), opts)
+					h.AssertNil(t, err)
+
+					h.AssertContains(t, out.String(), "Warning: run image 'localhost:5000/some-run-image' is not on the daemon")
+					h.AssertEq(t, imageFetcher.FetchCalls["localhost:5000/some-run-image"].Daemon, true)
+				})
+
+				it("passes the pull policy to the downloader for buildpack images", func() {
+					mockDownloader.EXPECT().Download("https://example.fake/bp-one.tgz", image.PullNever).Return(blob.NewBlob(filepath.Join("testdata", "buildpack")), nil).MinTimes(1)
+					mockDownloader.EXPECT().Download("file:///some-lifecycle", image.PullNever).Return(blob.NewBlob(filepath.Join("testdata", "lifecycle")), nil).MinTimes(1)
+
+					err := subject.CreateBuilder(context.TODO(), opts)
+					h.AssertNil(t, err)
+				})
+			})
 		})
 
 		when("only lifecycle version is provided", func() {
@@ -216,6 +242,7 @@ func testCreateBuilder(t *testing.T, when spec.G, it spec.S) {
 			it("should download from predetermined uri", func() {
 				mockDownloader.EXPECT().Download(
 					"https://github.com/buildpack/lifecycle/releases/download/v3.4.5/lifecycle-v3.4.5+linux.x86-64.tgz",
+					image.PullAlways,
 				).Return(
 					blob.NewBlob(filepath.Join("testdata", "lifecycle")), nil,
 				).MinTimes(1)
@@ -239,6 +266,7 @@ func testCreateBuilder(t *testing.T, when spec.G, it spec.S) {
 						expectedDefaultLifecycleVersion,
 						expectedDefaultLifecycleVersion,
 					),
+					image.PullAlways,
 				).Return(
 					blob.NewBlob(filepath.Join("testdata", "lifecycle")), nil,
 				).MinTimes(1)
@@ -287,6 +315,120 @@ func testCreateBuilder(t *testing.T, when spec.G, it spec.S) {
 			assertTarHasFile(t, layerTar, "/cnb/lifecycle/launcher")
 		})
 
+		when("the builder config has a base builder", func() {
+			var baseOpts CreateBuilderOptions
+
+			it.Before(func() {
+				opts.BuilderName = "some/base-builder"
+				h.AssertNil(t, subject.CreateBuilder(context.TODO(), opts))
+				imageFetcher.LocalImages["some/base-builder"] = fakeBuildImage
+
+				bpTwoDir := filepath.Join(tmpDir, "bp-one-v2")
+				h.AssertNil(t, os.MkdirAll(bpTwoDir, 0755))
+				h.AssertNil(t, ioutil.WriteFile(filepath.Join(bpTwoDir, "buildpack.toml"), []byte(`api = "0.3"
+
+[buildpack]
+id = "bp.one"
+version = "2.0.0"
+
+[[stacks]]
+id = "some.stack.id"
+`), 0644))
+				mockDownloader.EXPECT().Download("https://example.fake/bp-one-v2.tgz", image.PullAlways).Return(blob.NewBlob(bpTwoDir), nil).AnyTimes()
+				mockDownloader.EXPECT().Download("https://example.fake/bp-two.tgz", image.PullAlways).Return(blob.NewBlob(filepath.Join("testdata", "buildpack2")), nil).AnyTimes()
+
+				baseOpts = CreateBuilderOptions{
+					BuilderName: "some/extended-builder",
+					BuilderConfig: builder.Config{
+						BaseBuilder: "some/base-builder",
+					},
+					PullPolicy: image.PullAlways,
+				}
+			})
+
+			it("keeps the lifecycle, buildpacks, order and stack of the base builder", func() {
+				baseOpts.BuilderConfig.Buildpacks = []builder.BuildpackConfig{{URI: "https://example.fake/bp-two.tgz"}}
+
+				h.AssertNil(t, subject.CreateBuilder(context.TODO(), baseOpts))
+
+				builderImage, err := builder.GetBuilder(fakeBuildImage)
+				h.AssertNil(t, err)
+				h.AssertEq(t, builderImage.Name(), "some/extended-builder")
+				h.AssertEq(t, builderImage.Description(), "Some description")
+				h.AssertEq(t, builderImage.GetLifecycleDescriptor().Info.Version.String(), "3.4.5")
+				h.AssertEq(t, builderImage.GetStackInfo().RunImage.Image, "some/run-image")
+				h.AssertEq(t, builderImage.GetStackInfo().RunImage.Mirrors, []string{"localhost:5000/some-run-image"})
+				h.AssertEq(t, len(builderImage.GetBuildpacks()), 2)
+				h.AssertEq(t, builderImage.GetBuildpacks()[1].BuildpackInfo, builder.BuildpackInfo{ID: "some-other-buildpack-id", Version: "some-other-buildpack-version"})
+				h.AssertEq(t, builderImage.GetOrder(), builder.Order{{
+					Group: []builder.BuildpackRef{{BuildpackInfo: builder.BuildpackInfo{ID: "bp.one", Version: "1.2.3"}}},
+				}})
+			})
+
+			it("replaces the base builder's versions of a buildpack", func() {
+				baseOpts.BuilderConfig.Buildpacks = []builder.BuildpackConfig{{URI: "https://example.fake/bp-one-v2.tgz"}}
+
+				h.AssertNil(t, subject.CreateBuilder(context.TODO(), baseOpts))
+
+				builderImage, err := builder.GetBuilder(fakeBuildImage)
+				h.AssertNil(t, err)
+				bpInfo := builder.BuildpackInfo{ID: "bp.one", Version: "2.0.0"}
+				h.AssertEq(t, builderImage.GetBuildpacks(), []builder.BuildpackMetadata{{BuildpackInfo: bpInfo, Latest: true}})
+				h.AssertEq(t, builderImage.GetOrder(), builder.Order{{Group: []builder.BuildpackRef{{BuildpackInfo: bpInfo}}}})
+
+				_, err = fakeBuildImage.FindLayerWithPath("/cnb/buildpacks/bp.one/.wh.1.2.3")
+				h.AssertNil(t, err)
+			})
+
+			it("removes buildpacks of the base builder", func() {
+				baseOpts.BuilderConfig.Buildpacks = []builder.BuildpackConfig{{URI: "https://example.fake/bp-two.tgz"}}
+				baseOpts.BuilderConfig.RemoveBuildpacks = []builder.BuildpackInfo{{ID: "bp.one"}}
+				baseOpts.BuilderConfig.Order = builder.Order{{
+					Group: []builder.BuildpackRef{{BuildpackInfo: builder.BuildpackInfo{ID: "some-other-buildpack-id"}}},
+				}}
+
+				h.AssertNil(t, subject.CreateBuilder(context.TODO(), baseOpts))
+
+				builderImage, err := builder.GetBuilder(fakeBuildImage)
+				h.AssertNil(t, err)
+				h.AssertEq(t, len(builderImage.GetBuildpacks()), 1)
+				h.AssertEq(t, builderImage.GetBuildpacks()[0].ID, "some-other-buildpack-id")
+
+				layerTar, err := fakeBuildImage.FindLayerWithPath("/cnb/buildpacks/bp.one/.wh.1.2.3")
+				h.AssertNil(t, err)
+				assertTarHasFile(t, layerTar, "/buildpacks/bp.one/.wh.1.2.3")
+				assertTarHasFile(t, layerTar, "/buildpacks/bp.one/.wh.latest")
+			})
+
+			it("fails when the order of the base builder includes a removed buildpack", func() {
+				baseOpts.BuilderConfig.RemoveBuildpacks = []builder.BuildpackInfo{{ID: "bp.one", Version: "1.2.3"}}
+
+				err := subject.CreateBuilder(context.TODO(), baseOpts)
+				h.AssertError(t, err, "order of base builder includes removed buildpack 'bp.one@1.2.3'")
+			})
+
+			it("fails when a buildpack to remove is not on the base builder", func() {
+				baseOpts.BuilderConfig.RemoveBuildpacks = []builder.BuildpackInfo{{ID: "bp.missing"}}
+
+				err := subject.CreateBuilder(context.TODO(), baseOpts)
+				h.AssertError(t, err, "buildpack 'bp.missing' to remove was not found on base builder")
+			})
+
+			it("fails when the stack ID does not match the base builder", func() {
+				baseOpts.BuilderConfig.Stack.ID = "other.stack.id"
+
+				err := subject.CreateBuilder(context.TODO(), baseOpts)
+				h.AssertError(t, err, "stack 'other.stack.id' from builder config is incompatible with stack 'some.stack.id' from base builder")
+			})
+
+			it("fails when a build image is also given", func() {
+				baseOpts.BuilderConfig.Stack.BuildImage = "some/build-image"
+
+				err := subject.CreateBuilder(context.TODO(), baseOpts)
+				h.AssertError(t, err, "stack.build-image cannot be used with base-builder")
+			})
+		})
+
 		when("windows", func() {
 			it.Before(func() {
 				h.SkipIf(t, runtime.GOOS != "windows", "Skipped on non-windows")
@@ -5,6 +5,7 @@ import (
 	"encoding/base64"
 	"fmt"
 	"io"
+	"net/http"
 	"strings"
 
 	"github.com/buildpack/imgutil"
@@ -14,49 +15,184 @@ import (
 	"github.com/docker/docker/pkg/jsonmessage"
 	"github.com/docker/docker/pkg/term"
 	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
 	"github.com/pkg/errors"
 
+	"github.com/buildpack/pack/internal/registry"
 	"github.com/buildpack/pack/logging"
 	"github.com/buildpack/pack/style"
 )
 
 type Fetcher struct {
-	docker *client.Client
-	logger logging.Logger
+	docker     *client.Client
+	logger     logging.Logger
+	registries *registry.Registries
 }
 
-func NewFetcher(logger logging.Logger, docker *client.Client) *Fetcher {
+// NewFetcher returns a fetcher using the credentials, insecure flags and mirrors of registries, which may be nil
+func NewFetcher(logger logging.Logger, docker *client.Client, registries *registry.Registries) *Fetcher {
 	return &Fetcher{
-		logger: logger,
-		docker: docker,
+		logger:     logger,
+		docker:     docker,
+		registries: registries,
 	}
 }
 
 var ErrNotFound = errors.New("not found")
 
-func (f *Fetcher) Fetch(ctx context.Context, name string, daemon, pull bool) (image imgutil.Image, err error) {
-	image, err = imgutil.NewRemoteImage(name, authn.DefaultKeychain)
+// Fetch returns the image from the daemon, or from its registry when daemon is false. The pull policy decides whether
+// an image on the daemon is pulled first, with PullNever the registry is not contacted at all.
+func (f *Fetcher) Fetch(ctx context.Context, name string, daemon bool, pullPolicy PullPolicy) (imgutil.Image, error) {
+	if !daemon {
+		return f.fetchRemoteImage(name)
+	}
+
+	switch pullPolicy {
+	case PullNever:
+		return f.fetchDaemonImage(name)
+	case PullIfNotPresent:
+		img, err := f.fetchDaemonImage(name)
+		if errors.Cause(err) != ErrNotFound {
+			return img, err
+		}
+	}
+
+	if err := f.pull(ctx, name); err != nil {
+		return nil, err
+	}
+	return f.fetchDaemonImage(name)
+}
+
+// pull pulls the image from the first mirror of its registry that has it, falling back to the registry itself. An
+// image pulled from a mirror is tagged with its name on the registry.
+func (f *Fetcher) pull(ctx context.Context, name string) error {
+	mirrors, err := f.registries.Mirrors(name)
+	if err != nil {
+		return err
+	}
+	for _, mirror := range mirrors {
+		if !f.remoteFound(mirror) {
+			f.logger.Debugf("Image %s not found on mirror %s, skipping", style.Symbol(name), style.Symbol(mirror))
+			continue
+		}
+		f.logger.Debugf("Pulling image %s from mirror %s", style.Symbol(name), style.Symbol(mirror))
+		logging.LogEvent(f.logger, logging.Event{Type: logging.EventImagePull, Image: name})
+		if err := f.pullImage(ctx, mirror); err != nil {
+			return err
+		}
+		return f.docker.ImageTag(ctx, mirror, name)
+	}
+
+	if f.remoteFound(name) {
+		f.logger.Debugf("Pulling image %s", style.Symbol(name))
+		logging.LogEvent(f.logger, logging.Event{Type: logging.EventImagePull, Image: name})
+		return f.pullImage(ctx, name)
+	}
+	return nil
+}
+
+// remoteFound reports whether the registry has the image, reaching insecure registries over plain HTTP
+func (f *Fetcher) remoteFound(name string) bool {
+	ref, err := f.registries.ParseReference(name)
+	if err != nil {
+		return false
+	}
+	auth, err := f.registries.Keychain().Resolve(ref.Context().Registry)
+	if err != nil {
+		return false
+	}
+	_, err = remote.Image(ref, remote.WithAuth(auth), remote.WithTransport(http.DefaultTransport))
+	return err == nil
+}
+
+func (f *Fetcher) fetchRemoteImage(name string) (imgutil.Image, error) {
+	plainHTTP, err := f.registries.RequiresPlainHTTP(name)
 	if err != nil {
 		return nil, err
 	}
+	if plainHTTP {
+		return nil, fmt.Errorf("image %s is on an insecure registry, which can only be used through the daemon", style.Symbol(name))
+	}
 
-	remoteFound := image.Found()
+	image, err := imgutil.NewRemoteImage(name, f.registries.Keychain())
+	if err != nil {
+		return nil, err
+	}
+
+	if !image.Found() {
+		return nil, errors.Wrapf(ErrNotFound, "image %s does not exist in registry", style.Symbol(name))
+	}
+	return image, nil
+}
 
+// Layers returns the diff IDs of the layers of the image, bottom to top. It reads the image on the daemon, or on its
+// registry when daemon is false.
+func (f *Fetcher) Layers(ctx context.Context, name string, daemon bool) ([]string, error) {
 	if daemon {
-		if remoteFound && pull {
-			f.logger.Debugf("Pulling image %s", style.Symbol(name))
-			if err := f.pullImage(ctx, name); err != nil {
-				return nil, err
-			}
+		inspect, _, err := f.docker.ImageInspectWithRaw(ctx, name)
+		if client.IsErrNotFound(err) {
+			return nil, errors.Wrapf(ErrNotFound, "image %s does not exist on the daemon", style.Symbol(name))
 		}
-		return f.fetchDaemonImage(name)
+		if err != nil {
+			return nil, err
+		}
+		return inspect.RootFS.Layers, nil
 	}
 
-	if !remoteFound {
-		return nil, errors.Wrapf(ErrNotFound, "image %s does not exist in registry", style.Symbol(name))
+	ref, err := f.registries.ParseReference(name)
+	if err != nil {
+		return nil, err
+	}
+	auth, err := f.registries.Keychain().Resolve(ref.Context().Registry)
+	if err != nil {
+		return nil, err
 	}
+	img, err := remote.Image(ref, remote.WithAuth(auth), remote.WithTransport(http.DefaultTransport))
+	if err != nil {
+		return nil, err
+	}
+	configFile, err := img.ConfigFile()
+	if err != nil {
+		return nil, err
+	}
+	var layers []string
+	for _, diffID := range configFile.RootFS.DiffIDs {
+		layers = append(layers, diffID.String())
+	}
+	return layers, nil
+}
 
-	return image, nil
+// ID returns the ID of the image, the digest of its config. It reads the image on the daemon, or on its registry when
+// daemon is false.
+func (f *Fetcher) ID(ctx context.Context, name string, daemon bool) (string, error) {
+	if daemon {
+		inspect, _, err := f.docker.ImageInspectWithRaw(ctx, name)
+		if client.IsErrNotFound(err) {
+			return "", errors.Wrapf(ErrNotFound, "image %s does not exist on the daemon", style.Symbol(name))
+		}
+		if err != nil {
+			return "", err
+		}
+		return inspect.ID, nil
+	}
+
+	ref, err := f.registries.ParseReference(name)
+	if err != nil {
+		return "", err
+	}
+	auth, err := f.registries.Keychain().Resolve(ref.Context().Registry)
+	if err != nil {
+		return "", err
+	}
+	img, err := remote.Image(ref, remote.WithAuth(auth), remote.WithTransport(http.DefaultTransport))
+	if err != nil {
+		return "", err
+	}
+	configName, err := img.ConfigName()
+	if err != nil {
+		return "", err
+	}
+	return configName.String(), nil
 }
 
 func (f *Fetcher) fetchDaemonImage(name string) (imgutil.Image, error) {
@@ -72,7 +208,7 @@ func (f *Fetcher) fetchDaemonImage(name string) (imgutil.Image, error) {
 }
 
 func (f *Fetcher) pullImage(ctx context.Context, imageID string) error {
-	auth, err := registryAuth(imageID)
+	auth, err := registryAuth(f.registries.Keychain(), imageID)
 	if err != nil {
 		return err
 	}
@@ -92,9 +228,9 @@ func (f *Fetcher) pullImage(ctx context.Context, imageID string) error {
 	return rc.Close()
 }
 
-func registryAuth(ref string) (string, error) {
+func registryAuth(keychain authn.Keychain, ref string) (string, error) {
 	var regAuth string
-	_, a, err := auth.ReferenceForRepoName(authn.DefaultKeychain, ref)
+	_, a, err := auth.ReferenceForRepoName(keychain, ref)
 	if err != nil {
 		return "", errors.Wrapf(err, "resolve auth for ref %s", ref)
 	}
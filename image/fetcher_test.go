@@ -51,7 +51,7 @@ func testFetcher(t *testing.T, when spec.G, it spec.S) {
 	it.Before(func() {
 		repo = "some-org/" + h.RandString(10)
 		repoName = registryConfig.RepoName(repo)
-		fetcher = image.NewFetcher(fakes.NewFakeLogger(ioutil.Discard), docker)
+		fetcher = image.NewFetcher(fakes.NewFakeLogger(ioutil.Discard), docker, nil)
 	})
 
 	when("#Fetch", func() {
@@ -68,7 +68,7 @@ func testFetcher(t *testing.T, when spec.G, it spec.S) {
 				})
 
 				it("returns the remote image", func() {
-					img, err := fetcher.Fetch(context.TODO(), repoName, false, false)
+					img, err := fetcher.Fetch(context.TODO(), repoName, false, image.PullAlways)
 					h.AssertNil(t, err)
 
 					label, err := img.Label("repo_name")
@@ -79,14 +79,14 @@ func testFetcher(t *testing.T, when spec.G, it spec.S) {
 
 			when("there is no remote image", func() {
 				it("returns an error", func() {
-					_, err := fetcher.Fetch(context.TODO(), repoName, false, false)
+					_, err := fetcher.Fetch(context.TODO(), repoName, false, image.PullAlways)
 					h.AssertError(t, err, fmt.Sprintf("image '%s' does not exist in registry", repoName))
 				})
 			})
 		})
 
 		when("daemon is true", func() {
-			when("pull is false", func() {
+			when("pull policy is never", func() {
 				when("there is a local image", func() {
 					it.Before(func() {
 						h.CreateImageOnLocal(
@@ -102,7 +102,7 @@ func testFetcher(t *testing.T, when spec.G, it spec.S) {
 					})
 
 					it("returns the local image", func() {
-						img, err := fetcher.Fetch(context.TODO(), repoName, true, false)
+						img, err := fetcher.Fetch(context.TODO(), repoName, true, image.PullNever)
 						h.AssertNil(t, err)
 
 						label, err := img.Label("repo_name")
@@ -113,13 +113,60 @@ func testFetcher(t *testing.T, when spec.G, it spec.S) {
 
 				when("there is no local image", func() {
 					it("returns an error", func() {
-						_, err := fetcher.Fetch(context.TODO(), repoName, true, false)
+						_, err := fetcher.Fetch(context.TODO(), repoName, true, image.PullNever)
 						h.AssertError(t, err, fmt.Sprintf("image '%s' does not exist on the daemon", repoName))
 					})
 				})
 			})
 
-			when("pull is true", func() {
+			when("pull policy is if-not-present", func() {
+				it.Before(func() {
+					h.CreateImageOnRemote(
+						t,
+						docker,
+						registryConfig,
+						repo,
+						"FROM scratch\nLABEL origin=remote",
+					)
+				})
+
+				it.After(func() {
+					h.DockerRmi(docker, repoName)
+				})
+
+				when("there is a local image", func() {
+					it.Before(func() {
+						h.CreateImageOnLocal(
+							t,
+							docker,
+							repoName,
+							"FROM scratch\nLABEL origin=local",
+						)
+					})
+
+					it("returns the local image without pulling", func() {
+						img, err := fetcher.Fetch(context.TODO(), repoName, true, image.PullIfNotPresent)
+						h.AssertNil(t, err)
+
+						label, err := img.Label("origin")
+						h.AssertNil(t, err)
+						h.AssertEq(t, label, "local")
+					})
+				})
+
+				when("there is no local image", func() {
+					it("pulls the image and returns the local copy", func() {
+						img, err := fetcher.Fetch(context.TODO(), repoName, true, image.PullIfNotPresent)
+						h.AssertNil(t, err)
+
+						label, err := img.Label("origin")
+						h.AssertNil(t, err)
+						h.AssertEq(t, label, "remote")
+					})
+				})
+			})
+
+			when("pull policy is always", func() {
 				when("there is a remote image", func() {
 					it.Before(func() {
 						h.CreateImageOnRemote(
@@ -136,7 +183,7 @@ func testFetcher(t *testing.T, when spec.G, it spec.S) {
 					})
 
 					it("pull the image and return the local copy", func() {
-						img, err := fetcher.Fetch(context.TODO(), repoName, true, true)
+						img, err := fetcher.Fetch(context.TODO(), repoName, true, image.PullAlways)
 						h.AssertNil(t, err)
 
 						label, err := img.Label("repo_name")
@@ -161,7 +208,7 @@ func testFetcher(t *testing.T, when spec.G, it spec.S) {
 						})
 
 						it("returns the local image", func() {
-							img, err := fetcher.Fetch(context.TODO(), repoName, true, true)
+							img, err := fetcher.Fetch(context.TODO(), repoName, true, image.PullAlways)
 							h.AssertNil(t, err)
 
 							label, err := img.Label("repo_name")
@@ -172,7 +219,7 @@ func testFetcher(t *testing.T, when spec.G, it spec.S) {
 
 					when("there is no local image", func() {
 						it("returns an error", func() {
-							_, err := fetcher.Fetch(context.TODO(), repoName, true, true)
+							_, err := fetcher.Fetch(context.TODO(), repoName, true, image.PullAlways)
 							h.AssertError(t, err, fmt.Sprintf("image '%s' does not exist on the daemon", repoName))
 						})
 					})
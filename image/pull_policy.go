@@ -0,0 +1,43 @@
+package image
+
+import (
+	"fmt"
+
+	"github.com/buildpack/pack/style"
+)
+
+// PullPolicy decides when images on the daemon are pulled from their registry
+type PullPolicy int
+
+const (
+	// PullAlways pulls the image before every use, this is the default
+	PullAlways PullPolicy = iota
+	// PullIfNotPresent pulls the image only when the daemon does not have it
+	PullIfNotPresent
+	// PullNever uses the image on the daemon without contacting the registry
+	PullNever
+)
+
+var pullPolicyNames = map[PullPolicy]string{
+	PullAlways:       "always",
+	PullIfNotPresent: "if-not-present",
+	PullNever:        "never",
+}
+
+// ParsePullPolicy returns the policy with the given name, 'always', 'if-not-present' or 'never'
+func ParsePullPolicy(name string) (PullPolicy, error) {
+	for policy, policyName := range pullPolicyNames {
+		if policyName == name {
+			return policy, nil
+		}
+	}
+	return PullAlways, fmt.Errorf("invalid pull policy %s, must be one of %s, %s or %s",
+		style.Symbol(name), style.Symbol("always"), style.Symbol("if-not-present"), style.Symbol("never"))
+}
+
+func (p PullPolicy) String() string {
+	if name, ok := pullPolicyNames[p]; ok {
+		return name
+	}
+	return fmt.Sprintf("PullPolicy(%d)", int(p))
+}
@@ -0,0 +1,39 @@
+package image_test
+
+import (
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpack/pack/image"
+	h "github.com/buildpack/pack/testhelpers"
+)
+
+func TestPullPolicy(t *testing.T) {
+	color.NoColor = true
+	spec.Run(t, "PullPolicy", testPullPolicy, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testPullPolicy(t *testing.T, when spec.G, it spec.S) {
+	when("#ParsePullPolicy", func() {
+		it("parses the name of each policy", func() {
+			for _, policy := range []image.PullPolicy{image.PullAlways, image.PullIfNotPresent, image.PullNever} {
+				parsed, err := image.ParsePullPolicy(policy.String())
+				h.AssertNil(t, err)
+				h.AssertEq(t, parsed, policy)
+			}
+		})
+
+		it("fails for an unknown name", func() {
+			_, err := image.ParsePullPolicy("sometimes")
+			h.AssertError(t, err, "invalid pull policy 'sometimes', must be one of 'always', 'if-not-present' or 'never'")
+		})
+	})
+
+	it("defaults to always", func() {
+		var policy image.PullPolicy
+		h.AssertEq(t, policy, image.PullAlways)
+	})
+}
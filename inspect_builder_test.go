@@ -58,9 +58,9 @@ func testInspectBuilder(t *testing.T, when spec.G, it spec.S) {
 			when(fmt.Sprintf("daemon is %t", useDaemon), func() {
 				it.Before(func() {
 					if useDaemon {
-						mockImageFetcher.EXPECT().Fetch(gomock.Any(), "some/builder", true, false).Return(builderImage, nil)
+						mockImageFetcher.EXPECT().Fetch(gomock.Any(), "some/builder", true, image.PullNever).Return(builderImage, nil)
 					} else {
-						mockImageFetcher.EXPECT().Fetch(gomock.Any(), "some/builder", false, false).Return(builderImage, nil)
+						mockImageFetcher.EXPECT().Fetch(gomock.Any(), "some/builder", false, image.PullNever).Return(builderImage, nil)
 					}
 				})
 
@@ -81,6 +81,12 @@ func testInspectBuilder(t *testing.T, when spec.G, it spec.S) {
       "id": "test.bp.one",
       "version": "1.0.0",
       "latest": true
+    },
+    {
+      "id": "test.bp.meta",
+      "version": "2.0.0",
+      "latest": true,
+      "order": [{"group": [{"id": "test.bp.one", "version": "1.0.0", "optional": true}]}]
     }
   ],
   "groups": [
@@ -134,6 +140,17 @@ func testInspectBuilder(t *testing.T, when spec.G, it spec.S) {
 						})
 					})
 
+					it("sets the order of order buildpacks", func() {
+						builderInfo, err := subject.InspectBuilder("some/builder", useDaemon)
+						h.AssertNil(t, err)
+						h.AssertEq(t, builderInfo.Buildpacks[1].Order, builder.Order{
+							{Group: []builder.BuildpackRef{{
+								BuildpackInfo: builder.BuildpackInfo{ID: "test.bp.one", Version: "1.0.0"},
+								Optional:      true,
+							}}},
+						})
+					})
+
 					it("sets the groups", func() {
 						builderInfo, err := subject.InspectBuilder("some/builder", useDaemon)
 						h.AssertNil(t, err)
@@ -157,7 +174,7 @@ func testInspectBuilder(t *testing.T, when spec.G, it spec.S) {
 
 	when("fetcher fails to fetch the image", func() {
 		it.Before(func() {
-			mockImageFetcher.EXPECT().Fetch(gomock.Any(), "some/builder", false, false).Return(nil, errors.New("some-error"))
+			mockImageFetcher.EXPECT().Fetch(gomock.Any(), "some/builder", false, image.PullNever).Return(nil, errors.New("some-error"))
 		})
 
 		it("returns an error", func() {
@@ -170,7 +187,7 @@ func testInspectBuilder(t *testing.T, when spec.G, it spec.S) {
 		it.Before(func() {
 			notFoundImage := fakes.NewImage("", "", "")
 			notFoundImage.Delete()
-			mockImageFetcher.EXPECT().Fetch(gomock.Any(), "some/builder", true, false).Return(nil, errors.Wrap(image.ErrNotFound, "some-error"))
+			mockImageFetcher.EXPECT().Fetch(gomock.Any(), "some/builder", true, image.PullNever).Return(nil, errors.Wrap(image.ErrNotFound, "some-error"))
 		})
 
 		it("return nil metadata", func() {
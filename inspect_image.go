@@ -0,0 +1,54 @@
+package pack
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/buildpack/lifecycle/metadata"
+	"github.com/pkg/errors"
+
+	"github.com/buildpack/pack/image"
+	"github.com/buildpack/pack/style"
+)
+
+type ImageInfo struct {
+	StackID    string
+	Stack      metadata.StackMetadata
+	Base       metadata.RunImageMetadata
+	Buildpacks []metadata.BuildpackMetadata
+}
+
+func (c *Client) InspectImage(ctx context.Context, name string, daemon bool) (*ImageInfo, error) {
+	img, err := c.imageFetcher.Fetch(ctx, name, daemon, image.PullNever)
+	if err != nil {
+		if errors.Cause(err) == image.ErrNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	stackID, err := img.Label("io.buildpacks.stack.id")
+	if err != nil {
+		return nil, errors.Wrapf(err, "get label %s from image %s", style.Symbol("io.buildpacks.stack.id"), style.Symbol(name))
+	}
+
+	label, err := img.Label(metadata.AppMetadataLabel)
+	if err != nil {
+		return nil, errors.Wrapf(err, "get label %s from image %s", style.Symbol(metadata.AppMetadataLabel), style.Symbol(name))
+	}
+	if label == "" {
+		return nil, fmt.Errorf("image %s missing label %s", style.Symbol(name), style.Symbol(metadata.AppMetadataLabel))
+	}
+
+	md, err := metadata.GetAppMetadata(img)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ImageInfo{
+		StackID:    stackID,
+		Stack:      md.Stack,
+		Base:       md.RunImage,
+		Buildpacks: md.Buildpacks,
+	}, nil
+}
@@ -0,0 +1,157 @@
+package pack
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/buildpack/imgutil/fakes"
+	"github.com/buildpack/lifecycle/metadata"
+	"github.com/fatih/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpack/pack/image"
+	ifakes "github.com/buildpack/pack/internal/fakes"
+	h "github.com/buildpack/pack/testhelpers"
+)
+
+func TestInspectImage(t *testing.T) {
+	color.NoColor = true
+	spec.Run(t, "InspectImage", testInspectImage, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testInspectImage(t *testing.T, when spec.G, it spec.S) {
+	var (
+		subject          *Client
+		fakeImageFetcher *ifakes.FakeImageFetcher
+		fakeImage        *fakes.Image
+		out              bytes.Buffer
+	)
+
+	it.Before(func() {
+		fakeImageFetcher = ifakes.NewFakeImageFetcher()
+
+		subject = &Client{
+			logger:       ifakes.NewFakeLogger(&out),
+			imageFetcher: fakeImageFetcher,
+		}
+
+		fakeImage = fakes.NewImage("some/image", "", "")
+		h.AssertNil(t, fakeImage.SetLabel("io.buildpacks.stack.id", "test.stack.id"))
+		h.AssertNil(t, fakeImage.SetLabel(
+			"io.buildpacks.lifecycle.metadata",
+			`{
+  "stack": {
+    "runImage": {
+      "image": "some/run-image",
+      "mirrors": [
+        "some/mirror",
+        "other/mirror"
+      ]
+    }
+  },
+  "runImage": {
+    "topLayer": "some-top-layer",
+    "sha": "some-run-image-digest"
+  },
+  "buildpacks": [
+    {
+      "key": "test.bp.one",
+      "version": "1.0.0",
+      "layers": {
+        "some-layer": {"sha": "some-layer-sha", "launch": true}
+      }
+    },
+    {
+      "key": "test.bp.two",
+      "version": "2.0.0"
+    }
+  ]
+}`,
+		))
+		fakeImageFetcher.LocalImages["some/image"] = fakeImage
+		fakeImageFetcher.RemoteImages["some/image"] = fakeImage
+	})
+
+	it.After(func() {
+		fakeImage.Cleanup()
+	})
+
+	for _, useDaemon := range []bool{true, false} {
+		useDaemon := useDaemon
+		when(fmt.Sprintf("daemon is %t", useDaemon), func() {
+			it("fetches the image without pulling", func() {
+				_, err := subject.InspectImage(context.TODO(), "some/image", useDaemon)
+				h.AssertNil(t, err)
+				h.AssertEq(t, fakeImageFetcher.FetchCalls["some/image"].Daemon, useDaemon)
+				h.AssertEq(t, fakeImageFetcher.FetchCalls["some/image"].PullPolicy, image.PullNever)
+			})
+
+			it("returns the stack id", func() {
+				info, err := subject.InspectImage(context.TODO(), "some/image", useDaemon)
+				h.AssertNil(t, err)
+				h.AssertEq(t, info.StackID, "test.stack.id")
+			})
+
+			it("returns the stack run image and mirrors", func() {
+				info, err := subject.InspectImage(context.TODO(), "some/image", useDaemon)
+				h.AssertNil(t, err)
+				h.AssertEq(t, info.Stack, metadata.StackMetadata{
+					RunImage: metadata.StackRunImageMetadata{
+						Image:   "some/run-image",
+						Mirrors: []string{"some/mirror", "other/mirror"},
+					},
+				})
+			})
+
+			it("returns the base image", func() {
+				info, err := subject.InspectImage(context.TODO(), "some/image", useDaemon)
+				h.AssertNil(t, err)
+				h.AssertEq(t, info.Base, metadata.RunImageMetadata{
+					TopLayer: "some-top-layer",
+					SHA:      "some-run-image-digest",
+				})
+			})
+
+			it("returns the buildpacks and their layers", func() {
+				info, err := subject.InspectImage(context.TODO(), "some/image", useDaemon)
+				h.AssertNil(t, err)
+				h.AssertEq(t, len(info.Buildpacks), 2)
+				h.AssertEq(t, info.Buildpacks[0].ID, "test.bp.one")
+				h.AssertEq(t, info.Buildpacks[0].Version, "1.0.0")
+				h.AssertEq(t, info.Buildpacks[0].Layers["some-layer"].SHA, "some-layer-sha")
+				h.AssertEq(t, info.Buildpacks[0].Layers["some-layer"].Launch, true)
+				h.AssertEq(t, info.Buildpacks[1].ID, "test.bp.two")
+				h.AssertEq(t, info.Buildpacks[1].Version, "2.0.0")
+			})
+		})
+	}
+
+	when("the image is missing the lifecycle metadata label", func() {
+		var plainImage *fakes.Image
+
+		it.Before(func() {
+			plainImage = fakes.NewImage("some/plain-image", "", "")
+			fakeImageFetcher.LocalImages["some/plain-image"] = plainImage
+		})
+
+		it.After(func() {
+			plainImage.Cleanup()
+		})
+
+		it("returns an error", func() {
+			_, err := subject.InspectImage(context.TODO(), "some/plain-image", true)
+			h.AssertError(t, err, "image 'some/plain-image' missing label 'io.buildpacks.lifecycle.metadata'")
+		})
+	})
+
+	when("the image does not exist", func() {
+		it("returns nil info", func() {
+			info, err := subject.InspectImage(context.TODO(), "not/some-image", true)
+			h.AssertNil(t, err)
+			h.AssertNil(t, info)
+		})
+	})
+}
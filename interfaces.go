@@ -3,19 +3,22 @@ package pack
 import (
 	"context"
 
-	"github.com/buildpack/pack/blob"
-
 	"github.com/buildpack/imgutil"
+
+	"github.com/buildpack/pack/blob"
+	"github.com/buildpack/pack/image"
 )
 
 //go:generate mockgen -package testmocks -destination testmocks/mock_image_fetcher.go github.com/buildpack/pack ImageFetcher
 
 type ImageFetcher interface {
-	Fetch(ctx context.Context, name string, daemon, pull bool) (imgutil.Image, error)
+	Fetch(ctx context.Context, name string, daemon bool, pullPolicy image.PullPolicy) (imgutil.Image, error)
+	Layers(ctx context.Context, name string, daemon bool) ([]string, error) // diff IDs, bottom to top
+	ID(ctx context.Context, name string, daemon bool) (string, error)       // digest of the image config
 }
 
 //go:generate mockgen -package testmocks -destination testmocks/mock_downloader.go github.com/buildpack/pack Downloader
 
 type Downloader interface {
-	Download(pathOrUri string) (blob.Blob, error)
+	Download(pathOrUri string, pullPolicy image.PullPolicy) (blob.Blob, error) // pullPolicy is for the images of docker:// URIs
 }
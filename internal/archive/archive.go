@@ -9,6 +9,7 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/docker/docker/pkg/ioutils"
@@ -21,15 +22,19 @@ func init() {
 	NormalizedDateTime = time.Date(1980, time.January, 1, 0, 0, 1, 0, time.UTC)
 }
 
-func ReadDirAsTar(srcDir, basePath string, uid, gid int, mode int64) io.ReadCloser {
-	return readAsTar(srcDir, basePath, uid, gid, mode, WriteDirToTar)
+// ExcludeFunc reports whether the entry at the given slash-separated path, relative to the source, should be left out
+// of the archive. A nil ExcludeFunc includes every entry.
+type ExcludeFunc func(path string, isDir bool) bool
+
+func ReadDirAsTar(srcDir, basePath string, uid, gid int, mode int64, exclude ExcludeFunc) io.ReadCloser {
+	return readAsTar(srcDir, basePath, uid, gid, mode, exclude, WriteDirToTar)
 }
 
-func ReadZipAsTar(srcPath, basePath string, uid, gid int, mode int64) io.ReadCloser {
-	return readAsTar(srcPath, basePath, uid, gid, mode, WriteZipToTar)
+func ReadZipAsTar(srcPath, basePath string, uid, gid int, mode int64, exclude ExcludeFunc) io.ReadCloser {
+	return readAsTar(srcPath, basePath, uid, gid, mode, exclude, WriteZipToTar)
 }
 
-func readAsTar(src, basePath string, uid, gid int, mode int64, writeFn func(tw *tar.Writer, srcDir, basePath string, uid, gid int, mode int64) error) io.ReadCloser {
+func readAsTar(src, basePath string, uid, gid int, mode int64, exclude ExcludeFunc, writeFn func(tw *tar.Writer, srcDir, basePath string, uid, gid int, mode int64, exclude ExcludeFunc) error) io.ReadCloser {
 	var (
 		errChan = make(chan error)
 		r, w    = io.Pipe()
@@ -44,7 +49,7 @@ func readAsTar(src, basePath string, uid, gid int, mode int64, writeFn func(tw *
 			}
 		}()
 
-		err := writeFn(tw, src, basePath, uid, gid, mode)
+		err := writeFn(tw, src, basePath, uid, gid, mode, exclude)
 
 		closeErr := tw.Close()
 		closeErr = aggregateError(closeErr, w.CloseWithError(err))
@@ -156,7 +161,7 @@ func ReadTarEntry(rc io.Reader, entryPath string) (*tar.Header, []byte, error) {
 	return nil, nil, errors.Wrapf(ErrEntryNotExist, "could not find entry path '%s'", entryPath)
 }
 
-func WriteDirToTar(tw *tar.Writer, srcDir, basePath string, uid, gid int, mode int64) error {
+func WriteDirToTar(tw *tar.Writer, srcDir, basePath string, uid, gid int, mode int64, exclude ExcludeFunc) error {
 	return filepath.Walk(srcDir, func(file string, fi os.FileInfo, err error) error {
 		if err != nil {
 			return err
@@ -191,6 +196,13 @@ func WriteDirToTar(tw *tar.Writer, srcDir, basePath string, uid, gid int, mode i
 			return nil
 		}
 
+		if exclude != nil && exclude(filepath.ToSlash(relPath), fi.IsDir()) {
+			if fi.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
 		header.Name = filepath.ToSlash(filepath.Join(basePath, relPath))
 		finalizeHeader(header, uid, gid, mode)
 
@@ -214,7 +226,7 @@ func WriteDirToTar(tw *tar.Writer, srcDir, basePath string, uid, gid int, mode i
 	})
 }
 
-func WriteZipToTar(tw *tar.Writer, srcZip, basePath string, uid, gid int, mode int64) error {
+func WriteZipToTar(tw *tar.Writer, srcZip, basePath string, uid, gid int, mode int64, exclude ExcludeFunc) error {
 	zipReader, err := zip.OpenReader(srcZip)
 	if err != nil {
 		return err
@@ -222,6 +234,10 @@ func WriteZipToTar(tw *tar.Writer, srcZip, basePath string, uid, gid int, mode i
 	defer zipReader.Close()
 
 	for _, f := range zipReader.File {
+		if exclude != nil && isExcludedZipEntry(exclude, f.Name, f.FileInfo().IsDir()) {
+			continue
+		}
+
 		var header *tar.Header
 		if f.Mode()&os.ModeSymlink != 0 {
 			target, err := func() (string, error) {
@@ -283,6 +299,17 @@ func WriteZipToTar(tw *tar.Writer, srcZip, basePath string, uid, gid int, mode i
 	return nil
 }
 
+// zip archives need not contain entries for parent directories, so each parent is checked explicitly
+func isExcludedZipEntry(exclude ExcludeFunc, name string, isDir bool) bool {
+	parts := strings.Split(strings.TrimSuffix(name, "/"), "/")
+	for i := 1; i < len(parts); i++ {
+		if exclude(strings.Join(parts[:i], "/"), true) {
+			return true
+		}
+	}
+	return exclude(strings.Join(parts, "/"), isDir)
+}
+
 func finalizeHeader(header *tar.Header, uid, gid int, mode int64) {
 	if mode != -1 {
 		header.Mode = mode
@@ -12,6 +12,15 @@ import (
 	"github.com/pkg/errors"
 )
 
+// ExitError is returned when a container exits with a non-zero status code
+type ExitError struct {
+	StatusCode int
+}
+
+func (e *ExitError) Error() string {
+	return fmt.Sprintf("failed with status code: %d", e.StatusCode)
+}
+
 func Run(ctx context.Context, docker *client.Client, ctrID string, out, errOut io.Writer) error {
 	bodyChan, errChan := docker.ContainerWait(ctx, ctrID, dcontainer.WaitConditionNextExit)
 
@@ -36,7 +45,7 @@ func Run(ctx context.Context, docker *client.Client, ctrID string, out, errOut i
 	select {
 	case body := <-bodyChan:
 		if body.StatusCode != 0 {
-			return fmt.Errorf("failed with status code: %d", body.StatusCode)
+			return &ExitError{StatusCode: int(body.StatusCode)}
 		}
 	case err := <-errChan:
 		return err
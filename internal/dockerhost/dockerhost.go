@@ -0,0 +1,60 @@
+// Package dockerhost works out how pack and the lifecycle containers reach the docker daemon given by DOCKER_HOST.
+package dockerhost
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/docker/docker/client"
+	"github.com/pkg/errors"
+
+	"github.com/buildpack/pack/style"
+)
+
+const (
+	EnvHost = "DOCKER_HOST"
+
+	// DefaultSocket is where the daemon listens unless configured otherwise, and where containers expect its socket
+	DefaultSocket = "/var/run/docker.sock"
+
+	apiVersion = "1.38"
+)
+
+// ErrNoDaemonSocket is returned by DaemonSocket when containers cannot be given access to the daemon's socket
+var ErrNoDaemonSocket = errors.New("no daemon socket to share with containers")
+
+// NewClient creates a docker client for the host, which defaults to the local daemon when empty. The docker client
+// has no transport for ssh:// hosts, so those are rejected with a hint to forward the daemon's socket instead.
+func NewClient(host string) (*client.Client, error) {
+	if strings.HasPrefix(host, "ssh://") {
+		return nil, fmt.Errorf(
+			"%s %s is not supported, forward the daemon's socket with %s and set %s to the forwarded socket instead",
+			EnvHost, style.Symbol(host), style.Symbol("ssh -L"), EnvHost,
+		)
+	}
+	return client.NewClientWithOpts(client.FromEnv, client.WithVersion(apiVersion))
+}
+
+// DaemonSocket returns the path of the daemon's socket, for binding into containers that need daemon access. Only
+// the sockets of local daemons can be shared, for remote daemons ErrNoDaemonSocket is returned as the path of their
+// socket on the remote machine is unknown, and the image has to be published to a registry instead.
+func DaemonSocket(host string) (string, error) {
+	if host == "" {
+		return DefaultSocket, nil
+	}
+
+	hostURL, err := client.ParseHostURL(host)
+	if err != nil {
+		return "", errors.Wrapf(err, "parsing %s", EnvHost)
+	}
+
+	switch hostURL.Scheme {
+	case "unix":
+		// e.g. the socket of a rootless daemon, such as $XDG_RUNTIME_DIR/docker.sock
+		return hostURL.Host, nil
+	case "tcp", "ssh":
+		return "", errors.Wrapf(ErrNoDaemonSocket, "daemon %s is remote", style.Symbol(host))
+	default:
+		return "", errors.Wrapf(ErrNoDaemonSocket, "unsupported %s %s", EnvHost, style.Symbol(host))
+	}
+}
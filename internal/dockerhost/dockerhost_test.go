@@ -0,0 +1,59 @@
+package dockerhost
+
+import (
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/pkg/errors"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	h "github.com/buildpack/pack/testhelpers"
+)
+
+func TestDockerHost(t *testing.T) {
+	color.NoColor = true
+	spec.Run(t, "dockerhost", testDockerHost, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testDockerHost(t *testing.T, when spec.G, it spec.S) {
+	when("#DaemonSocket", func() {
+		it("defaults to the default socket", func() {
+			socket, err := DaemonSocket("")
+			h.AssertNil(t, err)
+			h.AssertEq(t, socket, DefaultSocket)
+		})
+
+		it("uses the socket of a unix host", func() {
+			socket, err := DaemonSocket("unix:///run/user/1000/docker.sock")
+			h.AssertNil(t, err)
+			h.AssertEq(t, socket, "/run/user/1000/docker.sock")
+		})
+
+		it("fails for remote hosts, whose socket is not known", func() {
+			for _, host := range []string{"tcp://10.0.0.1:2376", "ssh://user@builder.example.com"} {
+				_, err := DaemonSocket(host)
+				h.AssertError(t, err, "is remote")
+				h.AssertEq(t, errors.Cause(err) == ErrNoDaemonSocket, true)
+			}
+		})
+
+		it("fails for hosts whose socket cannot be shared", func() {
+			_, err := DaemonSocket("npipe:////./pipe/docker_engine")
+			h.AssertError(t, err, "unsupported DOCKER_HOST 'npipe:////./pipe/docker_engine'")
+			h.AssertEq(t, errors.Cause(err) == ErrNoDaemonSocket, true)
+		})
+
+		it("fails for hosts that cannot be parsed", func() {
+			_, err := DaemonSocket("not-a-host")
+			h.AssertError(t, err, "parsing DOCKER_HOST")
+		})
+	})
+
+	when("#NewClient", func() {
+		it("fails for ssh hosts", func() {
+			_, err := NewClient("ssh://user@builder.example.com")
+			h.AssertError(t, err, "DOCKER_HOST 'ssh://user@builder.example.com' is not supported, forward the daemon's socket")
+		})
+	})
+}
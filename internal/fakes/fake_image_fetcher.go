@@ -2,6 +2,7 @@ package fakes
 
 import (
 	"context"
+	"sync"
 
 	"github.com/buildpack/imgutil"
 	"github.com/pkg/errors"
@@ -10,14 +11,18 @@ import (
 )
 
 type FetchArgs struct {
-	Daemon bool
-	Pull   bool
+	Daemon     bool
+	PullPolicy image.PullPolicy
 }
 
 type FakeImageFetcher struct {
 	LocalImages  map[string]imgutil.Image
 	RemoteImages map[string]imgutil.Image
 	FetchCalls   map[string]*FetchArgs
+	ImageLayers  map[string][]string // returned by Layers for both daemon and registry images
+	ImageIDs     map[string]string   // returned by ID for both daemon and registry images
+
+	mu sync.Mutex
 }
 
 func NewFakeImageFetcher() *FakeImageFetcher {
@@ -25,15 +30,22 @@ func NewFakeImageFetcher() *FakeImageFetcher {
 		LocalImages:  map[string]imgutil.Image{},
 		RemoteImages: map[string]imgutil.Image{},
 		FetchCalls:   map[string]*FetchArgs{},
+		ImageLayers:  map[string][]string{},
+		ImageIDs:     map[string]string{},
 	}
 }
 
-func (f *FakeImageFetcher) Fetch(ctx context.Context, name string, daemon, pull bool) (imgutil.Image, error) {
-	f.FetchCalls[name] = &FetchArgs{Daemon: daemon, Pull: pull}
+func (f *FakeImageFetcher) Fetch(ctx context.Context, name string, daemon bool, pullPolicy image.PullPolicy) (imgutil.Image, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.FetchCalls[name] = &FetchArgs{Daemon: daemon, PullPolicy: pullPolicy}
 
 	ri, remoteFound := f.RemoteImages[name]
 
 	if daemon {
+		_, localFound := f.LocalImages[name]
+		pull := pullPolicy == image.PullAlways || (pullPolicy == image.PullIfNotPresent && !localFound)
 		if remoteFound && pull {
 			f.LocalImages[name] = ri
 		}
@@ -50,3 +62,25 @@ func (f *FakeImageFetcher) Fetch(ctx context.Context, name string, daemon, pull
 
 	return ri, nil
 }
+
+func (f *FakeImageFetcher) Layers(ctx context.Context, name string, daemon bool) ([]string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	layers, ok := f.ImageLayers[name]
+	if !ok {
+		return nil, errors.Wrapf(image.ErrNotFound, "image '%s' does not exist", name)
+	}
+	return layers, nil
+}
+
+func (f *FakeImageFetcher) ID(ctx context.Context, name string, daemon bool) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	id, ok := f.ImageIDs[name]
+	if !ok {
+		return "", errors.Wrapf(image.ErrNotFound, "image '%s' does not exist", name)
+	}
+	return id, nil
+}
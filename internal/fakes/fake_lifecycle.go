@@ -2,15 +2,20 @@ package fakes
 
 import (
 	"context"
+	"time"
 
 	"github.com/buildpack/pack/build"
 )
 
 type FakeLifecycle struct {
-	Opts build.LifecycleOptions
+	Opts     build.LifecycleOptions
+	Deadline time.Time // the deadline of the context passed to Execute, zero if there was none
+	Result   build.Result
 }
 
-func (f *FakeLifecycle) Execute(ctx context.Context, opts build.LifecycleOptions) error {
+func (f *FakeLifecycle) Execute(ctx context.Context, opts build.LifecycleOptions) (*build.Result, error) {
 	f.Opts = opts
-	return nil
+	f.Deadline, _ = ctx.Deadline()
+	result := f.Result
+	return &result, nil
 }
@@ -0,0 +1,171 @@
+// Package ignore implements gitignore-style exclusion patterns.
+package ignore
+
+import (
+	"bufio"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// FileName is the name of the ignore file read from the root of an app
+const FileName = ".packignore"
+
+type Matcher struct {
+	patterns []pattern
+}
+
+type pattern struct {
+	regexp  *regexp.Regexp
+	negate  bool
+	dirOnly bool
+}
+
+// ReadFile reads the patterns declared in the ignore file at path. A missing file yields no patterns.
+func ReadFile(path string) ([]string, error) {
+	fh, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "open %s", path)
+	}
+	defer fh.Close()
+
+	var patterns []string
+	scanner := bufio.NewScanner(fh)
+	for scanner.Scan() {
+		patterns = append(patterns, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrapf(err, "read %s", path)
+	}
+	return patterns, nil
+}
+
+// NewMatcher compiles the given patterns. Blank lines and lines starting with '#' are skipped.
+func NewMatcher(patterns []string) (*Matcher, error) {
+	m := &Matcher{}
+	for _, p := range patterns {
+		compiled, ok, err := compile(p)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid pattern '%s'", p)
+		}
+		if ok {
+			m.patterns = append(m.patterns, compiled)
+		}
+	}
+	return m, nil
+}
+
+// Matches reports whether the slash-separated path, relative to the app root, is excluded.
+// A path is excluded when it, or any of its parent directories, is matched by the last applicable pattern.
+func (m *Matcher) Matches(relPath string, isDir bool) bool {
+	relPath = strings.Trim(path.Clean("/"+relPath), "/")
+	if relPath == "" {
+		return false
+	}
+
+	parts := strings.Split(relPath, "/")
+	for i := 1; i < len(parts); i++ {
+		if m.matches(strings.Join(parts[:i], "/"), true) {
+			return true
+		}
+	}
+	return m.matches(relPath, isDir)
+}
+
+func (m *Matcher) matches(relPath string, isDir bool) bool {
+	excluded := false
+	for _, p := range m.patterns {
+		if p.dirOnly && !isDir {
+			continue
+		}
+		if p.regexp.MatchString(relPath) {
+			excluded = !p.negate
+		}
+	}
+	return excluded
+}
+
+func compile(line string) (pattern, bool, error) {
+	line = strings.TrimRight(line, " \t\r")
+	if line == "" || strings.HasPrefix(line, "#") {
+		return pattern{}, false, nil
+	}
+
+	var p pattern
+	if strings.HasPrefix(line, "!") {
+		p.negate = true
+		line = line[1:]
+	} else if strings.HasPrefix(line, `\!`) || strings.HasPrefix(line, `\#`) {
+		line = line[1:]
+	}
+
+	if strings.HasSuffix(line, "/") {
+		p.dirOnly = true
+		line = strings.TrimRight(line, "/")
+	}
+
+	anchored := strings.Contains(line, "/")
+	line = strings.TrimPrefix(line, "/")
+	if line == "" {
+		return pattern{}, false, nil
+	}
+
+	expr := globToRegexp(line)
+	if anchored {
+		expr = "^" + expr + "$"
+	} else {
+		expr = "^(.*/)?" + expr + "$"
+	}
+
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return pattern{}, false, err
+	}
+	p.regexp = re
+	return p, true, nil
+}
+
+func globToRegexp(glob string) string {
+	var sb strings.Builder
+	for i := 0; i < len(glob); i++ {
+		c := glob[i]
+		switch {
+		case c == '*' && i+1 < len(glob) && glob[i+1] == '*':
+			i++
+			if i+1 < len(glob) && glob[i+1] == '/' {
+				i++
+				sb.WriteString("(.*/)?")
+			} else {
+				sb.WriteString(".*")
+			}
+		case c == '*':
+			sb.WriteString("[^/]*")
+		case c == '?':
+			sb.WriteString("[^/]")
+		case c == '[':
+			end := strings.IndexByte(glob[i+1:], ']')
+			if end < 0 {
+				sb.WriteString(`\[`)
+				continue
+			}
+			class := glob[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			sb.WriteString("[" + strings.Replace(class, `\`, `\\`, -1) + "]")
+			i += end + 1
+		case c == '\\' && i+1 < len(glob):
+			i++
+			sb.WriteString(regexp.QuoteMeta(string(glob[i])))
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,141 @@
+package ignore_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpack/pack/internal/ignore"
+	h "github.com/buildpack/pack/testhelpers"
+)
+
+func TestIgnore(t *testing.T) {
+	spec.Run(t, "Ignore", testIgnore, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testIgnore(t *testing.T, when spec.G, it spec.S) {
+	when("#ReadFile", func() {
+		var tmpDir string
+
+		it.Before(func() {
+			var err error
+			tmpDir, err = ioutil.TempDir("", "ignore-test")
+			h.AssertNil(t, err)
+		})
+
+		it.After(func() {
+			os.RemoveAll(tmpDir)
+		})
+
+		it("returns each line as a pattern", func() {
+			path := filepath.Join(tmpDir, ignore.FileName)
+			h.AssertNil(t, ioutil.WriteFile(path, []byte("node_modules\n\n*.log\n"), 0644))
+
+			patterns, err := ignore.ReadFile(path)
+			h.AssertNil(t, err)
+			h.AssertEq(t, patterns, []string{"node_modules", "", "*.log"})
+		})
+
+		it("returns no patterns when the file does not exist", func() {
+			patterns, err := ignore.ReadFile(filepath.Join(tmpDir, "missing"))
+			h.AssertNil(t, err)
+			h.AssertEq(t, len(patterns), 0)
+		})
+	})
+
+	when("#Matches", func() {
+		type testCase struct {
+			path     string
+			isDir    bool
+			excluded bool
+		}
+
+		for desc, tc := range map[string]struct {
+			patterns []string
+			cases    []testCase
+		}{
+			"basename patterns match at any depth": {
+				patterns: []string{"*.log"},
+				cases: []testCase{
+					{"debug.log", false, true},
+					{"logs/debug.log", false, true},
+					{"debug.log.txt", false, false},
+				},
+			},
+			"patterns containing a slash are anchored to the root": {
+				patterns: []string{"/build", "docs/*.md"},
+				cases: []testCase{
+					{"build", true, true},
+					{"src/build", true, false},
+					{"docs/readme.md", false, true},
+					{"docs/api/readme.md", false, false},
+				},
+			},
+			"trailing slash only matches directories": {
+				patterns: []string{"tmp/"},
+				cases: []testCase{
+					{"tmp", true, true},
+					{"tmp", false, false},
+					{"a/tmp", true, true},
+				},
+			},
+			"children of excluded directories are excluded": {
+				patterns: []string{"node_modules", "!node_modules/keep.js"},
+				cases: []testCase{
+					{"node_modules/lib/index.js", false, true},
+					{"node_modules/keep.js", false, true},
+				},
+			},
+			"negated patterns re-include files": {
+				patterns: []string{"*.secret", "!public.secret"},
+				cases: []testCase{
+					{"prod.secret", false, true},
+					{"keys/public.secret", false, false},
+				},
+			},
+			"double asterisks match across directories": {
+				patterns: []string{"**/cache", "out/**", "a/**/b"},
+				cases: []testCase{
+					{"cache", true, true},
+					{"x/y/cache", true, true},
+					{"out/bin/app", false, true},
+					{"out", true, false},
+					{"a/b", false, true},
+					{"a/x/y/b", false, true},
+				},
+			},
+			"single character and class wildcards": {
+				patterns: []string{"file?.txt", "[!a]*.tmp"},
+				cases: []testCase{
+					{"file1.txt", false, true},
+					{"file10.txt", false, false},
+					{"b.tmp", false, true},
+					{"a.tmp", false, false},
+				},
+			},
+			"comments and blank lines are skipped": {
+				patterns: []string{"# *.go", "", `\#hash`},
+				cases: []testCase{
+					{"main.go", false, false},
+					{"#hash", false, true},
+				},
+			},
+		} {
+			desc := desc
+			tc := tc
+			it(desc, func() {
+				matcher, err := ignore.NewMatcher(tc.patterns)
+				h.AssertNil(t, err)
+				for _, c := range tc.cases {
+					if matcher.Matches(c.path, c.isDir) != c.excluded {
+						t.Fatalf("expected excluded to be %t for path '%s' (dir: %t) with patterns %v", c.excluded, c.path, c.isDir, tc.patterns)
+					}
+				}
+			})
+		}
+	})
+}
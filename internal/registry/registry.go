@@ -0,0 +1,121 @@
+// Package registry applies the registry settings of the pack config to image references.
+package registry
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+
+	"github.com/buildpack/pack/config"
+	"github.com/buildpack/pack/style"
+)
+
+// Registries resolves image references against the configured registries. A nil *Registries has no settings, so
+// references resolve as they would without a config.
+type Registries struct {
+	byName map[string]config.Registry
+}
+
+func New(registries []config.Registry) *Registries {
+	r := &Registries{byName: map[string]config.Registry{}}
+	for _, registry := range registries {
+		r.byName[normalize(registry.Name)] = registry
+	}
+	return r
+}
+
+// normalize returns the name of a registry as go-containerregistry reports it, e.g. 'index.docker.io' for 'docker.io'
+func normalize(registryName string) string {
+	reg, err := name.NewRegistry(registryName, name.WeakValidation)
+	if err != nil {
+		return registryName
+	}
+	return reg.RegistryStr()
+}
+
+func (r *Registries) find(registryName string) (config.Registry, bool) {
+	if r == nil {
+		return config.Registry{}, false
+	}
+	registry, ok := r.byName[normalize(registryName)]
+	return registry, ok
+}
+
+// ParseReference parses ref with weak validation, marking its registry as insecure when it is configured so
+func (r *Registries) ParseReference(ref string) (name.Reference, error) {
+	parsed, err := name.ParseReference(ref, name.WeakValidation)
+	if err != nil {
+		return nil, err
+	}
+	if !r.Insecure(parsed.Context().RegistryStr()) {
+		return parsed, nil
+	}
+	return name.ParseReference(ref, name.WeakValidation, name.Insecure)
+}
+
+// Insecure reports whether the registry is configured to be used over plain HTTP
+func (r *Registries) Insecure(registryName string) bool {
+	registry, ok := r.find(registryName)
+	return ok && registry.Insecure
+}
+
+// RequiresPlainHTTP reports whether ref is on an insecure registry that registry clients would otherwise only try
+// over HTTPS. Registries on localhost or private networks are tried over plain HTTP without any settings.
+func (r *Registries) RequiresPlainHTTP(ref string) (bool, error) {
+	parsed, err := name.ParseReference(ref, name.WeakValidation)
+	if err != nil {
+		return false, err
+	}
+	return r.Insecure(parsed.Context().RegistryStr()) && parsed.Context().Registry.Scheme() != "http", nil
+}
+
+// Mirrors returns ref on each mirror of its registry, in the configured order
+func (r *Registries) Mirrors(ref string) ([]string, error) {
+	parsed, err := name.ParseReference(ref, name.WeakValidation)
+	if err != nil {
+		return nil, err
+	}
+	registry, ok := r.find(parsed.Context().RegistryStr())
+	if !ok {
+		return nil, nil
+	}
+
+	suffix := ":" + parsed.Identifier()
+	if _, ok := parsed.(name.Digest); ok {
+		suffix = "@" + parsed.Identifier()
+	}
+	var mirrors []string
+	for _, mirror := range registry.Mirrors {
+		mirrors = append(mirrors, strings.TrimSuffix(mirror, "/")+"/"+parsed.Context().RepositoryStr()+suffix)
+	}
+	return mirrors, nil
+}
+
+// Keychain returns the credentials configured for a registry, falling back to those of the docker config
+func (r *Registries) Keychain() authn.Keychain {
+	return &keychain{registries: r}
+}
+
+type keychain struct {
+	registries *Registries
+}
+
+func (k *keychain) Resolve(reg name.Registry) (authn.Authenticator, error) {
+	registry, ok := k.registries.find(reg.RegistryStr())
+	if !ok || registry.Username == "" {
+		return authn.DefaultKeychain.Resolve(reg)
+	}
+
+	password := ""
+	if registry.PasswordEnv != "" {
+		var set bool
+		if password, set = os.LookupEnv(registry.PasswordEnv); !set {
+			return nil, fmt.Errorf("resolving password for registry %s: environment variable %s is not set",
+				style.Symbol(registry.Name), style.Symbol(registry.PasswordEnv))
+		}
+	}
+	return &authn.Basic{Username: registry.Username, Password: password}, nil
+}
@@ -0,0 +1,121 @@
+package registry_test
+
+import (
+	"encoding/base64"
+	"os"
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpack/pack/config"
+	"github.com/buildpack/pack/internal/registry"
+	h "github.com/buildpack/pack/testhelpers"
+)
+
+func TestRegistry(t *testing.T) {
+	color.NoColor = true
+	spec.Run(t, "registry", testRegistry, spec.Sequential(), spec.Report(report.Terminal{}))
+}
+
+func testRegistry(t *testing.T, when spec.G, it spec.S) {
+	var subject *registry.Registries
+
+	it.Before(func() {
+		subject = registry.New([]config.Registry{
+			{Name: "registry.example.com:5000", Insecure: true},
+			{Name: "localhost:5000", Insecure: true},
+			{Name: "docker.io", Mirrors: []string{"mirror.example.com/hub/", "other-mirror.example.com"}},
+			{Name: "private.example.com", Username: "some-user", PasswordEnv: "PACK_TEST_REGISTRY_PASSWORD"},
+		})
+	})
+
+	when("#ParseReference", func() {
+		it("reaches insecure registries over plain HTTP", func() {
+			ref, err := subject.ParseReference("registry.example.com:5000/some/app")
+			h.AssertNil(t, err)
+			h.AssertEq(t, ref.Context().Registry.Scheme(), "http")
+		})
+
+		it("reaches other registries over HTTPS", func() {
+			ref, err := subject.ParseReference("example.com/some/app")
+			h.AssertNil(t, err)
+			h.AssertEq(t, ref.Context().Registry.Scheme(), "https")
+		})
+	})
+
+	when("#RequiresPlainHTTP", func() {
+		it("is true for insecure registries that would be reached over HTTPS", func() {
+			plainHTTP, err := subject.RequiresPlainHTTP("registry.example.com:5000/some/app")
+			h.AssertNil(t, err)
+			h.AssertEq(t, plainHTTP, true)
+		})
+
+		it("is false for insecure registries reached over plain HTTP anyway", func() {
+			plainHTTP, err := subject.RequiresPlainHTTP("localhost:5000/some/app")
+			h.AssertNil(t, err)
+			h.AssertEq(t, plainHTTP, false)
+		})
+
+		it("is false without registry settings", func() {
+			var none *registry.Registries
+			plainHTTP, err := none.RequiresPlainHTTP("registry.example.com:5000/some/app")
+			h.AssertNil(t, err)
+			h.AssertEq(t, plainHTTP, false)
+		})
+	})
+
+	when("#Mirrors", func() {
+		it("returns the reference on each mirror in order", func() {
+			mirrors, err := subject.Mirrors("some/run:some-tag")
+			h.AssertNil(t, err)
+			h.AssertEq(t, mirrors, []string{
+				"mirror.example.com/hub/some/run:some-tag",
+				"other-mirror.example.com/some/run:some-tag",
+			})
+		})
+
+		it("keeps the implicit namespace and digest of the reference", func() {
+			mirrors, err := subject.Mirrors("ubuntu@sha256:9ab5e0a2bcd2e1bd6c6ff56ba0e3af8bb5f67fd5e2a23b0fbd1e4b6e5a0b2a7a")
+			h.AssertNil(t, err)
+			h.AssertEq(t, mirrors[0], "mirror.example.com/hub/library/ubuntu@sha256:9ab5e0a2bcd2e1bd6c6ff56ba0e3af8bb5f67fd5e2a23b0fbd1e4b6e5a0b2a7a")
+		})
+
+		it("returns nothing for registries without mirrors", func() {
+			mirrors, err := subject.Mirrors("example.com/some/run")
+			h.AssertNil(t, err)
+			h.AssertEq(t, len(mirrors), 0)
+		})
+	})
+
+	when("#Keychain", func() {
+		var privateRegistry name.Registry
+
+		it.Before(func() {
+			var err error
+			privateRegistry, err = name.NewRegistry("private.example.com")
+			h.AssertNil(t, err)
+		})
+
+		it.After(func() {
+			h.AssertNil(t, os.Unsetenv("PACK_TEST_REGISTRY_PASSWORD"))
+		})
+
+		it("resolves the configured credentials", func() {
+			h.AssertNil(t, os.Setenv("PACK_TEST_REGISTRY_PASSWORD", "some-password"))
+
+			auth, err := subject.Keychain().Resolve(privateRegistry)
+			h.AssertNil(t, err)
+			header, err := auth.Authorization()
+			h.AssertNil(t, err)
+			h.AssertEq(t, header, "Basic "+base64.StdEncoding.EncodeToString([]byte("some-user:some-password")))
+		})
+
+		it("errors when the password variable is not set", func() {
+			_, err := subject.Keychain().Resolve(privateRegistry)
+			h.AssertError(t, err, "environment variable 'PACK_TEST_REGISTRY_PASSWORD' is not set")
+		})
+	})
+}
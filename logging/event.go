@@ -0,0 +1,82 @@
+package logging
+
+import (
+	"strings"
+	"time"
+)
+
+type EventType string
+
+const (
+	EventLog        EventType = "log"
+	EventWarning    EventType = "warning"
+	EventError      EventType = "error"
+	EventOutput     EventType = "output"
+	EventPhaseStart EventType = "phase-start"
+	EventPhaseEnd   EventType = "phase-end"
+	EventImagePull  EventType = "image-pull"
+	EventDownload   EventType = "download"
+	EventCache      EventType = "cache"
+	EventResult     EventType = "result"
+)
+
+// Event is a structured record of something that happened while pack was working.
+// Only the fields relevant to the event type are set.
+type Event struct {
+	Type     EventType   `json:"type"`
+	Time     time.Time   `json:"time"`
+	Level    string      `json:"level,omitempty"`
+	Message  string      `json:"message,omitempty"`
+	Phase    string      `json:"phase,omitempty"`
+	Stream   string      `json:"stream,omitempty"`
+	ExitCode *int        `json:"exitCode,omitempty"`
+	Image    string      `json:"image,omitempty"`
+	URI      string      `json:"uri,omitempty"`
+	Cache    string      `json:"cache,omitempty"`
+	Action   string      `json:"action,omitempty"`
+	Result   interface{} `json:"result,omitempty"`
+	Prefix   string      `json:"prefix,omitempty"` // set by prefix loggers, e.g. to the app of a build-many build
+}
+
+// WithEvents is an optional interface for loggers that want to receive structured events.
+type WithEvents interface {
+	Event(e Event)
+}
+
+// LogEvent passes the event to loggers that support events and is a no-op for all others.
+func LogEvent(l Logger, e Event) {
+	if el, ok := l.(WithEvents); ok {
+		el.Event(e)
+	}
+}
+
+// EventWriter turns each line written to it into an output event
+type EventWriter struct {
+	logger WithEvents
+	phase  string
+	stream string
+}
+
+// NewEventWriter creates a writer for the output of the given phase and stream
+func NewEventWriter(l WithEvents, phase, stream string) *EventWriter {
+	return &EventWriter{
+		logger: l,
+		phase:  phase,
+		stream: stream,
+	}
+}
+
+func (w *EventWriter) Write(buf []byte) (int, error) {
+	if len(buf) == 0 {
+		return 0, nil
+	}
+	for _, line := range strings.Split(strings.TrimRight(string(buf), "\n"), "\n") {
+		w.logger.Event(Event{
+			Type:    EventOutput,
+			Phase:   w.phase,
+			Stream:  w.stream,
+			Message: strings.TrimRight(line, "\r"),
+		})
+	}
+	return len(buf), nil
+}
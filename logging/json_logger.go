@@ -0,0 +1,79 @@
+package logging
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// NewJSONLogger creates a logger that writes each message and event to w as a line of JSON.
+func NewJSONLogger(w io.Writer) Logger {
+	return &jsonLogger{
+		out:   json.NewEncoder(w),
+		timer: time.Now,
+	}
+}
+
+type jsonLogger struct {
+	sync.Mutex
+	out   *json.Encoder
+	timer func() time.Time
+}
+
+func (l *jsonLogger) Event(e Event) {
+	l.Lock()
+	defer l.Unlock()
+
+	if e.Time.IsZero() {
+		e.Time = l.timer().UTC()
+	}
+	_ = l.out.Encode(e)
+}
+
+func (l *jsonLogger) Debug(msg string) {
+	l.Event(Event{Type: EventLog, Level: "debug", Message: msg})
+}
+
+func (l *jsonLogger) Debugf(format string, v ...interface{}) {
+	l.Debug(fmt.Sprintf(format, v...))
+}
+
+func (l *jsonLogger) Info(msg string) {
+	l.Event(Event{Type: EventLog, Level: "info", Message: msg})
+}
+
+func (l *jsonLogger) Infof(format string, v ...interface{}) {
+	l.Info(fmt.Sprintf(format, v...))
+}
+
+func (l *jsonLogger) Warn(msg string) {
+	l.Event(Event{Type: EventWarning, Message: msg})
+}
+
+func (l *jsonLogger) Warnf(format string, v ...interface{}) {
+	l.Warn(fmt.Sprintf(format, v...))
+}
+
+func (l *jsonLogger) Error(msg string) {
+	l.Event(Event{Type: EventError, Message: msg})
+}
+
+func (l *jsonLogger) Errorf(format string, v ...interface{}) {
+	l.Error(fmt.Sprintf(format, v...))
+}
+
+func (l *jsonLogger) Writer() io.Writer {
+	return NewEventWriter(l, "", "stdout")
+}
+
+// DebugWriter returns a writer that emits raw output as output events
+func (l *jsonLogger) DebugWriter() io.Writer {
+	return NewEventWriter(l, "", "stdout")
+}
+
+// DebugErrorWriter returns a writer that emits raw error output as output events
+func (l *jsonLogger) DebugErrorWriter() io.Writer {
+	return NewEventWriter(l, "", "stderr")
+}
@@ -0,0 +1,76 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sclevine/spec"
+
+	h "github.com/buildpack/pack/testhelpers"
+)
+
+func TestJSONLogger(t *testing.T) {
+	spec.Run(t, "JSONLogger", func(t *testing.T, when spec.G, it spec.S) {
+		var (
+			w      bytes.Buffer
+			logger *jsonLogger
+		)
+
+		it.Before(func() {
+			logger = NewJSONLogger(&w).(*jsonLogger)
+			logger.timer = func() time.Time {
+				return time.Date(2019, 5, 10, 12, 30, 0, 0, time.UTC)
+			}
+		})
+
+		it.After(func() {
+			w.Reset()
+		})
+
+		it("should write log messages as events with a level", func() {
+			logger.Debugf("some %s", "debug")
+			logger.Info("some info")
+			h.AssertEq(t, w.String(), `{"type":"log","time":"2019-05-10T12:30:00Z","level":"debug","message":"some debug"}
+{"type":"log","time":"2019-05-10T12:30:00Z","level":"info","message":"some info"}
+`)
+		})
+
+		it("should write warnings and errors as their own event types", func() {
+			logger.Warnf("some %s", "warning")
+			logger.Error("some error")
+			h.AssertEq(t, w.String(), `{"type":"warning","time":"2019-05-10T12:30:00Z","message":"some warning"}
+{"type":"error","time":"2019-05-10T12:30:00Z","message":"some error"}
+`)
+		})
+
+		it("should write events passed to LogEvent", func() {
+			exitCode := 0
+			LogEvent(logger, Event{Type: EventPhaseEnd, Phase: "detector", ExitCode: &exitCode})
+			h.AssertEq(t, w.String(), `{"type":"phase-end","time":"2019-05-10T12:30:00Z","phase":"detector","exitCode":0}
+`)
+		})
+
+		it("should write each line of raw output as an output event", func() {
+			_, err := logger.DebugWriter().Write([]byte("first line\nsecond line\n"))
+			h.AssertNil(t, err)
+
+			lines := strings.Split(strings.TrimSpace(w.String()), "\n")
+			h.AssertEq(t, len(lines), 2)
+			var e Event
+			h.AssertNil(t, json.Unmarshal([]byte(lines[1]), &e))
+			h.AssertEq(t, e.Type, EventOutput)
+			h.AssertEq(t, e.Stream, "stdout")
+			h.AssertEq(t, e.Message, "second line")
+		})
+
+		it("should tag phase output with the phase", func() {
+			_, err := NewEventWriter(logger, "builder", "stderr").Write([]byte("some output\n"))
+			h.AssertNil(t, err)
+			h.AssertEq(t, w.String(), `{"type":"output","time":"2019-05-10T12:30:00Z","message":"some output","phase":"builder","stream":"stderr"}
+`)
+		})
+	})
+}
@@ -0,0 +1,51 @@
+package logging
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	h "github.com/buildpack/pack/testhelpers"
+)
+
+func TestPrefixLogger(t *testing.T) {
+	color.NoColor = true
+	spec.Run(t, "PrefixLogger", testPrefixLogger, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testPrefixLogger(t *testing.T, when spec.G, it spec.S) {
+	var (
+		w      bytes.Buffer
+		logger Logger
+	)
+
+	it.Before(func() {
+		logger = NewPrefixLogger(New(&w), "some-app")
+	})
+
+	it("prefixes messages", func() {
+		logger.Infof("some %s", "message")
+		h.AssertMatch(t, w.String(), `INFO:   \[some-app\] some message\n$`)
+	})
+
+	it("prefixes writes to the writer", func() {
+		fmt.Fprint(logger.Writer(), "some output\n")
+		h.AssertContains(t, w.String(), "[some-app] some output")
+	})
+
+	when("the wrapped logger supports events", func() {
+		it.Before(func() {
+			logger = NewPrefixLogger(NewJSONLogger(&w), "some-app")
+		})
+
+		it("passes events on with the prefix", func() {
+			LogEvent(logger, Event{Type: EventCache, Cache: "some-cache", Action: "use"})
+			h.AssertContains(t, w.String(), `"type":"cache"`)
+			h.AssertContains(t, w.String(), `"cache":"some-cache","action":"use","prefix":"some-app"}`)
+		})
+	})
+}
@@ -0,0 +1,44 @@
+package logging
+
+import (
+	"io"
+	"sort"
+	"strings"
+)
+
+// Redacted replaces secret values in output
+const Redacted = "[REDACTED]"
+
+// RedactingWriter replaces secret values in each write with Redacted
+type RedactingWriter struct {
+	out      io.Writer
+	replacer *strings.Replacer
+}
+
+// NewRedactingWriter returns a writer that redacts the given values before writing to w. Values are matched within a
+// single write, longer values first so that a value containing another is redacted whole.
+func NewRedactingWriter(w io.Writer, values ...string) io.Writer {
+	var secrets []string
+	for _, v := range values {
+		if v != "" {
+			secrets = append(secrets, v)
+		}
+	}
+	if len(secrets) == 0 {
+		return w
+	}
+	sort.Slice(secrets, func(i, j int) bool { return len(secrets[i]) > len(secrets[j]) })
+
+	var pairs []string
+	for _, s := range secrets {
+		pairs = append(pairs, s, Redacted)
+	}
+	return &RedactingWriter{out: w, replacer: strings.NewReplacer(pairs...)}
+}
+
+func (w *RedactingWriter) Write(buf []byte) (int, error) {
+	if _, err := io.WriteString(w.out, w.replacer.Replace(string(buf))); err != nil {
+		return 0, err
+	}
+	return len(buf), nil
+}
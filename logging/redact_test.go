@@ -0,0 +1,40 @@
+package logging
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/sclevine/spec"
+
+	h "github.com/buildpack/pack/testhelpers"
+)
+
+func TestRedactingWriter(t *testing.T) {
+	spec.Run(t, "RedactingWriter", func(t *testing.T, when spec.G, it spec.S) {
+		var out bytes.Buffer
+
+		it.After(func() {
+			out.Reset()
+		})
+
+		it("redacts each value", func() {
+			w := NewRedactingWriter(&out, "s3cret", "t0ken")
+			n, err := w.Write([]byte("using s3cret and t0ken, s3cret again\n"))
+			h.AssertNil(t, err)
+			h.AssertEq(t, n, 37)
+			h.AssertEq(t, out.String(), "using [REDACTED] and [REDACTED], [REDACTED] again\n")
+		})
+
+		it("redacts a value containing another value whole", func() {
+			w := NewRedactingWriter(&out, "abc", "abcdef")
+			_, err := w.Write([]byte("abcdef"))
+			h.AssertNil(t, err)
+			h.AssertEq(t, out.String(), "[REDACTED]")
+		})
+
+		it("returns the writer when there is nothing to redact", func() {
+			w := NewRedactingWriter(&out, "")
+			h.AssertEq(t, w == &out, true)
+		})
+	})
+}
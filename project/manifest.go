@@ -0,0 +1,76 @@
+package project
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/BurntSushi/toml"
+	"github.com/pkg/errors"
+
+	"github.com/buildpack/pack/style"
+)
+
+// Manifest lists apps to be built together
+type Manifest struct {
+	Apps []App `toml:"apps"`
+}
+
+// App is an app in a Manifest, settings that are not set fall back to those of the app's project.toml
+type App struct {
+	Path       string   `toml:"path"` // relative to the manifest
+	Image      string   `toml:"image"`
+	Builder    string   `toml:"builder"`
+	RunImage   string   `toml:"run-image"`
+	Buildpacks []string `toml:"buildpacks"`
+	Env        []EnvVar `toml:"env"`
+}
+
+// ReadManifest reads the manifest at path, resolving the app paths against the manifest's directory
+func ReadManifest(path string) (Manifest, error) {
+	var manifest Manifest
+	if _, err := toml.DecodeFile(path, &manifest); err != nil {
+		return Manifest{}, errors.Wrapf(err, "failed to read %s", style.Symbol(path))
+	}
+
+	if len(manifest.Apps) == 0 {
+		return Manifest{}, fmt.Errorf("no apps in %s", style.Symbol(path))
+	}
+
+	manifestDir, err := filepath.Abs(filepath.Dir(path))
+	if err != nil {
+		return Manifest{}, err
+	}
+
+	images := map[string]bool{}
+	for i, app := range manifest.Apps {
+		if app.Path == "" {
+			return Manifest{}, fmt.Errorf("invalid app %d in %s: %s must be set", i+1, style.Symbol(path), style.Symbol("path"))
+		}
+		if app.Image != "" {
+			if images[app.Image] {
+				return Manifest{}, fmt.Errorf("invalid app %d in %s: image %s is used by another app", i+1, style.Symbol(path), style.Symbol(app.Image))
+			}
+			images[app.Image] = true
+		}
+		for _, env := range app.Env {
+			if env.Name == "" {
+				return Manifest{}, fmt.Errorf("invalid env of app %d in %s: %s must be set", i+1, style.Symbol(path), style.Symbol("name"))
+			}
+		}
+
+		if !filepath.IsAbs(app.Path) {
+			manifest.Apps[i].Path = filepath.Join(manifestDir, app.Path)
+		}
+	}
+
+	return manifest, nil
+}
+
+// EnvMap returns the app's env as a map, later entries win over earlier ones with the same name
+func (a App) EnvMap() map[string]string {
+	env := map[string]string{}
+	for _, e := range a.Env {
+		env[e.Name] = e.Value
+	}
+	return env
+}
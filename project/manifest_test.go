@@ -0,0 +1,119 @@
+package project_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpack/pack/project"
+	h "github.com/buildpack/pack/testhelpers"
+)
+
+func TestManifest(t *testing.T) {
+	color.NoColor = true
+	spec.Run(t, "manifest", testManifest, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testManifest(t *testing.T, when spec.G, it spec.S) {
+	var (
+		tmpDir       string
+		manifestPath string
+	)
+
+	it.Before(func() {
+		var err error
+		tmpDir, err = ioutil.TempDir("", "pack.manifest.test.")
+		h.AssertNil(t, err)
+		tmpDir, err = filepath.EvalSymlinks(tmpDir)
+		h.AssertNil(t, err)
+		manifestPath = filepath.Join(tmpDir, "apps.toml")
+	})
+
+	it.After(func() {
+		h.AssertNil(t, os.RemoveAll(tmpDir))
+	})
+
+	writeManifest := func(contents string) {
+		h.AssertNil(t, ioutil.WriteFile(manifestPath, []byte(contents), 0644))
+	}
+
+	when("#ReadManifest", func() {
+		it("reads the apps, resolving their paths against the manifest", func() {
+			writeManifest(`
+[[apps]]
+path = "some-app"
+image = "some/app"
+builder = "some/builder"
+run-image = "some/run"
+buildpacks = ["some.bp@1.2.3"]
+
+[[apps.env]]
+name = "SOME_KEY"
+value = "some-value"
+
+[[apps]]
+path = "/other-app"
+`)
+			manifest, err := project.ReadManifest(manifestPath)
+			h.AssertNil(t, err)
+			h.AssertEq(t, len(manifest.Apps), 2)
+
+			h.AssertEq(t, manifest.Apps[0].Path, filepath.Join(tmpDir, "some-app"))
+			h.AssertEq(t, manifest.Apps[0].Image, "some/app")
+			h.AssertEq(t, manifest.Apps[0].Builder, "some/builder")
+			h.AssertEq(t, manifest.Apps[0].RunImage, "some/run")
+			h.AssertEq(t, manifest.Apps[0].Buildpacks, []string{"some.bp@1.2.3"})
+			h.AssertEq(t, manifest.Apps[0].EnvMap(), map[string]string{"SOME_KEY": "some-value"})
+
+			h.AssertEq(t, manifest.Apps[1].Path, "/other-app")
+		})
+
+		when("the manifest does not exist", func() {
+			it("returns an error", func() {
+				_, err := project.ReadManifest(manifestPath)
+				h.AssertError(t, err, "failed to read")
+			})
+		})
+
+		when("there are no apps", func() {
+			it("returns an error", func() {
+				writeManifest("")
+				_, err := project.ReadManifest(manifestPath)
+				h.AssertError(t, err, "no apps in")
+			})
+		})
+
+		when("an app has no path", func() {
+			it("returns an error", func() {
+				writeManifest(`
+[[apps]]
+image = "some/app"
+`)
+				_, err := project.ReadManifest(manifestPath)
+				h.AssertError(t, err, "invalid app 1")
+				h.AssertError(t, err, "'path' must be set")
+			})
+		})
+
+		when("two apps have the same image", func() {
+			it("returns an error", func() {
+				writeManifest(`
+[[apps]]
+path = "some-app"
+image = "some/app"
+
+[[apps]]
+path = "other-app"
+image = "some/app"
+`)
+				_, err := project.ReadManifest(manifestPath)
+				h.AssertError(t, err, "image 'some/app' is used by another app")
+			})
+		})
+	})
+}
@@ -0,0 +1,83 @@
+package project
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/BurntSushi/toml"
+	"github.com/pkg/errors"
+
+	"github.com/buildpack/pack/style"
+)
+
+const FileName = "project.toml"
+
+// Descriptor is the optional project.toml at the root of an app, holding defaults for building it
+type Descriptor struct {
+	Project Project `toml:"project"`
+	Build   Build   `toml:"build"`
+}
+
+type Project struct {
+	Image string `toml:"image"`
+}
+
+type Build struct {
+	Builder    string      `toml:"builder"`
+	Buildpacks []Buildpack `toml:"buildpacks"`
+	Env        []EnvVar    `toml:"env"`
+	Include    []string    `toml:"include"` // gitignore-style patterns, when set only matching files are part of the app
+	Exclude    []string    `toml:"exclude"` // gitignore-style patterns
+}
+
+// Buildpack references a buildpack either by ID and optional version, or by URI
+type Buildpack struct {
+	ID      string `toml:"id"`
+	Version string `toml:"version"`
+	URI     string `toml:"uri"`
+}
+
+type EnvVar struct {
+	Name  string `toml:"name"`
+	Value string `toml:"value"`
+}
+
+// ReadDescriptor reads the descriptor from the app directory, returning an empty descriptor if there is none
+func ReadDescriptor(appDir string) (Descriptor, error) {
+	path := filepath.Join(appDir, FileName)
+
+	var descriptor Descriptor
+	if _, err := toml.DecodeFile(path, &descriptor); err != nil {
+		if os.IsNotExist(err) {
+			return Descriptor{}, nil
+		}
+		return Descriptor{}, errors.Wrapf(err, "failed to read %s", style.Symbol(path))
+	}
+
+	for _, bp := range descriptor.Build.Buildpacks {
+		if (bp.ID == "") == (bp.URI == "") {
+			return Descriptor{}, fmt.Errorf("invalid buildpack in %s: exactly one of %s or %s must be set", style.Symbol(path), style.Symbol("id"), style.Symbol("uri"))
+		}
+		if bp.URI != "" && bp.Version != "" {
+			return Descriptor{}, fmt.Errorf("invalid buildpack %s in %s: %s cannot be set with %s", style.Symbol(bp.URI), style.Symbol(path), style.Symbol("version"), style.Symbol("uri"))
+		}
+	}
+
+	for _, env := range descriptor.Build.Env {
+		if env.Name == "" {
+			return Descriptor{}, fmt.Errorf("invalid env in %s: %s must be set", style.Symbol(path), style.Symbol("name"))
+		}
+	}
+
+	return descriptor, nil
+}
+
+// EnvMap returns the build env as a map, later entries win over earlier ones with the same name
+func (b Build) EnvMap() map[string]string {
+	env := map[string]string{}
+	for _, e := range b.Env {
+		env[e.Name] = e.Value
+	}
+	return env
+}
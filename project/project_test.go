@@ -0,0 +1,127 @@
+package project_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpack/pack/project"
+	h "github.com/buildpack/pack/testhelpers"
+)
+
+func TestProject(t *testing.T) {
+	color.NoColor = true
+	spec.Run(t, "project", testProject, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testProject(t *testing.T, when spec.G, it spec.S) {
+	var appDir string
+
+	it.Before(func() {
+		var err error
+		appDir, err = ioutil.TempDir("", "pack.project.test.")
+		h.AssertNil(t, err)
+	})
+
+	it.After(func() {
+		h.AssertNil(t, os.RemoveAll(appDir))
+	})
+
+	writeDescriptor := func(contents string) {
+		h.AssertNil(t, ioutil.WriteFile(filepath.Join(appDir, project.FileName), []byte(contents), 0644))
+	}
+
+	when("#ReadDescriptor", func() {
+		when("there is no project.toml", func() {
+			it("returns an empty descriptor", func() {
+				descriptor, err := project.ReadDescriptor(appDir)
+				h.AssertNil(t, err)
+				h.AssertEq(t, descriptor, project.Descriptor{})
+			})
+		})
+
+		when("the project.toml is valid", func() {
+			it("reads the project and build settings", func() {
+				writeDescriptor(`
+[project]
+image = "some/app"
+
+[build]
+builder = "some/builder"
+include = ["src/"]
+exclude = ["*.log"]
+
+[[build.buildpacks]]
+id = "some.bp"
+version = "1.2.3"
+
+[[build.buildpacks]]
+uri = "some/path"
+
+[[build.env]]
+name = "SOME_KEY"
+value = "some-value"
+`)
+				descriptor, err := project.ReadDescriptor(appDir)
+				h.AssertNil(t, err)
+				h.AssertEq(t, descriptor.Project.Image, "some/app")
+				h.AssertEq(t, descriptor.Build.Builder, "some/builder")
+				h.AssertEq(t, descriptor.Build.Include, []string{"src/"})
+				h.AssertEq(t, descriptor.Build.Exclude, []string{"*.log"})
+				h.AssertEq(t, descriptor.Build.Buildpacks, []project.Buildpack{
+					{ID: "some.bp", Version: "1.2.3"},
+					{URI: "some/path"},
+				})
+				h.AssertEq(t, descriptor.Build.EnvMap(), map[string]string{"SOME_KEY": "some-value"})
+			})
+		})
+
+		when("the project.toml is not valid toml", func() {
+			it("returns an error", func() {
+				writeDescriptor("[project")
+				_, err := project.ReadDescriptor(appDir)
+				h.AssertError(t, err, "failed to read")
+			})
+		})
+
+		when("a buildpack sets both id and uri", func() {
+			it("returns an error", func() {
+				writeDescriptor(`
+[[build.buildpacks]]
+id = "some.bp"
+uri = "some/path"
+`)
+				_, err := project.ReadDescriptor(appDir)
+				h.AssertError(t, err, "exactly one of 'id' or 'uri' must be set")
+			})
+		})
+
+		when("a buildpack sets a version with a uri", func() {
+			it("returns an error", func() {
+				writeDescriptor(`
+[[build.buildpacks]]
+uri = "some/path"
+version = "1.2.3"
+`)
+				_, err := project.ReadDescriptor(appDir)
+				h.AssertError(t, err, "'version' cannot be set with 'uri'")
+			})
+		})
+
+		when("an env var has no name", func() {
+			it("returns an error", func() {
+				writeDescriptor(`
+[[build.env]]
+value = "some-value"
+`)
+				_, err := project.ReadDescriptor(appDir)
+				h.AssertError(t, err, "'name' must be set")
+			})
+		})
+	})
+}
@@ -0,0 +1,84 @@
+package pack
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+	"github.com/pkg/errors"
+
+	"github.com/buildpack/pack/style"
+)
+
+// requiresPushFromDaemon reports whether any of the tags is on a registry that the lifecycle cannot publish to,
+// because it is configured as insecure but the lifecycle would only reach it over HTTPS
+func (c *Client) requiresPushFromDaemon(tags []string) (bool, error) {
+	for _, tag := range tags {
+		plainHTTP, err := c.registries.RequiresPlainHTTP(tag)
+		if err != nil {
+			return false, err
+		}
+		if plainHTTP {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// pushFromDaemon pushes the image saved to the daemon under the first tag to the registry of each tag, reaching
+// insecure registries over plain HTTP. It returns the digest of the pushed image.
+func (c *Client) pushFromDaemon(ctx context.Context, tags []string) (string, error) {
+	tmpDir, err := ioutil.TempDir("", "pack.push.")
+	if err != nil {
+		return "", errors.Wrap(err, "creating temp dir")
+	}
+	defer os.RemoveAll(tmpDir)
+
+	tarPath := filepath.Join(tmpDir, "image.tar")
+	if err := c.saveImage(ctx, tags[0], tarPath); err != nil {
+		return "", errors.Wrapf(err, "saving image %s", style.Symbol(tags[0]))
+	}
+	img, err := tarball.ImageFromPath(tarPath, nil)
+	if err != nil {
+		return "", errors.Wrapf(err, "reading image %s", style.Symbol(tags[0]))
+	}
+
+	for _, tag := range tags {
+		ref, err := c.registries.ParseReference(tag)
+		if err != nil {
+			return "", err
+		}
+		c.logger.Infof("Pushing image %s", style.Symbol(tag))
+		if err := remote.Write(ref, img, remote.WithAuthFromKeychain(c.registries.Keychain()), remote.WithTransport(http.DefaultTransport)); err != nil {
+			return "", errors.Wrapf(err, "pushing image %s", style.Symbol(tag))
+		}
+	}
+
+	digest, err := img.Digest()
+	if err != nil {
+		return "", err
+	}
+	return digest.String(), nil
+}
+
+func (c *Client) saveImage(ctx context.Context, imageName, path string) error {
+	rc, err := c.docker.ImageSave(ctx, []string{imageName})
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = io.Copy(f, rc)
+	return err
+}
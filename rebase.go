@@ -3,42 +3,180 @@ package pack
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 
+	"github.com/buildpack/imgutil"
 	"github.com/buildpack/lifecycle/metadata"
+	"github.com/google/go-containerregistry/pkg/name"
 	"github.com/pkg/errors"
 
 	"github.com/buildpack/pack/builder"
-
+	"github.com/buildpack/pack/image"
 	"github.com/buildpack/pack/style"
 )
 
 type RebaseOptions struct {
 	RepoName          string
 	Publish           bool
-	SkipPull          bool
+	PullPolicy        image.PullPolicy // for the app and run images when not publishing, defaults to image.PullAlways
 	RunImage          string
 	AdditionalMirrors map[string][]string
+	DryRun            bool // report the change without rebasing
+	Rollback          bool // rebase on the run image the image had before its last rebase, instead of RunImage
 }
 
-func (c *Client) Rebase(ctx context.Context, opts RebaseOptions) error {
+// RebaseLabel records the run images of an image rebased by pack, so that the rebase can be rolled back
+const RebaseLabel = "io.buildpacks.pack.rebase"
+
+type RebaseMetadata struct {
+	RunImage string           `json:"runImage"` // the run image the image was rebased on
+	Previous PreviousRunImage `json:"previous"`
+}
+
+type PreviousRunImage struct {
+	Image    string `json:"image"` // name of the run image, its repository is used to fetch it by digest
+	SHA      string `json:"sha"`
+	TopLayer string `json:"topLayer"`
+}
+
+// RebaseResult describes the change of run image made by Rebase, or the change it would make on a dry run
+type RebaseResult struct {
+	Image                  string
+	ImageDigest            string // digest of the rebased image, empty on a dry run
+	RunImage               string
+	PreviousRunImageDigest string
+	PreviousTopLayer       string
+	RunImageDigest         string
+	TopLayer               string
+	Changed                bool     // false when the image is already on the run image
+	ReplacedLayers         []string // layers of the previous run image, bottom to top, only set on a dry run
+	NewLayers              []string // layers of the run image replacing them, only set on a dry run
+}
+
+func (c *Client) Rebase(ctx context.Context, opts RebaseOptions) (*RebaseResult, error) {
 	imageRef, err := c.parseTagReference(opts.RepoName)
 	if err != nil {
-		return errors.Wrapf(err, "invalid image name '%s'", opts.RepoName)
+		return nil, errors.Wrapf(err, "invalid image name '%s'", opts.RepoName)
 	}
 
-	appImage, err := c.imageFetcher.Fetch(ctx, opts.RepoName, !opts.Publish, !opts.SkipPull)
+	appImage, err := c.imageFetcher.Fetch(ctx, opts.RepoName, !opts.Publish, opts.PullPolicy)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	md, err := metadata.GetAppMetadata(appImage)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	rebaseMd, err := getRebaseMetadata(appImage)
+	if err != nil {
+		return nil, err
+	}
+
+	var baseImage imgutil.Image
+	if opts.Rollback {
+		baseImage, err = c.fetchPreviousRunImage(ctx, rebaseMd, opts)
+	} else {
+		baseImage, err = c.fetchRebaseRunImage(ctx, imageRef.Context().RegistryStr(), md, opts)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateRebaseStack(appImage, baseImage); err != nil {
+		return nil, err
+	}
+
+	result := &RebaseResult{
+		Image:                  appImage.Name(),
+		RunImage:               baseImage.Name(),
+		PreviousRunImageDigest: md.RunImage.SHA,
+		PreviousTopLayer:       md.RunImage.TopLayer,
+	}
+	if result.RunImageDigest, err = baseImage.Digest(); err != nil {
+		return nil, err
+	}
+	if result.TopLayer, err = baseImage.TopLayer(); err != nil {
+		return nil, err
+	}
+	result.Changed = result.TopLayer != result.PreviousTopLayer || result.RunImageDigest != result.PreviousRunImageDigest
+
+	if opts.DryRun {
+		if result.Changed {
+			if result.ReplacedLayers, result.NewLayers, err = c.rebaseLayers(ctx, result, !opts.Publish); err != nil {
+				return nil, err
+			}
+		}
+		return result, nil
+	}
+
+	c.logger.Infof("Rebasing %s on run image %s", style.Symbol(appImage.Name()), style.Symbol(baseImage.Name()))
+	if err := appImage.Rebase(md.RunImage.TopLayer, baseImage); err != nil {
+		return nil, err
+	}
+
+	previousRunImage := rebaseMd.RunImage
+	if previousRunImage == "" {
+		// the image has not been rebased by pack, so its run image is the one of its stack
+		previousRunImage = md.Stack.RunImage.Image
+	}
+	rebaseMd = RebaseMetadata{
+		RunImage: baseImage.Name(),
+		Previous: PreviousRunImage{
+			Image:    previousRunImage,
+			SHA:      md.RunImage.SHA,
+			TopLayer: md.RunImage.TopLayer,
+		},
+	}
+
+	md.RunImage.SHA = result.RunImageDigest
+	md.RunImage.TopLayer = result.TopLayer
+
+	if err := setJSONLabel(appImage, metadata.AppMetadataLabel, md); err != nil {
+		return nil, err
 	}
+	if err := setJSONLabel(appImage, RebaseLabel, rebaseMd); err != nil {
+		return nil, err
+	}
+
+	if result.ImageDigest, err = appImage.Save(); err != nil {
+		return nil, err
+	}
+	c.logger.Infof("New sha: %s", style.Symbol(result.ImageDigest))
+	return result, nil
+}
+
+// rebaseLayers returns the layers of the app image that belong to its current run image, and the layers of the new
+// run image that would replace them
+func (c *Client) rebaseLayers(ctx context.Context, result *RebaseResult, daemon bool) ([]string, []string, error) {
+	appLayers, err := c.imageFetcher.Layers(ctx, result.Image, daemon)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "reading layers of image %s", style.Symbol(result.Image))
+	}
+
+	top := -1
+	for i, layer := range appLayers {
+		if layer == result.PreviousTopLayer {
+			top = i
+			break
+		}
+	}
+	if top < 0 {
+		return nil, nil, fmt.Errorf("run image top layer %s not found in image %s", style.Symbol(result.PreviousTopLayer), style.Symbol(result.Image))
+	}
+
+	runLayers, err := c.imageFetcher.Layers(ctx, result.RunImage, daemon)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "reading layers of run image %s", style.Symbol(result.RunImage))
+	}
+	return appLayers[:top+1], runLayers, nil
+}
 
+func (c *Client) fetchRebaseRunImage(ctx context.Context, registry string, md metadata.AppImageMetadata, opts RebaseOptions) (imgutil.Image, error) {
 	runImageName := c.resolveRunImage(
 		opts.RunImage,
-		imageRef.Context().RegistryStr(),
+		registry,
 		builder.StackMetadata{
 			RunImage: builder.RunImageMetadata{
 				Image:   md.Stack.RunImage.Image,
@@ -48,38 +186,74 @@ func (c *Client) Rebase(ctx context.Context, opts RebaseOptions) error {
 		opts.AdditionalMirrors)
 
 	if runImageName == "" {
-		return errors.New("run image must be specified")
+		return nil, errors.New("run image must be specified")
 	}
 
-	baseImage, err := c.imageFetcher.Fetch(ctx, runImageName, !opts.Publish, !opts.SkipPull)
-	if err != nil {
-		return err
+	return c.imageFetcher.Fetch(ctx, runImageName, !opts.Publish, opts.PullPolicy)
+}
+
+// fetchPreviousRunImage fetches the run image recorded by the last rebase, by digest when it has one
+func (c *Client) fetchPreviousRunImage(ctx context.Context, rebaseMd RebaseMetadata, opts RebaseOptions) (imgutil.Image, error) {
+	previous := rebaseMd.Previous
+	if previous.Image == "" {
+		return nil, errors.New("image has no previous run image to roll back to, it has not been rebased by pack")
 	}
 
-	c.logger.Infof("Rebasing %s on run image %s", style.Symbol(appImage.Name()), style.Symbol(baseImage.Name()))
-	if err := appImage.Rebase(md.RunImage.TopLayer, baseImage); err != nil {
-		return err
+	runImageName := previous.Image
+	if previous.SHA != "" {
+		ref, err := name.ParseReference(previous.Image, name.WeakValidation)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid previous run image '%s'", previous.Image)
+		}
+		runImageName = ref.Context().Name() + "@" + previous.SHA
 	}
 
-	md.RunImage.SHA, err = baseImage.Digest()
+	runImage, err := c.imageFetcher.Fetch(ctx, runImageName, !opts.Publish, opts.PullPolicy)
 	if err != nil {
-		return err
+		return nil, errors.Wrapf(err, "fetching previous run image %s", style.Symbol(runImageName))
 	}
+	topLayer, err := runImage.TopLayer()
+	if err != nil {
+		return nil, err
+	}
+	if topLayer != previous.TopLayer {
+		return nil, fmt.Errorf("previous run image %s has changed, its top layer is %s instead of %s",
+			style.Symbol(runImageName), style.Symbol(topLayer), style.Symbol(previous.TopLayer))
+	}
+	return runImage, nil
+}
 
-	md.RunImage.TopLayer, err = baseImage.TopLayer()
+func validateRebaseStack(appImage, runImage imgutil.Image) error {
+	appStackID, err := appImage.Label("io.buildpacks.stack.id")
 	if err != nil {
 		return err
 	}
-
-	newLabel, err := json.Marshal(md)
-	if err := appImage.SetLabel(metadata.AppMetadataLabel, string(newLabel)); err != nil {
+	runStackID, err := runImage.Label("io.buildpacks.stack.id")
+	if err != nil {
 		return err
 	}
+	if runStackID != appStackID {
+		return fmt.Errorf("run-image stack id '%s' does not match app image stack '%s'", runStackID, appStackID)
+	}
+	return nil
+}
 
-	sha, err := appImage.Save()
+func getRebaseMetadata(img imgutil.Image) (RebaseMetadata, error) {
+	var rebaseMd RebaseMetadata
+	label, err := img.Label(RebaseLabel)
+	if err != nil || label == "" {
+		return rebaseMd, err
+	}
+	if err := json.Unmarshal([]byte(label), &rebaseMd); err != nil {
+		return rebaseMd, errors.Wrapf(err, "reading label %s", style.Symbol(RebaseLabel))
+	}
+	return rebaseMd, nil
+}
+
+func setJSONLabel(img imgutil.Image, label string, value interface{}) error {
+	b, err := json.Marshal(value)
 	if err != nil {
 		return err
 	}
-	c.logger.Infof("New sha: %s", style.Symbol(sha))
-	return nil
+	return img.SetLabel(label, string(b))
 }
@@ -0,0 +1,253 @@
+package pack
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/buildpack/lifecycle/metadata"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/pkg/errors"
+
+	"github.com/buildpack/pack/image"
+	"github.com/buildpack/pack/style"
+)
+
+type RebaseManyOptions struct {
+	UsingRunImage     string   // required, images whose metadata names this run image or a mirror of it, or whose run image it is, are rebased
+	Images            []string // registry images to consider, the images on the daemon are considered when empty
+	RunImage          string   // run image to rebase onto, defaults to UsingRunImage
+	PullPolicy        image.PullPolicy
+	AdditionalMirrors map[string][]string
+	DryRun            bool
+	Concurrency       int // maximum number of rebases run at once, defaults to 1
+}
+
+type ImageRebaseResult struct {
+	Image          string
+	AdditionalTags []string      // other tags of the image on the daemon, moved to the rebased image
+	PreviousDigest string        // the image ID for images on the daemon
+	Digest         string        // empty on a dry run or when the rebase failed
+	Result         *RebaseResult // nil if the rebase failed
+	Err            error
+}
+
+// RebaseMany rebases each image built on opts.UsingRunImage, at most opts.Concurrency at a time. The run image is
+// pulled once for all images. The results are ordered by image name, and the returned error is non-nil if any
+// rebase failed.
+func (c *Client) RebaseMany(ctx context.Context, opts RebaseManyOptions) ([]ImageRebaseResult, error) {
+	if opts.UsingRunImage == "" {
+		return nil, errors.New("run image to find images by must be specified")
+	}
+	usingRunImage, err := name.ParseReference(opts.UsingRunImage, name.WeakValidation)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid run image '%s'", opts.UsingRunImage)
+	}
+
+	publish := len(opts.Images) > 0
+	fetcher := newSharedImageFetcher(c.imageFetcher)
+	matcher := runImageMatcher{ref: usingRunImage}
+	if usingImage, err := fetcher.Fetch(ctx, opts.UsingRunImage, !publish, opts.PullPolicy); err != nil {
+		c.logger.Debugf("Finding images by the name of run image %s only, it cannot be read: %s", style.Symbol(opts.UsingRunImage), err)
+	} else if matcher.topLayer, err = usingImage.TopLayer(); err != nil {
+		return nil, errors.Wrapf(err, "reading top layer of run image %s", style.Symbol(opts.UsingRunImage))
+	}
+
+	var results []ImageRebaseResult
+	if publish {
+		results = c.findRegistryImages(ctx, opts.Images, matcher)
+	} else if results, err = c.findDaemonImages(ctx, matcher); err != nil {
+		return nil, err
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].Image < results[j].Image })
+
+	runImage := opts.RunImage
+	if runImage == "" {
+		runImage = opts.UsingRunImage
+	}
+
+	concurrency := opts.Concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	var (
+		sem = make(chan struct{}, concurrency)
+		wg  sync.WaitGroup
+	)
+	for i := range results {
+		if results[i].Err != nil {
+			continue
+		}
+
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			results[i].Err = ctx.Err()
+			continue
+		}
+
+		wg.Add(1)
+		go func(result *ImageRebaseResult) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			imageClient := c.forApp(result.Image, fetcher, c.downloader)
+			result.Result, result.Err = imageClient.Rebase(ctx, RebaseOptions{
+				RepoName:          result.Image,
+				Publish:           publish,
+				PullPolicy:        opts.PullPolicy,
+				RunImage:          runImage,
+				AdditionalMirrors: opts.AdditionalMirrors,
+				DryRun:            opts.DryRun,
+			})
+			if result.Result != nil && result.Result.ImageDigest != "" {
+				result.Digest = result.Result.ImageDigest
+				if !publish {
+					result.Digest = imageID(result.Digest)
+				}
+				for _, tag := range result.AdditionalTags {
+					if err := c.docker.ImageTag(ctx, result.Image, tag); err != nil {
+						result.Err = errors.Wrapf(err, "tagging rebased image as %s", style.Symbol(tag))
+						break
+					}
+				}
+			}
+		}(&results[i])
+	}
+	wg.Wait()
+
+	failed := 0
+	for _, result := range results {
+		if result.Err != nil {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return results, fmt.Errorf("%d of %d rebases failed", failed, len(results))
+	}
+	return results, nil
+}
+
+// findDaemonImages returns the app images on the daemon that use the run image
+func (c *Client) findDaemonImages(ctx context.Context, matcher runImageMatcher) ([]ImageRebaseResult, error) {
+	summaries, err := c.docker.ImageList(ctx, types.ImageListOptions{
+		Filters: filters.NewArgs(filters.Arg("label", metadata.AppMetadataLabel)),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "listing images on the daemon")
+	}
+	return c.daemonImageResults(summaries, matcher), nil
+}
+
+// daemonImageResults returns a result for each image that uses the run image, named by its first tag, so that an
+// image with several tags is rebased once
+func (c *Client) daemonImageResults(summaries []types.ImageSummary, matcher runImageMatcher) []ImageRebaseResult {
+	var results []ImageRebaseResult
+	for _, summary := range summaries {
+		var md metadata.AppImageMetadata
+		if err := json.Unmarshal([]byte(summary.Labels[metadata.AppMetadataLabel]), &md); err != nil {
+			c.logger.Debugf("Skipping image %s with invalid metadata: %s", style.Symbol(summary.ID), err)
+			continue
+		}
+		var rebaseMd RebaseMetadata
+		if label := summary.Labels[RebaseLabel]; label != "" {
+			if err := json.Unmarshal([]byte(label), &rebaseMd); err != nil {
+				c.logger.Debugf("Skipping image %s with invalid label %s: %s", style.Symbol(summary.ID), style.Symbol(RebaseLabel), err)
+				continue
+			}
+		}
+		if !matcher.matches(md, rebaseMd) {
+			continue
+		}
+
+		var tags []string
+		for _, tag := range summary.RepoTags {
+			if tag != "<none>:<none>" {
+				tags = append(tags, tag)
+			}
+		}
+		if len(tags) == 0 {
+			continue
+		}
+		sort.Strings(tags)
+		results = append(results, ImageRebaseResult{Image: tags[0], AdditionalTags: tags[1:], PreviousDigest: imageID(summary.ID)})
+	}
+	return results
+}
+
+// findRegistryImages returns those of the images that use the run image, or that could not be read
+func (c *Client) findRegistryImages(ctx context.Context, images []string, matcher runImageMatcher) []ImageRebaseResult {
+	var results []ImageRebaseResult
+	for _, imageName := range images {
+		result := ImageRebaseResult{Image: imageName}
+
+		appImage, err := c.imageFetcher.Fetch(ctx, imageName, false, image.PullAlways)
+		if err != nil {
+			result.Err = err
+			results = append(results, result)
+			continue
+		}
+		md, err := metadata.GetAppMetadata(appImage)
+		if err != nil {
+			result.Err = err
+			results = append(results, result)
+			continue
+		}
+		rebaseMd, err := getRebaseMetadata(appImage)
+		if err != nil {
+			result.Err = err
+			results = append(results, result)
+			continue
+		}
+		if !matcher.matches(md, rebaseMd) {
+			c.logger.Debugf("Skipping image %s, it does not use run image %s", style.Symbol(imageName), style.Symbol(matcher.ref.Name()))
+			continue
+		}
+		if result.PreviousDigest, err = appImage.Digest(); err != nil {
+			result.Err = err
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// runImageMatcher finds the app images that use a run image
+type runImageMatcher struct {
+	ref      name.Reference
+	topLayer string // top layer of the run image, empty when it could not be read
+}
+
+// matches reports whether the app image is on the run image, going by the run image layer recorded in its metadata,
+// the run image of its last rebase by pack or, if it has not been rebased, the run image of its stack and its mirrors
+func (m runImageMatcher) matches(md metadata.AppImageMetadata, rebaseMd RebaseMetadata) bool {
+	if m.topLayer != "" && md.RunImage.TopLayer == m.topLayer {
+		return true
+	}
+
+	refs := append([]string{md.Stack.RunImage.Image}, md.Stack.RunImage.Mirrors...)
+	if rebaseMd.RunImage != "" {
+		refs = []string{rebaseMd.RunImage}
+	}
+	for _, ref := range refs {
+		parsed, err := name.ParseReference(ref, name.WeakValidation)
+		if err == nil && parsed.Name() == m.ref.Name() {
+			return true
+		}
+	}
+	return false
+}
+
+func imageID(id string) string {
+	if strings.HasPrefix(id, "sha256:") {
+		return id
+	}
+	return "sha256:" + id
+}
@@ -0,0 +1,170 @@
+package pack
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/buildpack/imgutil/fakes"
+	"github.com/docker/docker/api/types"
+	"github.com/fatih/color"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	ifakes "github.com/buildpack/pack/internal/fakes"
+	h "github.com/buildpack/pack/testhelpers"
+)
+
+func TestRebaseMany(t *testing.T) {
+	color.NoColor = true
+	spec.Run(t, "rebase_many", testRebaseMany, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testRebaseMany(t *testing.T, when spec.G, it spec.S) {
+	when("#RebaseMany", func() {
+		var (
+			fakeImageFetcher *ifakes.FakeImageFetcher
+			subject          *Client
+			images           []*fakes.Image
+			out              bytes.Buffer
+		)
+
+		newAppImage := func(name, digest, runImageMetadata string) *fakes.Image {
+			img := fakes.NewImage(name, "", digest)
+			h.AssertNil(t, img.SetLabel("io.buildpacks.lifecycle.metadata", `{"runImage":{"topLayer":"old-top-layer-sha"},"stack":{"runImage":`+runImageMetadata+`}}`))
+			fakeImageFetcher.RemoteImages[name] = img
+			images = append(images, img)
+			return img
+		}
+
+		it.Before(func() {
+			fakeImageFetcher = ifakes.NewFakeImageFetcher()
+			images = nil
+
+			runImage := fakes.NewImage("some/run", "run-image-top-layer-sha", "run-image-digest")
+			fakeImageFetcher.RemoteImages["some/run"] = runImage
+			images = append(images, runImage)
+
+			newAppImage("some/app", "some-app-digest", `{"image":"some/run"}`)
+			newAppImage("example.com/mirrored/app", "mirrored-app-digest", `{"image":"other/run","mirrors":["index.docker.io/some/run:latest"]}`)
+			newAppImage("other/app", "other-app-digest", `{"image":"other/run"}`)
+
+			subject = &Client{
+				logger:       ifakes.NewFakeLogger(&out),
+				imageFetcher: fakeImageFetcher,
+			}
+		})
+
+		it.After(func() {
+			for _, img := range images {
+				img.Cleanup()
+			}
+		})
+
+		when("registry images are given", func() {
+			it("rebases those that use the run image and summarizes the digests", func() {
+				results, err := subject.RebaseMany(context.TODO(), RebaseManyOptions{
+					UsingRunImage: "some/run",
+					Images:        []string{"some/app", "other/app", "example.com/mirrored/app"},
+					Concurrency:   2,
+				})
+				h.AssertNil(t, err)
+
+				h.AssertEq(t, len(results), 2)
+				h.AssertEq(t, results[0].Image, "example.com/mirrored/app")
+				h.AssertEq(t, results[0].PreviousDigest, "mirrored-app-digest")
+				h.AssertEq(t, results[0].Digest, "saved-digest-from-fake-run-image")
+				h.AssertEq(t, results[1].Image, "some/app")
+				h.AssertEq(t, results[1].PreviousDigest, "some-app-digest")
+				h.AssertEq(t, results[1].Digest, "saved-digest-from-fake-run-image")
+				h.AssertEq(t, results[1].Result.TopLayer, "run-image-top-layer-sha")
+
+				h.AssertEq(t, fakeImageFetcher.FetchCalls["some/app"].Daemon, false)
+			})
+
+			it("does not save on a dry run", func() {
+				fakeImageFetcher.ImageLayers["some/app"] = []string{"old-top-layer-sha", "app-layer-sha"}
+				fakeImageFetcher.ImageLayers["some/run"] = []string{"run-image-top-layer-sha"}
+
+				results, err := subject.RebaseMany(context.TODO(), RebaseManyOptions{
+					UsingRunImage: "some/run",
+					Images:        []string{"some/app"},
+					DryRun:        true,
+				})
+				h.AssertNil(t, err)
+				h.AssertEq(t, len(results), 1)
+				h.AssertEq(t, results[0].Digest, "")
+				h.AssertEq(t, results[0].Result.Changed, true)
+			})
+
+			it("rebases the images whose recorded run image is the run image", func() {
+				builtOnRunImage := newAppImage("built/app", "built-app-digest", `{"image":"other/run"}`)
+				h.AssertNil(t, builtOnRunImage.SetLabel("io.buildpacks.lifecycle.metadata", `{"runImage":{"topLayer":"run-image-top-layer-sha"},"stack":{"runImage":{"image":"other/run"}}}`))
+				rebasedOnto := newAppImage("rebased-onto/app", "rebased-onto-app-digest", `{"image":"other/run"}`)
+				h.AssertNil(t, rebasedOnto.SetLabel(RebaseLabel, `{"runImage":"some/run"}`))
+				rebasedAway := newAppImage("rebased-away/app", "rebased-away-app-digest", `{"image":"some/run"}`)
+				h.AssertNil(t, rebasedAway.SetLabel(RebaseLabel, `{"runImage":"other/run"}`))
+
+				results, err := subject.RebaseMany(context.TODO(), RebaseManyOptions{
+					UsingRunImage: "some/run",
+					Images:        []string{"built/app", "rebased-onto/app", "rebased-away/app"},
+				})
+				h.AssertNil(t, err)
+
+				h.AssertEq(t, len(results), 2)
+				h.AssertEq(t, results[0].Image, "built/app")
+				h.AssertEq(t, results[1].Image, "rebased-onto/app")
+			})
+
+			it("reports the images that cannot be read as failed", func() {
+				results, err := subject.RebaseMany(context.TODO(), RebaseManyOptions{
+					UsingRunImage: "some/run",
+					Images:        []string{"some/app", "missing/app"},
+				})
+				h.AssertError(t, err, "1 of 2 rebases failed")
+
+				h.AssertEq(t, len(results), 2)
+				h.AssertEq(t, results[0].Image, "missing/app")
+				h.AssertError(t, results[0].Err, "does not exist in registry")
+				h.AssertNil(t, results[1].Err)
+			})
+		})
+
+		when("images on the daemon are considered", func() {
+			it("returns each image that uses the run image once, named by its first tag", func() {
+				runImageRef, err := name.ParseReference("some/run", name.WeakValidation)
+				h.AssertNil(t, err)
+
+				results := subject.daemonImageResults([]types.ImageSummary{
+					{
+						ID:       "sha256:some-app-id",
+						RepoTags: []string{"some/app:v2", "some/app:latest", "<none>:<none>"},
+						Labels:   map[string]string{"io.buildpacks.lifecycle.metadata": `{"stack":{"runImage":{"image":"some/run"}}}`},
+					},
+					{
+						ID:       "sha256:other-app-id",
+						RepoTags: []string{"other/app:latest"},
+						Labels:   map[string]string{"io.buildpacks.lifecycle.metadata": `{"stack":{"runImage":{"image":"other/run"}}}`},
+					},
+					{
+						ID:       "sha256:untagged-app-id",
+						RepoTags: []string{"<none>:<none>"},
+						Labels:   map[string]string{"io.buildpacks.lifecycle.metadata": `{"stack":{"runImage":{"image":"some/run"}}}`},
+					},
+				}, runImageMatcher{ref: runImageRef})
+
+				h.AssertEq(t, results, []ImageRebaseResult{{
+					Image:          "some/app:latest",
+					AdditionalTags: []string{"some/app:v2"},
+					PreviousDigest: "sha256:some-app-id",
+				}})
+			})
+		})
+
+		it("errors without a run image", func() {
+			_, err := subject.RebaseMany(context.TODO(), RebaseManyOptions{Images: []string{"some/app"}})
+			h.AssertError(t, err, "run image to find images by must be specified")
+		})
+	})
+}
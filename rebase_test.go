@@ -10,6 +10,7 @@ import (
 	"github.com/sclevine/spec"
 	"github.com/sclevine/spec/report"
 
+	"github.com/buildpack/pack/image"
 	ifakes "github.com/buildpack/pack/internal/fakes"
 	h "github.com/buildpack/pack/testhelpers"
 )
@@ -70,10 +71,11 @@ func testRebase(t *testing.T, when spec.G, it spec.S) {
 					})
 
 					it("uses the run image provided by the user", func() {
-						h.AssertNil(t, subject.Rebase(context.TODO(), RebaseOptions{
+						_, err := subject.Rebase(context.TODO(), RebaseOptions{
 							RunImage: "custom/run",
 							RepoName: "some/app",
-						}))
+						})
+						h.AssertNil(t, err)
 						h.AssertEq(t, fakeAppImage.Base(), "custom/run")
 						lbl, _ := fakeAppImage.Label("io.buildpacks.lifecycle.metadata")
 						h.AssertContains(t, lbl, `"runImage":{"topLayer":"custom-base-top-layer-sha","sha":"custom-base-digest"`)
@@ -84,9 +86,10 @@ func testRebase(t *testing.T, when spec.G, it spec.S) {
 			when("run image is NOT provided by the user", func() {
 				when("the image has a label with a run image specified", func() {
 					it("uses the run image provided in the App image label", func() {
-						h.AssertNil(t, subject.Rebase(context.TODO(), RebaseOptions{
+						_, err := subject.Rebase(context.TODO(), RebaseOptions{
 							RepoName: "some/app",
-						}))
+						})
+						h.AssertNil(t, err)
 						h.AssertEq(t, fakeAppImage.Base(), "some/run")
 						lbl, _ := fakeAppImage.Label("io.buildpacks.lifecycle.metadata")
 						h.AssertContains(t, lbl, `"runImage":{"topLayer":"run-image-top-layer-sha","sha":"run-image-digest"`)
@@ -100,9 +103,10 @@ func testRebase(t *testing.T, when spec.G, it spec.S) {
 						})
 
 						it("chooses a matching mirror from the app image label", func() {
-							h.AssertNil(t, subject.Rebase(context.TODO(), RebaseOptions{
+							_, err := subject.Rebase(context.TODO(), RebaseOptions{
 								RepoName: "example.com/some/app",
-							}))
+							})
+							h.AssertNil(t, err)
 							h.AssertEq(t, fakeAppImage.Base(), "example.com/some/run")
 							lbl, _ := fakeAppImage.Label("io.buildpacks.lifecycle.metadata")
 							h.AssertContains(t, lbl, `"runImage":{"topLayer":"mirror-top-layer-sha","sha":"mirror-digest"`)
@@ -124,12 +128,13 @@ func testRebase(t *testing.T, when spec.G, it spec.S) {
 						})
 
 						it("chooses a matching local mirror first", func() {
-							h.AssertNil(t, subject.Rebase(context.TODO(), RebaseOptions{
+							_, err := subject.Rebase(context.TODO(), RebaseOptions{
 								RepoName: "example.com/some/app",
 								AdditionalMirrors: map[string][]string{
 									"some/run": {"example.com/some/local-run"},
 								},
-							}))
+							})
+							h.AssertNil(t, err)
 							h.AssertEq(t, fakeAppImage.Base(), "example.com/some/local-run")
 							lbl, _ := fakeAppImage.Label("io.buildpacks.lifecycle.metadata")
 							h.AssertContains(t, lbl, `"runImage":{"topLayer":"local-mirror-top-layer-sha","sha":"local-mirror-digest"`)
@@ -140,7 +145,7 @@ func testRebase(t *testing.T, when spec.G, it spec.S) {
 				when("the image does not have a label with a run image specified", func() {
 					it("returns an error", func() {
 						h.AssertNil(t, fakeAppImage.SetLabel("io.buildpacks.lifecycle.metadata", "{}"))
-						err := subject.Rebase(context.TODO(), RebaseOptions{
+						_, err := subject.Rebase(context.TODO(), RebaseOptions{
 							RepoName: "some/app",
 						})
 						h.AssertError(t, err, "run image must be specified")
@@ -148,6 +153,140 @@ func testRebase(t *testing.T, when spec.G, it spec.S) {
 				})
 			})
 
+			when("the run image is for another stack", func() {
+				it("returns an error", func() {
+					h.AssertNil(t, fakeAppImage.SetLabel("io.buildpacks.stack.id", "some.stack.id"))
+					h.AssertNil(t, fakeRunImage.SetLabel("io.buildpacks.stack.id", "other.stack.id"))
+
+					_, err := subject.Rebase(context.TODO(), RebaseOptions{
+						RepoName: "some/app",
+					})
+					h.AssertError(t, err, "run-image stack id 'other.stack.id' does not match app image stack 'some.stack.id'")
+					h.AssertEq(t, fakeAppImage.Base(), "")
+				})
+			})
+
+			when("previous run image", func() {
+				const previousDigest = "sha256:9ab5e0a2bcd2e1bd6c6ff56ba0e3af8bb5f67fd5e2a23b0fbd1e4b6e5a0b2a7a"
+
+				it.Before(func() {
+					h.AssertNil(t, fakeAppImage.SetLabel("io.buildpacks.lifecycle.metadata",
+						`{"runImage":{"topLayer":"old-top-layer-sha","sha":"`+previousDigest+`"},"stack":{"runImage":{"image":"some/run"}}}`))
+				})
+
+				it("is recorded in a label", func() {
+					_, err := subject.Rebase(context.TODO(), RebaseOptions{
+						RepoName: "some/app",
+					})
+					h.AssertNil(t, err)
+					lbl, _ := fakeAppImage.Label("io.buildpacks.pack.rebase")
+					h.AssertEq(t, lbl, `{"runImage":"some/run","previous":{"image":"some/run","sha":"`+previousDigest+`","topLayer":"old-top-layer-sha"}}`)
+				})
+
+				when("#Rollback", func() {
+					var fakePreviousRunImage *fakes.Image
+
+					it.Before(func() {
+						fakePreviousRunImage = fakes.NewImage("index.docker.io/some/run@"+previousDigest, "old-top-layer-sha", previousDigest)
+						fakeImageFetcher.LocalImages["index.docker.io/some/run@"+previousDigest] = fakePreviousRunImage
+
+						_, err := subject.Rebase(context.TODO(), RebaseOptions{
+							RepoName: "some/app",
+						})
+						h.AssertNil(t, err)
+					})
+
+					it.After(func() {
+						fakePreviousRunImage.Cleanup()
+					})
+
+					it("rebases on the previous run image by digest", func() {
+						_, err := subject.Rebase(context.TODO(), RebaseOptions{
+							RepoName: "some/app",
+							Rollback: true,
+						})
+						h.AssertNil(t, err)
+						h.AssertEq(t, fakeAppImage.Base(), "index.docker.io/some/run@"+previousDigest)
+						lbl, _ := fakeAppImage.Label("io.buildpacks.lifecycle.metadata")
+						h.AssertContains(t, lbl, `"runImage":{"topLayer":"old-top-layer-sha","sha":"`+previousDigest+`"`)
+						lbl, _ = fakeAppImage.Label("io.buildpacks.pack.rebase")
+						h.AssertEq(t, lbl, `{"runImage":"index.docker.io/some/run@`+previousDigest+`","previous":{"image":"some/run","sha":"run-image-digest","topLayer":"run-image-top-layer-sha"}}`)
+					})
+
+					it("returns an error when the previous run image has changed", func() {
+						fakeImageFetcher.LocalImages["index.docker.io/some/run@"+previousDigest] = fakeRunImage
+
+						_, err := subject.Rebase(context.TODO(), RebaseOptions{
+							RepoName: "some/app",
+							Rollback: true,
+						})
+						h.AssertError(t, err, "previous run image 'index.docker.io/some/run@"+previousDigest+"' has changed")
+					})
+				})
+
+				it("cannot be rolled back to when the image was not rebased by pack", func() {
+					_, err := subject.Rebase(context.TODO(), RebaseOptions{
+						RepoName: "some/app",
+						Rollback: true,
+					})
+					h.AssertError(t, err, "image has no previous run image to roll back to")
+				})
+			})
+
+			when("dry run", func() {
+				it.Before(func() {
+					h.AssertNil(t, fakeAppImage.SetLabel("io.buildpacks.lifecycle.metadata",
+						`{"runImage":{"topLayer":"old-top-layer-sha","sha":"old-digest"},"stack":{"runImage":{"image":"some/run"}}}`))
+					fakeImageFetcher.ImageLayers["some/app"] = []string{"old-base-layer-sha", "old-top-layer-sha", "app-layer-sha"}
+					fakeImageFetcher.ImageLayers["some/run"] = []string{"new-base-layer-sha", "run-image-top-layer-sha"}
+				})
+
+				it("reports the layers that would be replaced without rebasing", func() {
+					result, err := subject.Rebase(context.TODO(), RebaseOptions{
+						RepoName: "some/app",
+						DryRun:   true,
+					})
+					h.AssertNil(t, err)
+					h.AssertEq(t, result, &RebaseResult{
+						Image:                  "some/app",
+						RunImage:               "some/run",
+						PreviousRunImageDigest: "old-digest",
+						PreviousTopLayer:       "old-top-layer-sha",
+						RunImageDigest:         "run-image-digest",
+						TopLayer:               "run-image-top-layer-sha",
+						Changed:                true,
+						ReplacedLayers:         []string{"old-base-layer-sha", "old-top-layer-sha"},
+						NewLayers:              []string{"new-base-layer-sha", "run-image-top-layer-sha"},
+					})
+					h.AssertEq(t, fakeAppImage.Base(), "")
+					lbl, _ := fakeAppImage.Label("io.buildpacks.lifecycle.metadata")
+					h.AssertContains(t, lbl, `"runImage":{"topLayer":"old-top-layer-sha","sha":"old-digest"}`)
+				})
+
+				it("reports no change when the image is on the run image", func() {
+					h.AssertNil(t, fakeAppImage.SetLabel("io.buildpacks.lifecycle.metadata",
+						`{"runImage":{"topLayer":"run-image-top-layer-sha","sha":"run-image-digest"},"stack":{"runImage":{"image":"some/run"}}}`))
+
+					result, err := subject.Rebase(context.TODO(), RebaseOptions{
+						RepoName: "some/app",
+						DryRun:   true,
+					})
+					h.AssertNil(t, err)
+					h.AssertEq(t, result.Changed, false)
+					h.AssertEq(t, len(result.ReplacedLayers), 0)
+				})
+
+				it("errors when the top layer of the run image is not in the image", func() {
+					fakeImageFetcher.ImageLayers["some/app"] = []string{"other-layer-sha"}
+
+					_, err := subject.Rebase(context.TODO(), RebaseOptions{
+						RepoName: "some/app",
+						DryRun:   true,
+					})
+					h.AssertError(t, err, "run image top layer 'old-top-layer-sha' not found in image 'some/app'")
+				})
+			})
+
 			when("publish", func() {
 				var (
 					fakeRemoteRunImage *fakes.Image
@@ -163,24 +302,26 @@ func testRebase(t *testing.T, when spec.G, it spec.S) {
 				})
 
 				when("is false", func() {
-					when("skip pull is false", func() {
+					when("pull policy is always", func() {
 						it("updates the local image", func() {
-							h.AssertNil(t, subject.Rebase(context.TODO(), RebaseOptions{
-								RepoName: "some/app",
-								SkipPull: false,
-							}))
+							_, err := subject.Rebase(context.TODO(), RebaseOptions{
+								RepoName:   "some/app",
+								PullPolicy: image.PullAlways,
+							})
+							h.AssertNil(t, err)
 							h.AssertEq(t, fakeAppImage.Base(), "some/run")
 							lbl, _ := fakeAppImage.Label("io.buildpacks.lifecycle.metadata")
 							h.AssertContains(t, lbl, `"runImage":{"topLayer":"remote-top-layer-sha","sha":"remote-digest"`)
 						})
 					})
 
-					when("skip pull is true", func() {
+					when("pull policy is never", func() {
 						it("uses local image", func() {
-							h.AssertNil(t, subject.Rebase(context.TODO(), RebaseOptions{
-								RepoName: "some/app",
-								SkipPull: true,
-							}))
+							_, err := subject.Rebase(context.TODO(), RebaseOptions{
+								RepoName:   "some/app",
+								PullPolicy: image.PullNever,
+							})
+							h.AssertNil(t, err)
 							h.AssertEq(t, fakeAppImage.Base(), "some/run")
 							lbl, _ := fakeAppImage.Label("io.buildpacks.lifecycle.metadata")
 							h.AssertContains(t, lbl, `"runImage":{"topLayer":"run-image-top-layer-sha","sha":"run-image-digest"`)
@@ -195,10 +336,11 @@ func testRebase(t *testing.T, when spec.G, it spec.S) {
 
 					when("skip pull is anything", func() {
 						it("uses remote image", func() {
-							h.AssertNil(t, subject.Rebase(context.TODO(), RebaseOptions{
+							_, err := subject.Rebase(context.TODO(), RebaseOptions{
 								RepoName: "some/app",
 								Publish:  true,
-							}))
+							})
+							h.AssertNil(t, err)
 							h.AssertEq(t, fakeAppImage.Base(), "some/run")
 							lbl, _ := fakeAppImage.Label("io.buildpacks.lifecycle.metadata")
 							h.AssertContains(t, lbl, `"runImage":{"topLayer":"remote-top-layer-sha","sha":"remote-digest"`)
@@ -4,45 +4,79 @@ import (
 	"context"
 	"crypto/sha256"
 	"fmt"
+	"time"
 
 	"github.com/pkg/errors"
 
 	"github.com/buildpack/pack/app"
+	"github.com/buildpack/pack/image"
 	"github.com/buildpack/pack/style"
 )
 
 type RunOptions struct {
-	AppPath    string // defaults to current working directory
-	Builder    string // defaults to default builder on the client config
-	RunImage   string // defaults to the best mirror from the builder image
-	Env        map[string]string
-	NoPull     bool
-	ClearCache bool
-	Buildpacks []string
-	Ports      []string
+	AppPath        string // defaults to current working directory
+	Builder        string // defaults to the builder in the app's project.toml, then DefaultBuilder
+	DefaultBuilder string
+	RunImage       string // defaults to the best mirror from the builder image
+	Env            map[string]string
+	Secrets        map[string]string
+	PullPolicy     image.PullPolicy
+	ClearCache     bool
+	Buildpacks     []string
+	Exclude        []string
+	Ports          []string
+	Network        string
+	Volumes        []string // host mounts for the detect and build phases
+	Timeout        time.Duration
+	PhaseTimeouts  map[string]time.Duration
+	RuntimeEnv     map[string]string // env of the app container
+	RuntimeVolumes []string          // host mounts for the app container, in the form '<host path>:<target path>[:<mode>]'
+	ContainerName  string
+	Detach         bool   // start the app container and return its ID without waiting for it to exit
+	ProcessType    string // process type from the app's launch metadata, defaults to 'web'
 }
 
-func (c *Client) Run(ctx context.Context, opts RunOptions) error {
+// Run builds the app and runs it in a container, returning the ID of the container. Unless opts.Detach is set, Run
+// waits for the container to exit.
+func (c *Client) Run(ctx context.Context, opts RunOptions) (string, error) {
 	appPath, err := c.processAppPath(opts.AppPath)
 	if err != nil {
-		return errors.Wrapf(err, "invalid app dir '%s'", opts.AppPath)
+		return "", errors.Wrapf(err, "invalid app dir '%s'", opts.AppPath)
+	}
+	binds, err := processVolumes(opts.RuntimeVolumes)
+	if err != nil {
+		return "", errors.Wrap(err, "invalid runtime volume")
 	}
 	sum := sha256.Sum256([]byte(appPath))
 	imageName := fmt.Sprintf("pack.local/run/%x", sum[:8])
-	err = c.Build(ctx, BuildOptions{
-		AppPath:    appPath,
-		Builder:    opts.Builder,
-		RunImage:   opts.RunImage,
-		Env:        opts.Env,
-		Image:      imageName,
-		NoPull:     opts.NoPull,
-		ClearCache: opts.ClearCache,
-		Buildpacks: opts.Buildpacks,
+	_, err = c.Build(ctx, BuildOptions{
+		AppPath:        appPath,
+		Builder:        opts.Builder,
+		DefaultBuilder: opts.DefaultBuilder,
+		RunImage:       opts.RunImage,
+		Env:            opts.Env,
+		Secrets:        opts.Secrets,
+		Image:          imageName,
+		PullPolicy:     opts.PullPolicy,
+		ClearCache:     opts.ClearCache,
+		Buildpacks:     opts.Buildpacks,
+		Exclude:        opts.Exclude,
+		Network:        opts.Network,
+		Volumes:        opts.Volumes,
+		Timeout:        opts.Timeout,
+		PhaseTimeouts:  opts.PhaseTimeouts,
 	})
 	if err != nil {
-		return errors.Wrap(err, "build failed")
+		return "", errors.Wrap(err, "build failed")
 	}
 	appImage := &app.Image{RepoName: imageName, Logger: c.logger}
 	c.logger.Debug(style.Step("RUNNING"))
-	return appImage.Run(ctx, c.docker, opts.Ports)
+	return appImage.Run(ctx, c.docker, app.RunOptions{
+		Ports:       opts.Ports,
+		Env:         opts.RuntimeEnv,
+		Binds:       binds,
+		Name:        opts.ContainerName,
+		Detach:      opts.Detach,
+		ProcessType: opts.ProcessType,
+	})
 }
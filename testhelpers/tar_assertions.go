@@ -7,6 +7,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/pkg/errors"
 
@@ -103,3 +104,12 @@ func IsDirectory() TarEntryAssertion {
 		}
 	}
 }
+
+func HasModTime(expected time.Time) TarEntryAssertion {
+	return func(t *testing.T, header *tar.Header, _ []byte) {
+		t.Helper()
+		if !header.ModTime.Equal(expected) {
+			t.Fatalf("expected '%s' to have mod time '%s', but got '%s'", header.Name, expected, header.ModTime)
+		}
+	}
+}
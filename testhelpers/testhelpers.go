@@ -452,6 +452,7 @@ func writeTAR(t *testing.T, srcDir, tarDir string, mode int64, w io.Writer) {
 		srcDir,
 		tarDir,
 		0, 0, mode,
+		nil,
 	)
 	AssertNil(t, err)
 }
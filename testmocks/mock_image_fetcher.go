@@ -10,6 +10,8 @@ import (
 
 	imgutil "github.com/buildpack/imgutil"
 	gomock "github.com/golang/mock/gomock"
+
+	image "github.com/buildpack/pack/image"
 )
 
 // MockImageFetcher is a mock of ImageFetcher interface
@@ -36,7 +38,7 @@ func (m *MockImageFetcher) EXPECT() *MockImageFetcherMockRecorder {
 }
 
 // Fetch mocks base method
-func (m *MockImageFetcher) Fetch(arg0 context.Context, arg1 string, arg2, arg3 bool) (imgutil.Image, error) {
+func (m *MockImageFetcher) Fetch(arg0 context.Context, arg1 string, arg2 bool, arg3 image.PullPolicy) (imgutil.Image, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Fetch", arg0, arg1, arg2, arg3)
 	ret0, _ := ret[0].(imgutil.Image)
@@ -49,3 +51,33 @@ func (mr *MockImageFetcherMockRecorder) Fetch(arg0, arg1, arg2, arg3 interface{}
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Fetch", reflect.TypeOf((*MockImageFetcher)(nil).Fetch), arg0, arg1, arg2, arg3)
 }
+
+// ID mocks base method
+func (m *MockImageFetcher) ID(arg0 context.Context, arg1 string, arg2 bool) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ID", arg0, arg1, arg2)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ID indicates an expected call of ID
+func (mr *MockImageFetcherMockRecorder) ID(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ID", reflect.TypeOf((*MockImageFetcher)(nil).ID), arg0, arg1, arg2)
+}
+
+// Layers mocks base method
+func (m *MockImageFetcher) Layers(arg0 context.Context, arg1 string, arg2 bool) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Layers", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Layers indicates an expected call of Layers
+func (mr *MockImageFetcherMockRecorder) Layers(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Layers", reflect.TypeOf((*MockImageFetcher)(nil).Layers), arg0, arg1, arg2)
+}